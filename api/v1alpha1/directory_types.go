@@ -19,8 +19,10 @@ package v1alpha1
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -29,13 +31,58 @@ const (
 	DirectoryAvailableCondition = "Available"
 	// directoryDegradedCondition represents the status of a directory while resources are being deleted
 	DirectoryDegradedCondition = "Degraded"
+
+	// ReplicationHealthyCondition is true while every instance is within the allowed lag of the newest changes
+	ReplicationHealthyCondition = "ReplicationHealthy"
+	// IndexingCondition is true while an instance builds indexes added to a database
+	IndexingCondition = "Indexing"
+	// ScaleDownBlockedCondition is true while a scale-down is held back because it would remove the provider or
+	// out of sync members
+	ScaleDownBlockedCondition = "ScaleDownBlocked"
+	// ForceScaleDownAnnotation allows a scale-down to proceed when set to "true". Scale-downs are otherwise held back
+	// while they would remove the provider of a provider/consumer topology, or a multi-provider member which the
+	// last replication check found out of sync as it may hold changes the remaining members lack
+	ForceScaleDownAnnotation = "openldap.my.domain/force-scale-down"
+
+	// CutOverAnnotation stops replication from the external provider and promotes the directory to a
+	// standalone provider when set to "true"
+	CutOverAnnotation = "openldap.my.domain/cut-over"
+
+	// RebuildAnnotation rebuilds every index of the instances of a directory one at a time whenever its value
+	// changes. Instances with persistent storage are stopped while a slapindex job runs against their volume.
+	// Otherwise instances of a replicated directory are recreated, reloading each from the other instances
+	RebuildAnnotation = "openldap.my.domain/rebuild"
+
+	// PromoteAnnotation promotes the instance with the given ordinal to provider of a provider/consumer
+	// directory whenever its value changes
+	PromoteAnnotation = "openldap.my.domain/promote"
+
+	// DataVolumeName is the name of the volume claim template of the persistent storage of each instance
+	DataVolumeName = "slapd-data"
+
+	// RoleLabel is set on directory pods to the replication role of the instance
+	RoleLabel = "openldap.my.domain/role"
+	// RoleProvider marks instances which accept writes
+	RoleProvider = "provider"
+	// RoleConsumer marks read-only instances
+	RoleConsumer = "consumer"
 )
 
 // DirectorySpec defines the desired state of Directory.
+// +kubebuilder:validation:XValidation:rule="has(self.storage) == has(oldSelf.storage)",message="storage can't be added or removed"
 type DirectorySpec struct {
 	// Image to use for slapd container
 	// +kubebuilder:validation:Optional
 	Image string `json:"image,omitempty"`
+	// Number of slapd instances to run. Instances replicate from each other when more than one is run
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:default:=1
+	Replicas int32 `json:"replicas,omitempty"`
+	// Replication between the instances of the directory
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={}
+	Replication *ReplicationSpec `json:"replication,omitempty"`
 	// Configuration for slapd daemon
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:={}
@@ -44,6 +91,126 @@ type DirectorySpec struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:={}
 	Service *DirectoryServiceSpec `json:"service,omitempty"`
+	// TLS configuration for the directory
+	// +kubebuilder:validation:Optional
+	TLS *DirectoryTLSSpec `json:"tls,omitempty"`
+	// Initial content to load into the directory
+	// +kubebuilder:validation:Optional
+	Bootstrap *BootstrapSpec `json:"bootstrap,omitempty"`
+	// Publish a servicebinding.io compatible secret workloads can bind to
+	// +kubebuilder:validation:Optional
+	ServiceBinding *ServiceBindingSpec `json:"serviceBinding,omitempty"`
+	// Persistent storage of the config and databases of each instance. Instances keep them on emptyDir
+	// volumes unless set. Can't be changed once the directory is created
+	// +kubebuilder:validation:Optional
+	Storage *StorageSpec `json:"storage,omitempty"`
+	// Compute resources of the slapd container. The Auto tuning profile is derived from the requests
+	// +kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Suggest indexes from the searches slapd logs as unindexed or slow
+	// +kubebuilder:validation:Optional
+	IndexAdvisor *IndexAdvisorSpec `json:"indexAdvisor,omitempty"`
+	// Log level and output format of slapd
+	// +kubebuilder:validation:Optional
+	Logging *LoggingSpec `json:"logging,omitempty"`
+	// Ranges uidNumbers and gidNumbers are allocated from for POSIX users and groups which don't set them
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={}
+	IDAllocation *IDAllocationSpec `json:"idAllocation,omitempty"`
+}
+
+// Spec of the persistent volume claimed for each instance
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="storage is immutable"
+type StorageSpec struct {
+	// Size of the volume
+	// +kubebuilder:validation:Required
+	Size resource.Quantity `json:"size"`
+	// Storage class of the volume. The default storage class is used if unset
+	// +kubebuilder:validation:Optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+}
+
+// Spec of the ranges POSIX ids are allocated from. Allocated ids are never reused, even after the user or group
+// holding them is deleted
+type IDAllocationSpec struct {
+	// Range uidNumbers of users are allocated from
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={start:10000,end:59999}
+	UIDs IDRange `json:"uids,omitempty"`
+	// Range gidNumbers of groups and users without a gidNumber are allocated from
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={start:10000,end:59999}
+	GIDs IDRange `json:"gids,omitempty"`
+}
+
+// Inclusive range of POSIX ids
+// +kubebuilder:validation:XValidation:rule="self.start <= self.end",message="start must not be greater than end"
+type IDRange struct {
+	// First id of the range
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum:=1
+	Start int64 `json:"start"`
+	// Last id of the range
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum:=1
+	End int64 `json:"end"`
+}
+
+var defaultIDRange = IDRange{Start: 10000, End: 59999}
+
+// Contains returns whether id is within the range
+func (r IDRange) Contains(id int64) bool {
+	return id >= r.Start && id <= r.End
+}
+
+// Type to represent a slapd log subsystem
+// +kubebuilder:validation:Enum:=none;trace;packets;args;conns;BER;filter;config;ACL;stats;stats2;shell;parse;sync;any
+type LogLevel string
+
+// Type to represent the format logs are written in
+// +kubebuilder:validation:Enum:=Text;JSON
+type LogFormat string
+
+const (
+	// LogFormatText leaves slapd writing free-text logs to stderr
+	LogFormatText LogFormat = "Text"
+	// LogFormatJSON adds a sidecar which parses the slapd log into one JSON object per line on its stdout
+	LogFormatJSON LogFormat = "JSON"
+)
+
+// Spec of slapd logging
+type LoggingSpec struct {
+	// Subsystems slapd logs. Refers to olcLogLevel. The levels replace those slapd logs at, including the level
+	// set by the image, which is only kept while logging isn't configured
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={"stats"}
+	Level []LogLevel `json:"level,omitempty"`
+	// Format logs are shipped in. slapd keeps logging free text to stderr in either format
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Text
+	Format LogFormat `json:"format,omitempty"`
+	// Image of the log shipping and audit sidecars. Defaults to the image of the operator
+	// +kubebuilder:validation:Optional
+	ShipperImage string `json:"shipperImage,omitempty"`
+}
+
+// Spec of the index advisor, which reads the logs of the slapd instances for unindexed and slow searches.
+// Slow searches are only logged at the stats log level
+type IndexAdvisorSpec struct {
+	// Duration after which a search is considered slow
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="1s"
+	SlowThreshold *metav1.Duration `json:"slowThreshold,omitempty"`
+	// Maximum number of suggestions reported with an IndexSuggested event, most frequent first. The status keeps
+	// every suggestion so their counts carry on accumulating
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:default:=20
+	MaxSuggestions int32 `json:"maxSuggestions,omitempty"`
+	// Attributes the search metrics are labelled with, alongside those the database indexes. Searches filtering
+	// on any other attribute are counted under the attribute label "other"
+	// +kubebuilder:validation:Optional
+	Attributes []string `json:"attributes,omitempty"`
 }
 
 // SlapdConfigSpec defines the desired configuration of the slapd daemon
@@ -63,19 +230,107 @@ type SlapdConfigSpec struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:={}
 	ConfigDatabase *ConfigDatabaseConfig `json:"configDatabase,omitempty"`
+	// Data databases to create. Each database is created as an olcMdbConfig database
+	// +kubebuilder:validation:Optional
+	// +listType:=map
+	// +listMapKey:=name
+	Databases []DatabaseSpec `json:"databases,omitempty"`
+	// Sizing and tuning of the slapd daemon and the frontend limits
+	// +kubebuilder:validation:Optional
+	Tuning *TuningSpec `json:"tuning,omitempty"`
+}
+
+// Type to represent a tuning profile
+// +kubebuilder:validation:Enum:=None;Auto
+type TuningProfile string
+
+const (
+	// TuningProfileNone leaves settings which aren't set to the slapd defaults
+	TuningProfileNone TuningProfile = "None"
+	// TuningProfileAuto derives settings which aren't set from the resource requests of the slapd container, and the
+	// maximum size of the databases from spec.storage when set
+	TuningProfileAuto TuningProfile = "Auto"
+)
+
+// Sizing and tuning of the slapd daemon. Settings which are set take precedence over the profile
+type TuningSpec struct {
+	// Profile deriving defaults for settings which aren't set
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=None
+	Profile TuningProfile `json:"profile,omitempty"`
+	// Size of the worker thread pool. Refers to olcThreads
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=2
+	// +kubebuilder:validation:Maximum:=1024
+	Threads *int32 `json:"threads,omitempty"`
+	// Number of threads used by slapindex and other tools. Refers to olcToolThreads
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	ToolThreads *int32 `json:"toolThreads,omitempty"`
+	// Seconds after which idle client connections are closed, 0 to never close them. Refers to olcIdleTimeout
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	IdleTimeout *int32 `json:"idleTimeout,omitempty"`
+	// Maximum number of pending requests of an anonymous connection. Refers to olcConnMaxPending
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	ConnMaxPending *int32 `json:"connMaxPending,omitempty"`
+	// Maximum number of entries returned by a search, -1 for no limit. Refers to olcSizeLimit of the frontend
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=-1
+	SizeLimit *int32 `json:"sizeLimit,omitempty"`
+	// Maximum number of seconds spent on a search, -1 for no limit. Refers to olcTimeLimit of the frontend
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=-1
+	TimeLimit *int32 `json:"timeLimit,omitempty"`
 }
 
 // Type to represent a valid LDAP schema
-// +kubebuilder:validation:Enum:=collective;cobra;core;cosine;dsee;duaconf;dyngroup;inetorgperson;java;misc;msuser;namedobject;nis;openldap;pmi
+// +kubebuilder:validation:Enum:=collective;cobra;core;cosine;dsee;duaconf;dyngroup;inetorgperson;java;misc;msuser;namedobject;nis;openldap;pmi;openssh-lpk;sudo
 type Schema string
 
+const (
+	// SchemaOpenSSHLPK holds the sshPublicKey attribute of users, used to look up SSH keys with AuthorizedKeysCommand
+	SchemaOpenSSHLPK Schema = "openssh-lpk"
+	// SchemaSudo holds the sudoRole entries hosts read sudoers from
+	SchemaSudo Schema = "sudo"
+)
+
 func (s Schema) String() string {
 	return string(s)
 }
 
+// Custom returns whether the schema is loaded into cn=schema,cn=config by the operator rather than shipped with
+// the slapd image
+func (s Schema) Custom() bool {
+	return s == SchemaOpenSSHLPK || s == SchemaSudo
+}
+
 // Type to represent a list of LDAP schemas
 type SchemaList []Schema
 
+// Bundled returns the schemas shipped with the slapd image
+func (sl SchemaList) Bundled() SchemaList {
+	bundled := SchemaList{}
+	for _, schema := range sl {
+		if !schema.Custom() {
+			bundled = append(bundled, schema)
+		}
+	}
+	return bundled
+}
+
+// Custom returns the schemas loaded by the operator
+func (sl SchemaList) Custom() SchemaList {
+	custom := SchemaList{}
+	for _, schema := range sl {
+		if schema.Custom() {
+			custom = append(custom, schema)
+		}
+	}
+	return custom
+}
+
 func (sl SchemaList) Join() string {
 	switch len(sl) {
 	case 0:
@@ -113,6 +368,251 @@ type ConfigDatabaseConfig struct {
 	Access []string `json:"access,omitempty"`
 }
 
+// Data database specific config
+type DatabaseSpec struct {
+	// Name of the database. Used to name the volume holding the database files
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength:=60
+	// +kubebuilder:validation:Pattern:=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Suffix of the database, e.g. dc=example,dc=com
+	// +kubebuilder:validation:Required
+	Suffix string `json:"suffix"`
+	// Access controls for database
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={"to * by * read"}
+	Access []string `json:"access,omitempty"`
+	// Overlays to configure on the database
+	// +kubebuilder:validation:Optional
+	Overlays *DatabaseOverlaysSpec `json:"overlays,omitempty"`
+	// Sizing and tuning of the MDB environment. Settings which aren't set are derived from the tuning profile
+	// +kubebuilder:validation:Optional
+	Tuning *DatabaseTuningSpec `json:"tuning,omitempty"`
+	// Indexes maintained for the database. Refers to olcDbIndex. Indexes added to an existing database are
+	// built online in the background
+	// +kubebuilder:validation:Optional
+	Indexes []IndexSpec `json:"indexes,omitempty"`
+	// Record every write to the database with the auditlog overlay. The audit log is shipped as JSON by a sidecar
+	// +kubebuilder:validation:Optional
+	Audit *AuditSpec `json:"audit,omitempty"`
+	// Name of the organizational unit below the suffix holding the sudoRole entries of SudoRules
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=SUDOers
+	// +kubebuilder:validation:Pattern:=`^[A-Za-z0-9_-]+$`
+	SudoersOU string `json:"sudoersOU,omitempty"`
+}
+
+// Spec of the audit log of a database
+type AuditSpec struct {
+	// Size at which the audit log is rotated
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="10Mi"
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// Number of rotated audit logs kept alongside the current one
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:default:=3
+	MaxFiles int32 `json:"maxFiles,omitempty"`
+	// Attributes whose values are redacted from shipped audit records. Password attributes such as
+	// userPassword are always redacted
+	// +kubebuilder:validation:Optional
+	RedactAttributes []string `json:"redactAttributes,omitempty"`
+}
+
+// Type to represent a kind of index
+// +kubebuilder:validation:Enum:=pres;eq;approx;sub;subinitial;subany;subfinal;nolang;nosubtypes
+type IndexType string
+
+// Index of one or more attributes of a database
+type IndexSpec struct {
+	// Attributes to index, e.g. uid
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems:=1
+	Attributes []string `json:"attributes"`
+	// Kinds of index to maintain
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems:=1
+	Types []IndexType `json:"types"`
+}
+
+// Type to represent an MDB environment flag
+// +kubebuilder:validation:Enum:=nosync;nometasync;writemap;mapasync;nordahead
+type MdbEnvFlag string
+
+// Sizing and tuning of an MDB database
+type DatabaseTuningSpec struct {
+	// Maximum size of the database. Refers to olcDbMaxSize
+	// +kubebuilder:validation:Optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// Flags of the MDB environment. Refers to olcDbEnvFlags
+	// +kubebuilder:validation:Optional
+	EnvFlags []MdbEnvFlag `json:"envFlags,omitempty"`
+	// How often the database is checkpointed. Refers to olcDbCheckpoint
+	// +kubebuilder:validation:Optional
+	Checkpoint *MdbCheckpointSpec `json:"checkpoint,omitempty"`
+}
+
+// Interval at which an MDB database is checkpointed, whichever is reached first
+type MdbCheckpointSpec struct {
+	// Kilobytes written since the last checkpoint
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum:=0
+	KBytes int32 `json:"kbytes"`
+	// Minutes since the last checkpoint
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum:=0
+	Minutes int32 `json:"minutes"`
+}
+
+// Overlays configured on a data database. An overlay is enabled when its spec is set
+type DatabaseOverlaysSpec struct {
+	// Maintain memberOf attributes on the members of groups. Refers to the memberof overlay
+	// +kubebuilder:validation:Optional
+	MemberOf *MemberOfOverlaySpec `json:"memberOf,omitempty"`
+	// Remove references to entries which are deleted or renamed. Refers to the refint overlay
+	// +kubebuilder:validation:Optional
+	RefInt *RefIntOverlaySpec `json:"refInt,omitempty"`
+}
+
+// Type to represent how the memberof overlay treats members which don't exist
+// +kubebuilder:validation:Enum:=ignore;drop;error
+type MemberOfDangling string
+
+const (
+	// MemberOfDanglingIgnore keeps references to missing entries
+	MemberOfDanglingIgnore MemberOfDangling = "ignore"
+	// MemberOfDanglingDrop silently removes references to missing entries
+	MemberOfDanglingDrop MemberOfDangling = "drop"
+	// MemberOfDanglingError rejects modifications referencing missing entries
+	MemberOfDanglingError MemberOfDangling = "error"
+)
+
+// Config of the memberof overlay
+type MemberOfOverlaySpec struct {
+	// Object class of group entries. Refers to olcMemberOfGroupOC
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=groupOfNames
+	GroupObjectClass string `json:"groupObjectClass,omitempty"`
+	// Attribute of groups listing their members. Refers to olcMemberOfMemberAD
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=member
+	MemberAttribute string `json:"memberAttribute,omitempty"`
+	// Attribute of members listing their groups. Refers to olcMemberOfMemberOfAD
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=memberOf
+	MemberOfAttribute string `json:"memberOfAttribute,omitempty"`
+	// Behaviour when a group references an entry which doesn't exist. Refers to olcMemberOfDangling
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=ignore
+	Dangling MemberOfDangling `json:"dangling,omitempty"`
+	// Keep groups and members consistent when entries are renamed or deleted. Refers to olcMemberOfRefInt
+	// +kubebuilder:validation:Optional
+	RefInt bool `json:"refInt,omitempty"`
+}
+
+// Config of the refint overlay
+type RefIntOverlaySpec struct {
+	// Attributes whose values are updated when the entry they reference is renamed or deleted.
+	// Refers to olcRefintAttribute
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinItems:=1
+	// +kubebuilder:default:={"member"}
+	Attributes []string `json:"attributes,omitempty"`
+	// DN recorded as the modifiersName of entries updated by the overlay. Refers to olcRefintModifiersName
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="cn=Referential Integrity Overlay"
+	ModifiersName string `json:"modifiersName,omitempty"`
+}
+
+// RootDN returns the DN of the operator managed administrator of the database
+func (db *DatabaseSpec) RootDN() string {
+	return fmt.Sprintf("cn=admin,%s", db.Suffix)
+}
+
+// ServicesDN returns the DN of the organizational unit holding service accounts
+func (db *DatabaseSpec) ServicesDN() string {
+	return fmt.Sprintf("ou=services,%s", db.Suffix)
+}
+
+// Sudoers returns the name of the organizational unit holding sudoRole entries
+func (db *DatabaseSpec) Sudoers() string {
+	if db.SudoersOU != "" {
+		return db.SudoersOU
+	}
+	return "SUDOers"
+}
+
+// SudoersDN returns the DN of the organizational unit holding sudoRole entries
+func (db *DatabaseSpec) SudoersDN() string {
+	return fmt.Sprintf("ou=%s,%s", db.Sudoers(), db.Suffix)
+}
+
+// PeopleDN returns the DN of the organizational unit holding user entries
+func (db *DatabaseSpec) PeopleDN() string {
+	return fmt.Sprintf("ou=people,%s", db.Suffix)
+}
+
+// GroupsDN returns the DN of the organizational unit holding group entries
+func (db *DatabaseSpec) GroupsDN() string {
+	return fmt.Sprintf("ou=groups,%s", db.Suffix)
+}
+
+// AccessLogSuffix returns the suffix of the accesslog database recording changes to the database
+func (db *DatabaseSpec) AccessLogSuffix() string {
+	return fmt.Sprintf("cn=accesslog-%s", db.Name)
+}
+
+// Spec of initial content to load into the directory
+type BootstrapSpec struct {
+	// LDIF to load into a database after it has been created
+	// +kubebuilder:validation:Optional
+	Seed *SeedSpec `json:"seed,omitempty"`
+}
+
+// Type to represent a predefined directory information tree
+// +kubebuilder:validation:Enum:=None;Standard
+type DITPreset string
+
+const (
+	// DITPresetNone doesn't create any entries
+	DITPresetNone DITPreset = "None"
+	// DITPresetStandard creates the suffix entry along with ou=people, ou=groups and ou=services
+	DITPresetStandard DITPreset = "Standard"
+)
+
+// Spec of seed data loaded into a database
+type SeedSpec struct {
+	// Name of the database to load seed data into. Defaults to the first database
+	// +kubebuilder:validation:Optional
+	Database string `json:"database,omitempty"`
+	// Predefined tree to create before loading sources
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=None
+	Preset DITPreset `json:"preset,omitempty"`
+	// LDIF sources to load. Sources are loaded in order and each revision of a source is only loaded once
+	// +kubebuilder:validation:Optional
+	// +listType:=map
+	// +listMapKey:=name
+	Sources []SeedSource `json:"sources,omitempty"`
+}
+
+// Source of LDIF to load into a database
+type SeedSource struct {
+	// Name used to track whether the source has been loaded
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// ConfigMap key containing LDIF
+	// +kubebuilder:validation:Optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// Secret key containing LDIF
+	// +kubebuilder:validation:Optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// Render the LDIF as a Go template before loading. Templates can refer to {{ .Suffix }} and {{ .RootDN }}
+	// and generate passwords with {{ password "name" }}, which are stored in the seed password secret
+	// +kubebuilder:validation:Optional
+	Template bool `json:"template,omitempty"`
+}
+
 // Spec of desired service to create for OpenLDAP instance
 type DirectoryServiceSpec struct {
 	// Type of service to create. Defaults to ClusterIP
@@ -122,14 +622,440 @@ type DirectoryServiceSpec struct {
 	Type corev1.ServiceType `json:"type,omitempty"`
 }
 
+// Spec of the certificate presented by slapd
+type DirectoryTLSSpec struct {
+	// Name of a kubernetes.io/tls secret containing tls.crt, tls.key and ca.crt
+	// +kubebuilder:validation:Required
+	SecretName string `json:"secretName"`
+}
+
+// Type to represent how instances of a directory replicate
+// +kubebuilder:validation:Enum:=MultiProvider;ProviderConsumer
+type ReplicationTopology string
+
+const (
+	// ReplicationTopologyMultiProvider accepts writes on every instance and replicates between all of them
+	ReplicationTopologyMultiProvider ReplicationTopology = "MultiProvider"
+	// ReplicationTopologyProviderConsumer accepts writes on the first instance, which the others replicate from
+	ReplicationTopologyProviderConsumer ReplicationTopology = "ProviderConsumer"
+)
+
+// Type to represent the syncrepl protocol variant used to replicate
+// +kubebuilder:validation:Enum:=Syncrepl;DeltaSyncrepl
+type ReplicationMode string
+
+const (
+	// ReplicationModeSyncrepl replicates complete entries
+	ReplicationModeSyncrepl ReplicationMode = "Syncrepl"
+	// ReplicationModeDeltaSyncrepl replicates the changes recorded in an accesslog database
+	ReplicationModeDeltaSyncrepl ReplicationMode = "DeltaSyncrepl"
+)
+
+// Spec of replication between the instances of a directory
+type ReplicationSpec struct {
+	// Which instances accept writes
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=MultiProvider
+	Topology ReplicationTopology `json:"topology,omitempty"`
+	// Protocol variant used to replicate
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Syncrepl
+	Mode ReplicationMode `json:"mode,omitempty"`
+	// Ordinal of the instance accepting writes in the ProviderConsumer topology. Changing it fails over to
+	// the given instance. Defaults to the first instance
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	Provider *int32 `json:"provider,omitempty"`
+	// Accesslog databases provisioned for each data database in DeltaSyncrepl mode
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={}
+	AccessLog *AccessLogSpec `json:"accessLog,omitempty"`
+	// Lag behind the newest changes after which replication is reported unhealthy
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="1m"
+	MaxLag *metav1.Duration `json:"maxLag,omitempty"`
+	// LDAP server outside the cluster a database replicates from until it is cut over
+	// +kubebuilder:validation:Optional
+	External *ExternalProviderSpec `json:"external,omitempty"`
+	// Providers outside the cluster, e.g. in another region, which form one multi-provider directory with
+	// the providers of this directory
+	// +kubebuilder:validation:Optional
+	// +listType:=map
+	// +listMapKey:=name
+	Peers []ReplicationPeerSpec `json:"peers,omitempty"`
+	// Server IDs allocated to the instances of this directory. Each region running the operator must use a
+	// disjoint range, and the server IDs of peers must lie outside it. Defaults to 1-999. Changing the range
+	// only affects instances allocated a server ID afterwards
+	// +kubebuilder:validation:Optional
+	ServerIDs *ServerIDRange `json:"serverIDs,omitempty"`
+}
+
+// Range of server IDs, both ends inclusive
+// +kubebuilder:validation:XValidation:rule="self.start <= self.end",message="start must not be greater than end"
+type ServerIDRange struct {
+	// First server ID of the range
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=999
+	Start int32 `json:"start"`
+	// Last server ID of the range
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=999
+	End int32 `json:"end"`
+}
+
+// Contains returns whether id lies within the range
+func (r ServerIDRange) Contains(id int32) bool {
+	return id >= r.Start && id <= r.End
+}
+
+// Spec of a provider outside the cluster taking part in multi-provider replication
+type ReplicationPeerSpec struct {
+	// Name of the peer
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength:=50
+	// +kubebuilder:validation:Pattern:=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// URI of the peer, e.g. ldaps://ldap.eu-west.example.com
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:=`^ldaps?://`
+	URI string `json:"uri"`
+	// Server ID of the peer. Must be unique across the directory and is never allocated to local instances
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=999
+	ServerID int32 `json:"serverID"`
+	// Name of the database replicated with the peer. Defaults to the first database
+	// +kubebuilder:validation:Optional
+	Database string `json:"database,omitempty"`
+	// DN to bind to the peer as
+	// +kubebuilder:validation:Required
+	BindDN string `json:"bindDN"`
+	// Secret key holding the password of the bind DN
+	// +kubebuilder:validation:Required
+	PasswordSecretRef corev1.SecretKeySelector `json:"passwordSecretRef"`
+	// Secret key holding the CA certificate the peer's certificate is verified against
+	// +kubebuilder:validation:Optional
+	CASecretRef *corev1.SecretKeySelector `json:"caSecretRef,omitempty"`
+	// Upgrade ldap:// connections with StartTLS
+	// +kubebuilder:validation:Optional
+	StartTLS bool `json:"startTLS,omitempty"`
+}
+
+// Spec of an LDAP server outside the cluster replicated from using syncrepl
+type ExternalProviderSpec struct {
+	// URI of the provider, e.g. ldaps://ldap.example.com
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:=`^ldaps?://`
+	URI string `json:"uri"`
+	// Name of the database replicated into. Defaults to the first database
+	// +kubebuilder:validation:Optional
+	Database string `json:"database,omitempty"`
+	// Base of the replicated subtree. Defaults to the suffix of the database
+	// +kubebuilder:validation:Optional
+	SearchBase string `json:"searchBase,omitempty"`
+	// DN to bind to the provider as
+	// +kubebuilder:validation:Required
+	BindDN string `json:"bindDN"`
+	// Secret key holding the password of the bind DN
+	// +kubebuilder:validation:Required
+	PasswordSecretRef corev1.SecretKeySelector `json:"passwordSecretRef"`
+	// Secret key holding the CA certificate the provider's certificate is verified against
+	// +kubebuilder:validation:Optional
+	CASecretRef *corev1.SecretKeySelector `json:"caSecretRef,omitempty"`
+	// Upgrade ldap:// connections with StartTLS
+	// +kubebuilder:validation:Optional
+	StartTLS bool `json:"startTLS,omitempty"`
+	// Syncrepl retry schedule as pairs of interval and count, e.g. "60 10 300 +"
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="60 +"
+	Retry string `json:"retry,omitempty"`
+}
+
+// Spec of the accesslog overlay and database
+type AccessLogSpec struct {
+	// Operations to log. Must include writes for delta-syncrepl. Refers to olcAccessLogOps
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={"writes"}
+	// +kubebuilder:validation:items:Enum:=writes;reads;session;all;add;delete;modify;modrdn;compare;search;bind;unbind;abandon
+	Operations []string `json:"operations,omitempty"`
+	// Only log successful operations. Refers to olcAccessLogSuccess
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=true
+	SuccessOnly *bool `json:"successOnly,omitempty"`
+	// Age after which log entries are purged, in the form [dd+]hh:mm[:ss]. Refers to olcAccessLogPurge
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern:=`^([0-9]+\+)?[0-9]{2}:[0-9]{2}(:[0-9]{2})?$`
+	// +kubebuilder:default:="07+00:00"
+	PurgeAge string `json:"purgeAge,omitempty"`
+	// Interval between purges, in the form [dd+]hh:mm[:ss]. Refers to olcAccessLogPurge
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern:=`^([0-9]+\+)?[0-9]{2}:[0-9]{2}(:[0-9]{2})?$`
+	// +kubebuilder:default:="01+00:00"
+	PurgeInterval string `json:"purgeInterval,omitempty"`
+	// Maximum size of the accesslog database. Refers to olcDbMaxSize
+	// +kubebuilder:validation:Optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+}
+
+// Spec of the servicebinding.io secret published for the directory
+type ServiceBindingSpec struct {
+	// Create the binding secret
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Name of the database whose suffix is published. Defaults to the first database
+	// +kubebuilder:validation:Optional
+	Database string `json:"database,omitempty"`
+	// Access granted to the service account published in the binding secret. The account is managed through an
+	// LdapBinding named after the directory, so bound workloads never receive the root DN
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Read
+	Access BindingAccess `json:"access,omitempty"`
+	// DN of the subtree the service account is granted access to. Defaults to the database suffix
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern:=`^[A-Za-z][A-Za-z0-9-]*=[^"\r\n]+$`
+	Scope string `json:"scope,omitempty"`
+}
+
 // DirectoryStatus defines the observed state of Directory.
 type DirectoryStatus struct {
 	// Slice of conditions storing the condition of the directory
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Seed sources which have been loaded into the directory. Without spec.storage a seed is loaded again once its
+	// entries are lost with the data of the instances
+	AppliedSeeds []AppliedSeed `json:"appliedSeeds,omitempty"`
+	// Details clients need to connect to the directory
+	Connection *ConnectionStatus `json:"connection,omitempty"`
+	// Secret containing servicebinding.io connection details. Follows the Provisioned Service duck type
+	Binding *corev1.LocalObjectReference `json:"binding,omitempty"`
+	// Members of the replication topology
+	Replication *ReplicationStatus `json:"replication,omitempty"`
+	// Progress of replication from an external provider
+	External *ExternalReplicationStatus `json:"external,omitempty"`
+	// Progress of online indexing of each database
+	Indexes []DatabaseIndexStatus `json:"indexes,omitempty"`
+	// Progress of the most recently requested rebuild
+	Rebuild *RebuildStatus `json:"rebuild,omitempty"`
+	// Indexes suggested by the index advisor
+	IndexAdvice *IndexAdviceStatus `json:"indexAdvice,omitempty"`
+}
+
+// Indexes suggested from the logs of the slapd instances
+type IndexAdviceStatus struct {
+	// Time the logs were last read up to
+	LastChecked *metav1.Time `json:"lastChecked,omitempty"`
+	// Time of the last log line read from each pod
+	Positions []LogPosition `json:"positions,omitempty"`
+	// Suggested indexes, most frequently needed first
+	Suggestions []IndexSuggestion `json:"suggestions,omitempty"`
+}
+
+// Position the logs of a pod were read up to
+type LogPosition struct {
+	// Name of the pod
+	Pod string `json:"pod"`
+	// Time of the last line read
+	Time metav1.MicroTime `json:"time"`
+}
+
+// Index suggested for an attribute of a database
+type IndexSuggestion struct {
+	// Name of the database
+	Database string `json:"database"`
+	// Attribute to index
+	Attribute string `json:"attribute"`
+	// Type of index to add
+	Type IndexType `json:"type"`
+	// Number of times slapd reported the attribute as not indexed
+	Unindexed int64 `json:"unindexed,omitempty"`
+	// Number of slow searches filtering on the attribute
+	Slow int64 `json:"slow,omitempty"`
+	// Time the attribute was last found in the logs
+	LastSeen metav1.Time `json:"lastSeen"`
+	// Whether an IndexSuggested event has been emitted for the suggestion
+	Reported bool `json:"reported,omitempty"`
+}
+
+// Observed state of the indexes of a database
+type DatabaseIndexStatus struct {
+	// Name of the database
+	Database string `json:"database"`
+	// olcDbIndex values applied to the database
+	Indexes []string `json:"indexes,omitempty"`
+	// Pods still building indexes of the database
+	Indexing []string `json:"indexing,omitempty"`
+	// Time indexing last completed on every instance
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+}
+
+// Progress of a rebuild of the instances of a directory
+type RebuildStatus struct {
+	// Value of the rebuild annotation acted upon
+	Request string `json:"request,omitempty"`
+	// Ordinals of the instances still to be rebuilt, starting with the instance being rebuilt
+	Remaining []int32 `json:"remaining,omitempty"`
+	// Time the instance being rebuilt was deleted
+	DeletedAt *metav1.Time `json:"deletedAt,omitempty"`
+	// Time the instance being rebuilt was stopped to run slapindex against its volume
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+	// Time the rebuild was requested
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// Time the last instance was rebuilt
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+}
+
+// Type to represent the phase of replication from an external provider
+// +kubebuilder:validation:Enum:=InitialLoad;Replicating;CutOver
+type ExternalReplicationPhase string
+
+const (
+	// ExternalReplicationPhaseInitialLoad is the phase while the initial refresh from the provider is running
+	ExternalReplicationPhaseInitialLoad ExternalReplicationPhase = "InitialLoad"
+	// ExternalReplicationPhaseReplicating is the phase once the initial refresh has completed
+	ExternalReplicationPhaseReplicating ExternalReplicationPhase = "Replicating"
+	// ExternalReplicationPhaseCutOver is the phase once the directory has stopped replicating and accepts writes
+	ExternalReplicationPhaseCutOver ExternalReplicationPhase = "CutOver"
+)
+
+// Observed state of replication from an external provider
+type ExternalReplicationStatus struct {
+	// Phase of replication
+	Phase ExternalReplicationPhase `json:"phase,omitempty"`
+	// Number of entries replicated into the database so far
+	Entries int64 `json:"entries,omitempty"`
+	// Context CSNs of the database, set once the initial refresh has completed
+	ContextCSN []string `json:"contextCSN,omitempty"`
+	// Time the directory was cut over to a standalone provider
+	CutOverAt *metav1.Time `json:"cutOverAt,omitempty"`
+}
+
+// Observed state of replication between the instances of a directory
+type ReplicationStatus struct {
+	// Instances taking part in replication with their allocated server IDs
+	// +listType:=map
+	// +listMapKey:=ordinal
+	Members []ReplicationMember `json:"members,omitempty"`
+	// Last server ID allocated. Server IDs are allocated in sequence so that departed members' IDs aren't reused
+	LastServerID int32 `json:"lastServerID,omitempty"`
+	// Sync state of each instance as of the last check
+	Replicas []ReplicaStatus `json:"replicas,omitempty"`
+	// Time the sync state was last checked
+	LastChecked *metav1.Time `json:"lastChecked,omitempty"`
+	// Connectivity of each peer outside the cluster as of the last check
+	Peers []PeerStatus `json:"peers,omitempty"`
+	// Ordinal of the instance accepting writes in the ProviderConsumer topology
+	Provider *int32 `json:"provider,omitempty"`
+	// Value of spec.replication.provider last acted upon
+	ObservedProvider *int32 `json:"observedProvider,omitempty"`
+	// Value of the promote annotation last acted upon
+	Promotion string `json:"promotion,omitempty"`
+	// Most recent promotion of a consumer to provider
+	LastFailover *FailoverStatus `json:"lastFailover,omitempty"`
+}
+
+// Record of a consumer being promoted to provider
+type FailoverStatus struct {
+	// Ordinal of the previous provider
+	From int32 `json:"from"`
+	// Ordinal of the promoted instance
+	To int32 `json:"to"`
+	// What requested the promotion, either the spec or the promote annotation
+	Trigger string `json:"trigger"`
+	// Time of the promotion
+	Time metav1.Time `json:"time"`
+}
+
+// Connectivity of a peer outside the cluster
+type PeerStatus struct {
+	// Name of the peer
+	Name string `json:"name"`
+	// Whether the operator could bind to the peer with its credentials
+	Reachable bool `json:"reachable"`
+	// Context CSNs of the replicated database on the peer
+	ContextCSN []string `json:"contextCSN,omitempty"`
+	// Error encountered connecting to the peer
+	Message string `json:"message,omitempty"`
+}
+
+// Sync state of an instance
+type ReplicaStatus struct {
+	// Name of the pod running the instance
+	Pod string `json:"pod"`
+	// Replication role of the instance
+	Role string `json:"role,omitempty"`
+	// Whether the instance holds the newest changes of every database
+	InSync bool `json:"inSync"`
+	// Largest lag of a database of the instance behind the newest changes, in seconds
+	LagSeconds int64 `json:"lagSeconds,omitempty"`
+	// Context CSNs of each database
+	Databases []DatabaseSyncStatus `json:"databases,omitempty"`
+	// Error encountered reading the sync state of the instance
+	Message string `json:"message,omitempty"`
+}
+
+// Sync state of a database on an instance
+type DatabaseSyncStatus struct {
+	// Name of the database
+	Name string `json:"name"`
+	// Values of contextCSN on the suffix entry
+	ContextCSN []string `json:"contextCSN,omitempty"`
+	// Lag behind the newest changes, in seconds
+	LagSeconds int64 `json:"lagSeconds,omitempty"`
+}
+
+// Instance taking part in replication
+type ReplicationMember struct {
+	// Ordinal of the statefulset pod running the instance
+	Ordinal int32 `json:"ordinal"`
+	// Value of olcServerID on the instance
+	ServerID int32 `json:"serverID"`
+}
+
+// Connection details of a directory
+type ConnectionStatus struct {
+	// URI of the directory within the cluster
+	URI string `json:"uri,omitempty"`
+	// URI of the instances accepting writes within the cluster
+	WriteURI string `json:"writeURI,omitempty"`
+	// URI of the instances serving searches within the cluster
+	ReadURI string `json:"readURI,omitempty"`
+	// LDAPS URI of the directory within the cluster. Only set when TLS is configured
+	LDAPSURI string `json:"ldapsURI,omitempty"`
+	// URIs of the directory outside the cluster. Only reported for LoadBalancer services once an ingress is
+	// assigned. NodePort services are left out, as the node addresses they're reachable on depend on the cluster
+	ExternalURIs []string `json:"externalURIs,omitempty"`
+	// Suffixes of the data databases
+	Suffixes []string `json:"suffixes,omitempty"`
+	// Root DN of the first database
+	AdminDN string `json:"adminDN,omitempty"`
+	// Root DN of the config database
+	ConfigDN string `json:"configDN,omitempty"`
+	// Secret key holding the password of the root DNs
+	AdminSecretRef *corev1.SecretKeySelector `json:"adminSecretRef,omitempty"`
+	// Secret key holding the CA certificate. Only set when TLS is configured
+	CASecretRef *corev1.SecretKeySelector `json:"caSecretRef,omitempty"`
+}
+
+// Record of seed data loaded into the directory
+type AppliedSeed struct {
+	// Name of the seed source
+	Name string `json:"name"`
+	// Revision of the source content which was loaded
+	Revision string `json:"revision"`
+	// Time the source was loaded
+	AppliedAt metav1.Time `json:"appliedAt"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="URI",type="string",JSONPath=`.status.connection.uri`
+// +kubebuilder:printcolumn:name="Suffix",type="string",JSONPath=`.status.connection.suffixes[0]`
+// +kubebuilder:printcolumn:name="Admin DN",type="string",JSONPath=`.status.connection.adminDN`,priority=1
+// +kubebuilder:printcolumn:name="External URI",type="string",JSONPath=`.status.connection.externalURIs[0]`,priority=1
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=`.spec.replicas`
+// +kubebuilder:printcolumn:name="Provider",type="integer",JSONPath=`.status.replication.provider`,priority=1
+// +kubebuilder:printcolumn:name="Replication Healthy",type="string",JSONPath=`.status.conditions[?(@.type=="ReplicationHealthy")].status`,priority=1
 // +kubebuilder:printcolumn:name="Available",type="string",JSONPath=`.status.conditions[?(@.type=="Available")].status`
 // +kubebuilder:printcolumn:name="Age", type="date",JSONPath=`.metadata.creationTimestamp`
 // Directory is the Schema for the directories API.
@@ -145,14 +1071,213 @@ func (directory *Directory) SecretName() string {
 	return fmt.Sprintf("%s-cn-config", directory.Name)
 }
 
+func (directory *Directory) SeedSecretName() string {
+	return fmt.Sprintf("%s-seed-passwords", directory.Name)
+}
+
+// IDAllocationConfigMapName returns the name of the ConfigMap recording the next free POSIX ids
+func (directory *Directory) IDAllocationConfigMapName() string {
+	return fmt.Sprintf("%s-id-allocation", directory.Name)
+}
+
+// IDRanges returns the ranges POSIX ids are allocated from
+func (directory *Directory) IDRanges() IDAllocationSpec {
+	ranges := IDAllocationSpec{UIDs: defaultIDRange, GIDs: defaultIDRange}
+	if spec := directory.Spec.IDAllocation; spec != nil {
+		if spec.UIDs.Start > 0 {
+			ranges.UIDs = spec.UIDs
+		}
+		if spec.GIDs.Start > 0 {
+			ranges.GIDs = spec.GIDs
+		}
+	}
+	return ranges
+}
+
+func (directory *Directory) BindingSecretName() string {
+	return fmt.Sprintf("%s-binding", directory.Name)
+}
+
+// ServiceBindingName returns the name of the LdapBinding whose service account is published in the binding secret
+func (directory *Directory) ServiceBindingName() string {
+	return fmt.Sprintf("%s-servicebinding", directory.Name)
+}
+
 func (directory *Directory) ServiceName() string {
 	return directory.Name
 }
 
+func (directory *Directory) WriteServiceName() string {
+	return fmt.Sprintf("%s-write", directory.Name)
+}
+
+func (directory *Directory) ReadServiceName() string {
+	return fmt.Sprintf("%s-read", directory.Name)
+}
+
+func (directory *Directory) HeadlessServiceName() string {
+	return fmt.Sprintf("%s-headless", directory.Name)
+}
+
 func (directory *Directory) StatefulSetName() string {
 	return fmt.Sprintf("%s-slapd", directory.Name)
 }
 
+// DataClaimName returns the name of the persistent volume claim of the instance with the given ordinal
+func (directory *Directory) DataClaimName(ordinal int32) string {
+	return fmt.Sprintf("%s-%s-%d", DataVolumeName, directory.StatefulSetName(), ordinal)
+}
+
+// SlapindexJobName returns the name of the job rebuilding the indexes of the instance with the given ordinal
+func (directory *Directory) SlapindexJobName(ordinal int32) string {
+	return fmt.Sprintf("%s-slapindex-%d", directory.StatefulSetName(), ordinal)
+}
+
+// Database returns the database with the given name or the first database if name is empty.
+// Returns nil if no matching database exists
+func (directory *Directory) Database(name string) *DatabaseSpec {
+	if directory.Spec.SlapdConfig == nil {
+		return nil
+	}
+	for i := range directory.Spec.SlapdConfig.Databases {
+		if name == "" || directory.Spec.SlapdConfig.Databases[i].Name == name {
+			return &directory.Spec.SlapdConfig.Databases[i]
+		}
+	}
+	return nil
+}
+
+// Replicated returns true if the directory runs more than one instance, or still has more than one
+// replication member while scaling down
+func (directory *Directory) Replicated() bool {
+	return directory.Spec.Replicas > 1 || len(directory.Members()) > 1
+}
+
+// JSONLogging returns true if slapd logs are shipped as JSON by a sidecar
+func (directory *Directory) JSONLogging() bool {
+	return directory.Spec.Logging != nil && directory.Spec.Logging.Format == LogFormatJSON
+}
+
+// ShipperImage returns the image of the log shipping and audit sidecars
+func (directory *Directory) ShipperImage() string {
+	if directory.Spec.Logging == nil {
+		return ""
+	}
+	return directory.Spec.Logging.ShipperImage
+}
+
+// AuditedDatabases returns the databases with an audit log
+func (directory *Directory) AuditedDatabases() []DatabaseSpec {
+	databases := []DatabaseSpec{}
+	if directory.Spec.SlapdConfig == nil {
+		return databases
+	}
+	for _, db := range directory.Spec.SlapdConfig.Databases {
+		if db.Audit != nil {
+			databases = append(databases, db)
+		}
+	}
+	return databases
+}
+
+// MaxReplicationLag returns the lag after which replication is reported unhealthy, defaulting to a minute
+func (directory *Directory) MaxReplicationLag() time.Duration {
+	if directory.Spec.Replication == nil || directory.Spec.Replication.MaxLag == nil {
+		return time.Minute
+	}
+	return directory.Spec.Replication.MaxLag.Duration
+}
+
+// ExternalProvider returns the external provider the directory replicates from, or nil if it doesn't or has
+// been cut over
+func (directory *Directory) ExternalProvider() *ExternalProviderSpec {
+	if directory.Spec.Replication == nil || directory.Spec.Replication.External == nil {
+		return nil
+	}
+	if directory.Status.External != nil && directory.Status.External.Phase == ExternalReplicationPhaseCutOver {
+		return nil
+	}
+	return directory.Spec.Replication.External
+}
+
+// ReplicationPeers returns the providers outside the cluster the directory replicates with
+func (directory *Directory) ReplicationPeers() []ReplicationPeerSpec {
+	if directory.Spec.Replication == nil {
+		return nil
+	}
+	return directory.Spec.Replication.Peers
+}
+
+// Members returns the replication members recorded in the status
+func (directory *Directory) Members() []ReplicationMember {
+	if directory.Status.Replication == nil {
+		return nil
+	}
+	return directory.Status.Replication.Members
+}
+
+// ServerIDRange returns the range of server IDs allocated to the instances of the directory
+func (directory *Directory) ServerIDRange() ServerIDRange {
+	if directory.Spec.Replication != nil && directory.Spec.Replication.ServerIDs != nil {
+		return *directory.Spec.Replication.ServerIDs
+	}
+	return ServerIDRange{Start: 1, End: 999}
+}
+
+// ServerID returns the server ID allocated to the instance with the given ordinal, or 0 if none is allocated
+func (directory *Directory) ServerID(ordinal int) int32 {
+	for _, member := range directory.Members() {
+		if int(member.Ordinal) == ordinal {
+			return member.ServerID
+		}
+	}
+	return 0
+}
+
+// ReplicationTopology returns the replication topology, defaulting to MultiProvider
+func (directory *Directory) ReplicationTopology() ReplicationTopology {
+	if directory.Spec.Replication == nil || directory.Spec.Replication.Topology == "" {
+		return ReplicationTopologyMultiProvider
+	}
+	return directory.Spec.Replication.Topology
+}
+
+// ReplicationMode returns the replication mode, defaulting to Syncrepl
+func (directory *Directory) ReplicationMode() ReplicationMode {
+	if directory.Spec.Replication == nil || directory.Spec.Replication.Mode == "" {
+		return ReplicationModeSyncrepl
+	}
+	return directory.Spec.Replication.Mode
+}
+
+// ProviderOrdinal returns the ordinal of the instance accepting writes in a provider/consumer topology
+func (directory *Directory) ProviderOrdinal() int32 {
+	if directory.Status.Replication == nil || directory.Status.Replication.Provider == nil {
+		return 0
+	}
+	return *directory.Status.Replication.Provider
+}
+
+// PodRole returns the replication role of the instance with the given ordinal. Every instance is a provider
+// unless the directory uses a provider/consumer topology, in which case only the instance last promoted is
+func (directory *Directory) PodRole(ordinal int) string {
+	if directory.Replicated() && directory.ReplicationTopology() == ReplicationTopologyProviderConsumer &&
+		ordinal != int(directory.ProviderOrdinal()) {
+		return RoleConsumer
+	}
+	return RoleProvider
+}
+
+// SeedApplied returns true if the given revision of a seed source has been loaded
+func (directory *Directory) SeedApplied(name, revision string) bool {
+	for _, seed := range directory.Status.AppliedSeeds {
+		if seed.Name == name && seed.Revision == revision {
+			return true
+		}
+	}
+	return false
+}
+
 // +kubebuilder:object:root=true
 
 // DirectoryList contains a list of Directory.
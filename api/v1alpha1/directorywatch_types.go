@@ -0,0 +1,186 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DirectoryWatchReadyCondition represents whether changes are being delivered to the sink
+	DirectoryWatchReadyCondition = "Ready"
+)
+
+// Type to represent a kind of change delivered by a watch
+// +kubebuilder:validation:Enum:=Add;Modify;Delete
+type WatchChangeType string
+
+const (
+	WatchChangeAdd    WatchChangeType = "Add"
+	WatchChangeModify WatchChangeType = "Modify"
+	WatchChangeDelete WatchChangeType = "Delete"
+)
+
+// DirectoryWatchSpec defines the desired state of DirectoryWatch.
+type DirectoryWatchSpec struct {
+	// Directory to watch for changes
+	// +kubebuilder:validation:Required
+	DirectoryRef corev1.LocalObjectReference `json:"directoryRef"`
+	// LdapBinding whose service account the watch binds as. Only entries and attributes its access controls
+	// allow it to read are delivered
+	// +kubebuilder:validation:Required
+	BindingRef corev1.LocalObjectReference `json:"bindingRef"`
+	// Name of the database to watch. Defaults to the database of the binding
+	// +kubebuilder:validation:Optional
+	Database string `json:"database,omitempty"`
+	// DN of the subtree to watch. Defaults to the database suffix
+	// +kubebuilder:validation:Optional
+	Base string `json:"base,omitempty"`
+	// Filter selecting the entries to watch
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="(objectClass=*)"
+	Filter string `json:"filter,omitempty"`
+	// Attributes included in events. Changes to other attributes aren't delivered. Defaults to all user attributes.
+	// Password attributes such as userPassword are never included
+	// +kubebuilder:validation:Optional
+	Attributes []string `json:"attributes,omitempty"`
+	// Kinds of change to deliver. Defaults to all changes
+	// +kubebuilder:validation:Optional
+	Changes []WatchChangeType `json:"changes,omitempty"`
+	// Webhook changes are delivered to as CloudEvents
+	// +kubebuilder:validation:Required
+	Sink WebhookSinkSpec `json:"sink"`
+	// Retries of failed deliveries
+	// +kubebuilder:validation:Optional
+	Retry *DeliveryRetrySpec `json:"retry,omitempty"`
+}
+
+// Webhook events are delivered to
+type WebhookSinkSpec struct {
+	// URL of the webhook
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:=`^https?://`
+	URL string `json:"url"`
+	// Secret key containing the CA certificate to verify the webhook with
+	// +kubebuilder:validation:Optional
+	CASecretRef *corev1.SecretKeySelector `json:"caSecretRef,omitempty"`
+	// Secret whose keys and values are sent as HTTP headers, e.g. Authorization
+	// +kubebuilder:validation:Optional
+	HeadersSecretRef *corev1.LocalObjectReference `json:"headersSecretRef,omitempty"`
+}
+
+// Retries of failed deliveries. Changes are delivered in order, so later changes wait for a failed delivery
+// to be retried
+type DeliveryRetrySpec struct {
+	// Number of times delivery of a change is attempted before the watch is restarted from the last delivered
+	// change
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:default:=5
+	Attempts int32 `json:"attempts,omitempty"`
+	// Delay before the first retry, doubling for each retry after
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="1s"
+	Backoff metav1.Duration `json:"backoff,omitempty"`
+}
+
+// DirectoryWatchStatus defines the observed state of DirectoryWatch.
+type DirectoryWatchStatus struct {
+	// Slice of conditions storing the condition of the watch
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// syncrepl cookie of the last delivered change, the watch resumes from it when restarted
+	Cookie string `json:"cookie,omitempty"`
+	// Number of changes delivered to the sink
+	Delivered int64 `json:"delivered,omitempty"`
+	// Time the last change was delivered
+	LastDelivered *metav1.Time `json:"lastDelivered,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Directory",type="string",JSONPath=`.spec.directoryRef.name`
+// +kubebuilder:printcolumn:name="Sink",type="string",JSONPath=`.spec.sink.url`
+// +kubebuilder:printcolumn:name="Delivered",type="integer",JSONPath=`.status.delivered`
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age", type="date",JSONPath=`.metadata.creationTimestamp`
+// DirectoryWatch is the Schema for the directorywatches API.
+type DirectoryWatch struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DirectoryWatchSpec   `json:"spec,omitempty"`
+	Status DirectoryWatchStatus `json:"status,omitempty"`
+}
+
+// Base returns the DN of the subtree watched within a database
+func (watch *DirectoryWatch) Base(db *DatabaseSpec) string {
+	if watch.Spec.Base != "" {
+		return watch.Spec.Base
+	}
+	return db.Suffix
+}
+
+// Filter returns the filter selecting the entries watched
+func (watch *DirectoryWatch) Filter() string {
+	if watch.Spec.Filter != "" {
+		return watch.Spec.Filter
+	}
+	return "(objectClass=*)"
+}
+
+// Delivers returns true if changes of the given type are delivered to the sink
+func (watch *DirectoryWatch) Delivers(change WatchChangeType) bool {
+	if len(watch.Spec.Changes) == 0 {
+		return true
+	}
+	for _, delivered := range watch.Spec.Changes {
+		if delivered == change {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryPolicy returns the number of delivery attempts and the initial backoff between them
+func (watch *DirectoryWatch) RetryPolicy() (int, time.Duration) {
+	attempts, backoff := 5, time.Second
+	if watch.Spec.Retry != nil {
+		if watch.Spec.Retry.Attempts > 0 {
+			attempts = int(watch.Spec.Retry.Attempts)
+		}
+		if watch.Spec.Retry.Backoff.Duration > 0 {
+			backoff = watch.Spec.Retry.Backoff.Duration
+		}
+	}
+	return attempts, backoff
+}
+
+// +kubebuilder:object:root=true
+
+// DirectoryWatchList contains a list of DirectoryWatch.
+type DirectoryWatchList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DirectoryWatch `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DirectoryWatch{}, &DirectoryWatchList{})
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// GroupRoleBindingReadyCondition represents whether the RoleBinding holds the current members of the group
+	GroupRoleBindingReadyCondition = "Ready"
+)
+
+// Type to represent the kind of role a group is bound to
+// +kubebuilder:validation:Enum:=Role;ClusterRole
+type RoleKind string
+
+const (
+	// RoleKindRole is a Role in the namespace of the GroupRoleBinding
+	RoleKindRole RoleKind = "Role"
+	// RoleKindClusterRole is a ClusterRole, granted within the namespace of the GroupRoleBinding
+	RoleKindClusterRole RoleKind = "ClusterRole"
+)
+
+// GroupRoleRef references the role members of the group are bound to
+type GroupRoleRef struct {
+	// Kind of the role
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=ClusterRole
+	Kind RoleKind `json:"kind,omitempty"`
+	// Name of the role
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+}
+
+// GroupRoleBindingSpec defines the desired state of GroupRoleBinding. Only the roles the operator is configured to
+// bind with --bindable-roles can be granted, as the manager itself may bind any role. Anyone able to create a GroupRoleBinding can grant those roles to any
+// group of the directory
+type GroupRoleBindingSpec struct {
+	// Directory holding the group
+	// +kubebuilder:validation:Required
+	DirectoryRef corev1.LocalObjectReference `json:"directoryRef"`
+	// Name of the database holding the group. Defaults to the first database
+	// +kubebuilder:validation:Optional
+	Database string `json:"database,omitempty"`
+	// DN of the group, e.g. cn=developers,ou=groups,dc=example,dc=com. Members of nested groups are members of the group
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	GroupDN string `json:"groupDN"`
+	// Role granted to the members of the group
+	// +kubebuilder:validation:Required
+	RoleRef GroupRoleRef `json:"roleRef"`
+	// Attribute of user entries holding the Kubernetes username
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=uid
+	UsernameAttribute string `json:"usernameAttribute,omitempty"`
+	// Prefix added to usernames, matching the username prefix of the authenticator, e.g. oidc:
+	// +kubebuilder:validation:Optional
+	UsernamePrefix string `json:"usernamePrefix,omitempty"`
+	// Maximum depth of nested groups followed
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:default:=10
+	MaxNestingDepth *int32 `json:"maxNestingDepth,omitempty"`
+	// Interval at which membership is resolved again, catching changes made outside the operator. Changes to
+	// LdapUsers and LdapGroups of the directory are picked up immediately
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="10m"
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+}
+
+// GroupRoleBindingStatus defines the observed state of GroupRoleBinding.
+type GroupRoleBindingStatus struct {
+	// Slice of conditions storing the condition of the binding
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Name of the maintained RoleBinding
+	RoleBindingName string `json:"roleBindingName,omitempty"`
+	// Usernames of the members bound to the role
+	Members []string `json:"members,omitempty"`
+	// Time membership was last resolved
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Directory",type="string",JSONPath=`.spec.directoryRef.name`
+// +kubebuilder:printcolumn:name="Group",type="string",JSONPath=`.spec.groupDN`
+// +kubebuilder:printcolumn:name="Role",type="string",JSONPath=`.spec.roleRef.name`
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Last Sync",type="date",JSONPath=`.status.lastSyncTime`,priority=1
+// +kubebuilder:printcolumn:name="Age", type="date",JSONPath=`.metadata.creationTimestamp`
+// GroupRoleBinding is the Schema for the grouprolebindings API.
+type GroupRoleBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GroupRoleBindingSpec   `json:"spec,omitempty"`
+	Status GroupRoleBindingStatus `json:"status,omitempty"`
+}
+
+// RoleKind returns the kind of the bound role
+func (binding *GroupRoleBinding) RoleKind() RoleKind {
+	if binding.Spec.RoleRef.Kind != "" {
+		return binding.Spec.RoleRef.Kind
+	}
+	return RoleKindClusterRole
+}
+
+// UsernameAttribute returns the attribute of user entries holding the Kubernetes username
+func (binding *GroupRoleBinding) UsernameAttribute() string {
+	if binding.Spec.UsernameAttribute != "" {
+		return binding.Spec.UsernameAttribute
+	}
+	return "uid"
+}
+
+// MaxNestingDepth returns the maximum depth of nested groups followed
+func (binding *GroupRoleBinding) MaxNestingDepth() int {
+	if binding.Spec.MaxNestingDepth != nil {
+		return int(*binding.Spec.MaxNestingDepth)
+	}
+	return 10
+}
+
+// ResyncInterval returns the interval at which membership is resolved again
+func (binding *GroupRoleBinding) ResyncInterval() time.Duration {
+	if binding.Spec.ResyncInterval != nil && binding.Spec.ResyncInterval.Duration > 0 {
+		return binding.Spec.ResyncInterval.Duration
+	}
+	return 10 * time.Minute
+}
+
+// +kubebuilder:object:root=true
+
+// GroupRoleBindingList contains a list of GroupRoleBinding.
+type GroupRoleBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GroupRoleBinding `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GroupRoleBinding{}, &GroupRoleBindingList{})
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LdapBindingReadyCondition represents whether the service account and connection secret are up to date
+	LdapBindingReadyCondition = "Ready"
+
+	// RotatePasswordAnnotation requests a new password for a binding whenever its value changes
+	RotatePasswordAnnotation = "openldap.my.domain/rotate-password"
+)
+
+// Type to represent the access granted to a binding
+// +kubebuilder:validation:Enum:=Read;Write
+type BindingAccess string
+
+const (
+	// BindingAccessRead grants read access to the binding scope
+	BindingAccessRead BindingAccess = "Read"
+	// BindingAccessWrite grants write access to the binding scope
+	BindingAccessWrite BindingAccess = "Write"
+)
+
+// LdapBindingSpec defines the desired state of LdapBinding.
+type LdapBindingSpec struct {
+	// Directory to create the service account in
+	// +kubebuilder:validation:Required
+	DirectoryRef corev1.LocalObjectReference `json:"directoryRef"`
+	// Name of the database to create the service account in. Defaults to the first database
+	// +kubebuilder:validation:Optional
+	Database string `json:"database,omitempty"`
+	// Access granted to the service account
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Read
+	Access BindingAccess `json:"access,omitempty"`
+	// DN of the subtree access is granted to, at or below the database suffix. Defaults to the database suffix
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern:=`^[A-Za-z][A-Za-z0-9-]*=[^"\r\n]+$`
+	Scope string `json:"scope,omitempty"`
+	// Name of the secret to write connection details to. Defaults to the name of the binding
+	// +kubebuilder:validation:Optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// LdapBindingStatus defines the observed state of LdapBinding.
+type LdapBindingStatus struct {
+	// Slice of conditions storing the condition of the binding
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// DN of the service account entry
+	BindDN string `json:"bindDN,omitempty"`
+	// Name of the connection secret
+	SecretName string `json:"secretName,omitempty"`
+	// Value of the rotate password annotation when the password was last rotated
+	PasswordRotation string `json:"passwordRotation,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Directory",type="string",JSONPath=`.spec.directoryRef.name`
+// +kubebuilder:printcolumn:name="Bind DN",type="string",JSONPath=`.status.bindDN`
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age", type="date",JSONPath=`.metadata.creationTimestamp`
+// LdapBinding is the Schema for the ldapbindings API.
+type LdapBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LdapBindingSpec   `json:"spec,omitempty"`
+	Status LdapBindingStatus `json:"status,omitempty"`
+}
+
+func (binding *LdapBinding) SecretName() string {
+	if binding.Spec.SecretName != "" {
+		return binding.Spec.SecretName
+	}
+	return binding.Name
+}
+
+// BindDN returns the DN of the service account entry within a database
+func (binding *LdapBinding) BindDN(db *DatabaseSpec) string {
+	return fmt.Sprintf("cn=%s,%s", binding.Name, db.ServicesDN())
+}
+
+// Scope returns the DN of the subtree access is granted to within a database
+func (binding *LdapBinding) Scope(db *DatabaseSpec) string {
+	if binding.Spec.Scope != "" {
+		return binding.Spec.Scope
+	}
+	return db.Suffix
+}
+
+// +kubebuilder:object:root=true
+
+// LdapBindingList contains a list of LdapBinding.
+type LdapBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LdapBinding `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&LdapBinding{}, &LdapBindingList{})
+}
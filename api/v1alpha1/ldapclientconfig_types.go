@@ -0,0 +1,191 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LdapClientConfigReadyCondition represents whether the generated client configuration is up to date
+	LdapClientConfigReadyCondition = "Ready"
+)
+
+// Type to represent a generated client configuration file
+// +kubebuilder:validation:Enum:=ldap.conf;sssd.conf;nslcd.conf;dex
+type ClientConfigFormat string
+
+const (
+	// ClientConfigLdapConf is the configuration of the OpenLDAP client libraries and tools
+	ClientConfigLdapConf ClientConfigFormat = "ldap.conf"
+	// ClientConfigSSSD is the configuration of sssd
+	ClientConfigSSSD ClientConfigFormat = "sssd.conf"
+	// ClientConfigNslcd is the configuration of nslcd
+	ClientConfigNslcd ClientConfigFormat = "nslcd.conf"
+	// ClientConfigDex is an LDAP connector of Dex
+	ClientConfigDex ClientConfigFormat = "dex"
+)
+
+// defaultClientConfigFormats are generated when no formats are set
+var defaultClientConfigFormats = []ClientConfigFormat{ClientConfigLdapConf, ClientConfigSSSD, ClientConfigNslcd}
+
+// Type to represent where clients reach the directory from
+// +kubebuilder:validation:Enum:=Internal;External
+type ClientEndpoint string
+
+const (
+	// ClientEndpointInternal clients run within the cluster and use the services of the directory
+	ClientEndpointInternal ClientEndpoint = "Internal"
+	// ClientEndpointExternal clients run outside the cluster and use the external URIs of a LoadBalancer service
+	ClientEndpointExternal ClientEndpoint = "External"
+)
+
+// LdapClientConfigSpec defines the desired state of LdapClientConfig.
+type LdapClientConfigSpec struct {
+	// Directory clients connect to
+	// +kubebuilder:validation:Required
+	DirectoryRef corev1.LocalObjectReference `json:"directoryRef"`
+	// LdapBinding whose service account clients bind as. Clients bind anonymously if not set
+	// +kubebuilder:validation:Optional
+	BindingRef *corev1.LocalObjectReference `json:"bindingRef,omitempty"`
+	// Name of the database clients search when no binding is set. Defaults to the first database
+	// +kubebuilder:validation:Optional
+	Database string `json:"database,omitempty"`
+	// Configuration files to generate. ldap.conf is written to a ConfigMap, sssd.conf, nslcd.conf and the Dex
+	// connector contain the bind password and are written to a Secret. Both are named after the LdapClientConfig
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={"ldap.conf","sssd.conf","nslcd.conf"}
+	Formats []ClientConfigFormat `json:"formats,omitempty"`
+	// Where clients reach the directory from
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Internal
+	Endpoint ClientEndpoint `json:"endpoint,omitempty"`
+	// Path the ca.crt key of the ConfigMap is mounted at on clients. Only used when the directory uses TLS
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=/etc/ldap/ca.crt
+	CACertPath string `json:"caCertPath,omitempty"`
+	// Name of the sssd domain. Defaults to the name of the directory
+	// +kubebuilder:validation:Optional
+	Domain string `json:"domain,omitempty"`
+	// Settings of the Dex connector. Only used when the dex format is generated
+	// +kubebuilder:validation:Optional
+	Dex *DexConnectorSpec `json:"dex,omitempty"`
+}
+
+// DexConnectorSpec defines the connector entry added to the connectors of Dex
+type DexConnectorSpec struct {
+	// ID of the connector, which is part of the subject of issued tokens and must not change once in use
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=ldap
+	ID string `json:"id,omitempty"`
+	// Name of the connector shown on the login page
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=OpenLDAP
+	Name string `json:"name,omitempty"`
+	// Prompt of the username field on the login page
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Username
+	UsernamePrompt string `json:"usernamePrompt,omitempty"`
+}
+
+// LdapClientConfigStatus defines the observed state of LdapClientConfig.
+type LdapClientConfigStatus struct {
+	// Slice of conditions storing the condition of the client configuration
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Name of the ConfigMap holding ldap.conf and the CA certificate
+	ConfigMapName string `json:"configMapName,omitempty"`
+	// Name of the Secret holding sssd.conf and nslcd.conf
+	SecretName string `json:"secretName,omitempty"`
+	// URIs written to the configuration
+	URIs []string `json:"uris,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Directory",type="string",JSONPath=`.spec.directoryRef.name`
+// +kubebuilder:printcolumn:name="Binding",type="string",JSONPath=`.spec.bindingRef.name`
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age", type="date",JSONPath=`.metadata.creationTimestamp`
+// LdapClientConfig is the Schema for the ldapclientconfigs API.
+type LdapClientConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LdapClientConfigSpec   `json:"spec,omitempty"`
+	Status LdapClientConfigStatus `json:"status,omitempty"`
+}
+
+// Generates returns whether the configuration file is generated
+func (config *LdapClientConfig) Generates(format ClientConfigFormat) bool {
+	formats := config.Spec.Formats
+	if len(formats) == 0 {
+		formats = defaultClientConfigFormats
+	}
+	for _, generated := range formats {
+		if generated == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Domain returns the name of the sssd domain
+func (config *LdapClientConfig) Domain() string {
+	if config.Spec.Domain != "" {
+		return config.Spec.Domain
+	}
+	return config.Spec.DirectoryRef.Name
+}
+
+// CACertPath returns the path of the CA certificate on clients
+func (config *LdapClientConfig) CACertPath() string {
+	if config.Spec.CACertPath != "" {
+		return config.Spec.CACertPath
+	}
+	return "/etc/ldap/ca.crt"
+}
+
+// DexConnector returns the settings of the Dex connector with defaults applied
+func (config *LdapClientConfig) DexConnector() DexConnectorSpec {
+	dex := DexConnectorSpec{ID: "ldap", Name: "OpenLDAP", UsernamePrompt: "Username"}
+	if config.Spec.Dex != nil {
+		if config.Spec.Dex.ID != "" {
+			dex.ID = config.Spec.Dex.ID
+		}
+		if config.Spec.Dex.Name != "" {
+			dex.Name = config.Spec.Dex.Name
+		}
+		if config.Spec.Dex.UsernamePrompt != "" {
+			dex.UsernamePrompt = config.Spec.Dex.UsernamePrompt
+		}
+	}
+	return dex
+}
+
+// +kubebuilder:object:root=true
+
+// LdapClientConfigList contains a list of LdapClientConfig.
+type LdapClientConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LdapClientConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&LdapClientConfig{}, &LdapClientConfigList{})
+}
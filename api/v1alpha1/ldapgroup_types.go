@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LdapGroupReadyCondition represents whether the group entry is up to date
+	LdapGroupReadyCondition = "Ready"
+)
+
+// LdapGroupSpec defines the desired state of LdapGroup.
+type LdapGroupSpec struct {
+	// Directory to create the group in
+	// +kubebuilder:validation:Required
+	DirectoryRef corev1.LocalObjectReference `json:"directoryRef"`
+	// Name of the database to create the group in. Defaults to the first database
+	// +kubebuilder:validation:Optional
+	Database string `json:"database,omitempty"`
+	// Name of the group, used as the RDN of the entry. Defaults to the name of the LdapGroup
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern:=`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`
+	CN string `json:"cn,omitempty"`
+	// Description of the group
+	// +kubebuilder:validation:Optional
+	Description string `json:"description,omitempty"`
+	// LdapUsers in the same namespace which are members of the group. Users must belong to the same
+	// directory and database as the group
+	// +kubebuilder:validation:Optional
+	// +listType:=map
+	// +listMapKey:=name
+	Members []corev1.LocalObjectReference `json:"members,omitempty"`
+	// POSIX group attributes. The entry is made a posixGroup listing the uids of its members instead of a
+	// groupOfNames if set
+	// +kubebuilder:validation:Optional
+	Posix *PosixGroupSpec `json:"posix,omitempty"`
+}
+
+// Spec of the POSIX attributes of a group
+type PosixGroupSpec struct {
+	// Numeric group id. Refers to gidNumber. Defaults to an id allocated from the GID range of the directory
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	GIDNumber *int64 `json:"gidNumber,omitempty"`
+}
+
+// LdapGroupStatus defines the observed state of LdapGroup.
+type LdapGroupStatus struct {
+	// Slice of conditions storing the condition of the group
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// DN of the group entry
+	DN string `json:"dn,omitempty"`
+	// DNs of the members of the group entry
+	Members []string `json:"members,omitempty"`
+	// gidNumber of a POSIX group, either set in the spec or allocated
+	GIDNumber *int64 `json:"gidNumber,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Directory",type="string",JSONPath=`.spec.directoryRef.name`
+// +kubebuilder:printcolumn:name="DN",type="string",JSONPath=`.status.dn`
+// +kubebuilder:printcolumn:name="GID Number",type="integer",JSONPath=`.status.gidNumber`,priority=1
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age", type="date",JSONPath=`.metadata.creationTimestamp`
+// LdapGroup is the Schema for the ldapgroups API.
+type LdapGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LdapGroupSpec   `json:"spec,omitempty"`
+	Status LdapGroupStatus `json:"status,omitempty"`
+}
+
+// CN returns the name of the group
+func (group *LdapGroup) CN() string {
+	if group.Spec.CN != "" {
+		return group.Spec.CN
+	}
+	return group.Name
+}
+
+// DN returns the DN of the group entry within a database
+func (group *LdapGroup) DN(db *DatabaseSpec) string {
+	return fmt.Sprintf("cn=%s,%s", group.CN(), db.GroupsDN())
+}
+
+// HasMember returns whether the LdapUser with the given name is a member of the group
+func (group *LdapGroup) HasMember(name string) bool {
+	for _, member := range group.Spec.Members {
+		if member.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// +kubebuilder:object:root=true
+
+// LdapGroupList contains a list of LdapGroup.
+type LdapGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LdapGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&LdapGroup{}, &LdapGroupList{})
+}
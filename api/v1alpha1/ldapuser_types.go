@@ -0,0 +1,202 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LdapUserReadyCondition represents whether the user entry is up to date
+	LdapUserReadyCondition = "Ready"
+)
+
+// LdapUserSpec defines the desired state of LdapUser.
+type LdapUserSpec struct {
+	// Directory to create the user in
+	// +kubebuilder:validation:Required
+	DirectoryRef corev1.LocalObjectReference `json:"directoryRef"`
+	// Name of the database to create the user in. Defaults to the first database
+	// +kubebuilder:validation:Optional
+	Database string `json:"database,omitempty"`
+	// Login name of the user, used as the RDN of the entry. Defaults to the name of the LdapUser
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern:=`^[a-z_][a-z0-9_.-]*$`
+	UID string `json:"uid,omitempty"`
+	// Full name of the user
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	CN string `json:"cn"`
+	// Surname of the user
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	SN string `json:"sn"`
+	// Given name of the user
+	// +kubebuilder:validation:Optional
+	GivenName string `json:"givenName,omitempty"`
+	// Name shown when displaying the user
+	// +kubebuilder:validation:Optional
+	DisplayName string `json:"displayName,omitempty"`
+	// Email addresses of the user
+	// +kubebuilder:validation:Optional
+	Mail []string `json:"mail,omitempty"`
+	// POSIX account attributes. The entry is made a posixAccount if set
+	// +kubebuilder:validation:Optional
+	Posix *PosixAccountSpec `json:"posix,omitempty"`
+	// Secret key containing the password of the user. The user can't bind without a password
+	// +kubebuilder:validation:Optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+	// SSH public keys written to the sshPublicKey attribute of the user. Requires the openssh-lpk schema
+	// +kubebuilder:validation:Optional
+	// +listType:=map
+	// +listMapKey:=name
+	SSHPublicKeys []SSHPublicKeySpec `json:"sshPublicKeys,omitempty"`
+}
+
+// Spec of an SSH public key of a user
+type SSHPublicKeySpec struct {
+	// Name identifying the key
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+	// Public key in authorized_keys format, e.g. ssh-ed25519 AAAA... user@host. Key options aren't allowed
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Key string `json:"key"`
+	// Time after which the key is removed from the user entry
+	// +kubebuilder:validation:Optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	// Remove the key from the user entry. The fingerprint of a revoked key is recorded so the key can't be added
+	// back, even under another name
+	// +kubebuilder:validation:Optional
+	Revoked bool `json:"revoked,omitempty"`
+}
+
+// Type to represent whether an SSH public key is written to the user entry
+type SSHPublicKeyState string
+
+const (
+	// SSHPublicKeyActive keys are written to the user entry
+	SSHPublicKeyActive SSHPublicKeyState = "Active"
+	// SSHPublicKeyExpired keys are past their expiry time
+	SSHPublicKeyExpired SSHPublicKeyState = "Expired"
+	// SSHPublicKeyRevoked keys are revoked, or have the fingerprint of a revoked key
+	SSHPublicKeyRevoked SSHPublicKeyState = "Revoked"
+	// SSHPublicKeyInvalid keys couldn't be parsed or are too weak
+	SSHPublicKeyInvalid SSHPublicKeyState = "Invalid"
+)
+
+// Observed state of an SSH public key of a user
+type SSHPublicKeyStatus struct {
+	// Name of the key
+	Name string `json:"name"`
+	// SHA256 fingerprint of the key
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Whether the key is written to the user entry
+	State SSHPublicKeyState `json:"state"`
+	// Reason an invalid key was rejected
+	Message string `json:"message,omitempty"`
+}
+
+// Spec of the POSIX account attributes of a user
+type PosixAccountSpec struct {
+	// Numeric user id. Refers to uidNumber. Defaults to an id allocated from the UID range of the directory
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	UIDNumber *int64 `json:"uidNumber,omitempty"`
+	// Numeric id of the primary group. Refers to gidNumber. Defaults to an id allocated from the GID range of
+	// the directory, giving the user a private group id
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	GIDNumber *int64 `json:"gidNumber,omitempty"`
+	// Home directory of the user. Defaults to /home/<uid>
+	// +kubebuilder:validation:Optional
+	HomeDirectory string `json:"homeDirectory,omitempty"`
+	// Login shell of the user
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=/bin/bash
+	LoginShell string `json:"loginShell,omitempty"`
+}
+
+// LdapUserStatus defines the observed state of LdapUser.
+type LdapUserStatus struct {
+	// Slice of conditions storing the condition of the user
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// DN of the user entry
+	DN string `json:"dn,omitempty"`
+	// uidNumber of a POSIX user, either set in the spec or allocated
+	UIDNumber *int64 `json:"uidNumber,omitempty"`
+	// gidNumber of a POSIX user, either set in the spec or allocated
+	GIDNumber *int64 `json:"gidNumber,omitempty"`
+	// State of the SSH public keys of the user
+	SSHPublicKeys []SSHPublicKeyStatus `json:"sshPublicKeys,omitempty"`
+	// Fingerprints of the SSH public keys which have been revoked
+	RevokedSSHPublicKeys []string `json:"revokedSSHPublicKeys,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Directory",type="string",JSONPath=`.spec.directoryRef.name`
+// +kubebuilder:printcolumn:name="DN",type="string",JSONPath=`.status.dn`
+// +kubebuilder:printcolumn:name="UID Number",type="integer",JSONPath=`.status.uidNumber`,priority=1
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age", type="date",JSONPath=`.metadata.creationTimestamp`
+// LdapUser is the Schema for the ldapusers API.
+type LdapUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LdapUserSpec   `json:"spec,omitempty"`
+	Status LdapUserStatus `json:"status,omitempty"`
+}
+
+// UID returns the login name of the user
+func (user *LdapUser) UID() string {
+	if user.Spec.UID != "" {
+		return user.Spec.UID
+	}
+	return user.Name
+}
+
+// DN returns the DN of the user entry within a database
+func (user *LdapUser) DN(db *DatabaseSpec) string {
+	return fmt.Sprintf("uid=%s,%s", user.UID(), db.PeopleDN())
+}
+
+// HomeDirectory returns the home directory of a POSIX user
+func (user *LdapUser) HomeDirectory() string {
+	if user.Spec.Posix != nil && user.Spec.Posix.HomeDirectory != "" {
+		return user.Spec.Posix.HomeDirectory
+	}
+	return fmt.Sprintf("/home/%s", user.UID())
+}
+
+// +kubebuilder:object:root=true
+
+// LdapUserList contains a list of LdapUser.
+type LdapUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LdapUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&LdapUser{}, &LdapUserList{})
+}
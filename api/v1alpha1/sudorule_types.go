@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SudoRuleReadyCondition represents whether the sudoRole entry is up to date
+	SudoRuleReadyCondition = "Ready"
+)
+
+// SudoRuleSpec defines the desired state of SudoRule.
+type SudoRuleSpec struct {
+	// Directory to create the rule in
+	// +kubebuilder:validation:Required
+	DirectoryRef corev1.LocalObjectReference `json:"directoryRef"`
+	// Name of the database to create the rule in. Defaults to the first database
+	// +kubebuilder:validation:Optional
+	Database string `json:"database,omitempty"`
+	// Users the rule applies to: user names, %group, %#gid, #uid, +netgroup or ALL. Refers to sudoUser
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems:=1
+	Users []string `json:"users"`
+	// Hosts the rule applies to: host names, IP addresses, networks, +netgroup or ALL. Refers to sudoHost
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={"ALL"}
+	Hosts []string `json:"hosts,omitempty"`
+	// Commands the users may run: absolute paths with optional arguments, optionally prefixed with a digest
+	// such as sha256:<digest>, sudoedit with absolute paths, or ALL. Commands prefixed with ! are denied.
+	// Refers to sudoCommand
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems:=1
+	Commands []string `json:"commands"`
+	// Users commands may be run as. Refers to sudoRunAsUser
+	// +kubebuilder:validation:Optional
+	RunAsUsers []string `json:"runAsUsers,omitempty"`
+	// Groups commands may be run as. Refers to sudoRunAsGroup
+	// +kubebuilder:validation:Optional
+	RunAsGroups []string `json:"runAsGroups,omitempty"`
+	// sudoers options of the rule, e.g. !authenticate or env_keep+=SSH_AUTH_SOCK. Refers to sudoOption
+	// +kubebuilder:validation:Optional
+	Options []string `json:"options,omitempty"`
+	// Time the rule starts applying. Only honoured by hosts with SUDOERS_TIMED set. Refers to sudoNotBefore
+	// +kubebuilder:validation:Optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+	// Time the rule stops applying. Only honoured by hosts with SUDOERS_TIMED set. Refers to sudoNotAfter
+	// +kubebuilder:validation:Optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+	// Precedence of the rule, rules with a higher order win. Refers to sudoOrder
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	Order *int32 `json:"order,omitempty"`
+	// Description of the rule
+	// +kubebuilder:validation:Optional
+	Description string `json:"description,omitempty"`
+}
+
+// SudoRuleStatus defines the observed state of SudoRule.
+type SudoRuleStatus struct {
+	// Slice of conditions storing the condition of the rule
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// DN of the sudoRole entry
+	DN string `json:"dn,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Directory",type="string",JSONPath=`.spec.directoryRef.name`
+// +kubebuilder:printcolumn:name="DN",type="string",JSONPath=`.status.dn`
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age", type="date",JSONPath=`.metadata.creationTimestamp`
+// SudoRule is the Schema for the sudorules API.
+type SudoRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SudoRuleSpec   `json:"spec,omitempty"`
+	Status SudoRuleStatus `json:"status,omitempty"`
+}
+
+// DN returns the DN of the sudoRole entry within a database
+func (rule *SudoRule) DN(db *DatabaseSpec) string {
+	return fmt.Sprintf("cn=%s,%s", rule.Name, db.SudoersDN())
+}
+
+// +kubebuilder:object:root=true
+
+// SudoRuleList contains a list of SudoRule.
+type SudoRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SudoRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SudoRule{}, &SudoRuleList{})
+}
@@ -21,10 +21,102 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessLogSpec) DeepCopyInto(out *AccessLogSpec) {
+	*out = *in
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SuccessOnly != nil {
+		in, out := &in.SuccessOnly, &out.SuccessOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessLogSpec.
+func (in *AccessLogSpec) DeepCopy() *AccessLogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessLogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedSeed) DeepCopyInto(out *AppliedSeed) {
+	*out = *in
+	in.AppliedAt.DeepCopyInto(&out.AppliedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedSeed.
+func (in *AppliedSeed) DeepCopy() *AppliedSeed {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedSeed)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditSpec) DeepCopyInto(out *AuditSpec) {
+	*out = *in
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.RedactAttributes != nil {
+		in, out := &in.RedactAttributes, &out.RedactAttributes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditSpec.
+func (in *AuditSpec) DeepCopy() *AuditSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapSpec) DeepCopyInto(out *BootstrapSpec) {
+	*out = *in
+	if in.Seed != nil {
+		in, out := &in.Seed, &out.Seed
+		*out = new(SeedSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapSpec.
+func (in *BootstrapSpec) DeepCopy() *BootstrapSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigDatabaseConfig) DeepCopyInto(out *ConfigDatabaseConfig) {
 	*out = *in
@@ -45,6 +137,218 @@ func (in *ConfigDatabaseConfig) DeepCopy() *ConfigDatabaseConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionStatus) DeepCopyInto(out *ConnectionStatus) {
+	*out = *in
+	if in.ExternalURIs != nil {
+		in, out := &in.ExternalURIs, &out.ExternalURIs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Suffixes != nil {
+		in, out := &in.Suffixes, &out.Suffixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdminSecretRef != nil {
+		in, out := &in.AdminSecretRef, &out.AdminSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionStatus.
+func (in *ConnectionStatus) DeepCopy() *ConnectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseIndexStatus) DeepCopyInto(out *DatabaseIndexStatus) {
+	*out = *in
+	if in.Indexes != nil {
+		in, out := &in.Indexes, &out.Indexes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Indexing != nil {
+		in, out := &in.Indexing, &out.Indexing
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseIndexStatus.
+func (in *DatabaseIndexStatus) DeepCopy() *DatabaseIndexStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseIndexStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseOverlaysSpec) DeepCopyInto(out *DatabaseOverlaysSpec) {
+	*out = *in
+	if in.MemberOf != nil {
+		in, out := &in.MemberOf, &out.MemberOf
+		*out = new(MemberOfOverlaySpec)
+		**out = **in
+	}
+	if in.RefInt != nil {
+		in, out := &in.RefInt, &out.RefInt
+		*out = new(RefIntOverlaySpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseOverlaysSpec.
+func (in *DatabaseOverlaysSpec) DeepCopy() *DatabaseOverlaysSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseOverlaysSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
+	*out = *in
+	if in.Access != nil {
+		in, out := &in.Access, &out.Access
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Overlays != nil {
+		in, out := &in.Overlays, &out.Overlays
+		*out = new(DatabaseOverlaysSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tuning != nil {
+		in, out := &in.Tuning, &out.Tuning
+		*out = new(DatabaseTuningSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Indexes != nil {
+		in, out := &in.Indexes, &out.Indexes
+		*out = make([]IndexSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(AuditSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSpec.
+func (in *DatabaseSpec) DeepCopy() *DatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseSyncStatus) DeepCopyInto(out *DatabaseSyncStatus) {
+	*out = *in
+	if in.ContextCSN != nil {
+		in, out := &in.ContextCSN, &out.ContextCSN
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSyncStatus.
+func (in *DatabaseSyncStatus) DeepCopy() *DatabaseSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseTuningSpec) DeepCopyInto(out *DatabaseTuningSpec) {
+	*out = *in
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.EnvFlags != nil {
+		in, out := &in.EnvFlags, &out.EnvFlags
+		*out = make([]MdbEnvFlag, len(*in))
+		copy(*out, *in)
+	}
+	if in.Checkpoint != nil {
+		in, out := &in.Checkpoint, &out.Checkpoint
+		*out = new(MdbCheckpointSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseTuningSpec.
+func (in *DatabaseTuningSpec) DeepCopy() *DatabaseTuningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseTuningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeliveryRetrySpec) DeepCopyInto(out *DeliveryRetrySpec) {
+	*out = *in
+	out.Backoff = in.Backoff
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeliveryRetrySpec.
+func (in *DeliveryRetrySpec) DeepCopy() *DeliveryRetrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeliveryRetrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DexConnectorSpec) DeepCopyInto(out *DexConnectorSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DexConnectorSpec.
+func (in *DexConnectorSpec) DeepCopy() *DexConnectorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DexConnectorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Directory) DeepCopyInto(out *Directory) {
 	*out = *in
@@ -122,6 +426,11 @@ func (in *DirectoryServiceSpec) DeepCopy() *DirectoryServiceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DirectorySpec) DeepCopyInto(out *DirectorySpec) {
 	*out = *in
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(ReplicationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SlapdConfig != nil {
 		in, out := &in.SlapdConfig, &out.SlapdConfig
 		*out = new(SlapdConfigSpec)
@@ -132,6 +441,46 @@ func (in *DirectorySpec) DeepCopyInto(out *DirectorySpec) {
 		*out = new(DirectoryServiceSpec)
 		**out = **in
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(DirectoryTLSSpec)
+		**out = **in
+	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(BootstrapSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceBinding != nil {
+		in, out := &in.ServiceBinding, &out.ServiceBinding
+		*out = new(ServiceBindingSpec)
+		**out = **in
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IndexAdvisor != nil {
+		in, out := &in.IndexAdvisor, &out.IndexAdvisor
+		*out = new(IndexAdvisorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(LoggingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IDAllocation != nil {
+		in, out := &in.IDAllocation, &out.IDAllocation
+		*out = new(IDAllocationSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectorySpec.
@@ -149,11 +498,55 @@ func (in *DirectoryStatus) DeepCopyInto(out *DirectoryStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AppliedSeeds != nil {
+		in, out := &in.AppliedSeeds, &out.AppliedSeeds
+		*out = make([]AppliedSeed, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Connection != nil {
+		in, out := &in.Connection, &out.Connection
+		*out = new(ConnectionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Binding != nil {
+		in, out := &in.Binding, &out.Binding
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(ReplicationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalReplicationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Indexes != nil {
+		in, out := &in.Indexes, &out.Indexes
+		*out = make([]DatabaseIndexStatus, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Rebuild != nil {
+		in, out := &in.Rebuild, &out.Rebuild
+		*out = new(RebuildStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IndexAdvice != nil {
+		in, out := &in.IndexAdvice, &out.IndexAdvice
+		*out = new(IndexAdviceStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectoryStatus.
@@ -167,47 +560,1430 @@ func (in *DirectoryStatus) DeepCopy() *DirectoryStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FrontendDatabaseConfig) DeepCopyInto(out *FrontendDatabaseConfig) {
+func (in *DirectoryTLSSpec) DeepCopyInto(out *DirectoryTLSSpec) {
 	*out = *in
-	if in.Access != nil {
-		in, out := &in.Access, &out.Access
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendDatabaseConfig.
-func (in *FrontendDatabaseConfig) DeepCopy() *FrontendDatabaseConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectoryTLSSpec.
+func (in *DirectoryTLSSpec) DeepCopy() *DirectoryTLSSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(FrontendDatabaseConfig)
+	out := new(DirectoryTLSSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in SchemaList) DeepCopyInto(out *SchemaList) {
-	{
-		in := &in
-		*out = make(SchemaList, len(*in))
-		copy(*out, *in)
-	}
+func (in *DirectoryWatch) DeepCopyInto(out *DirectoryWatch) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaList.
-func (in SchemaList) DeepCopy() SchemaList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectoryWatch.
+func (in *DirectoryWatch) DeepCopy() *DirectoryWatch {
 	if in == nil {
 		return nil
 	}
-	out := new(SchemaList)
+	out := new(DirectoryWatch)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SlapdConfigSpec) DeepCopyInto(out *SlapdConfigSpec) {
-	*out = *in
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DirectoryWatch) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DirectoryWatchList) DeepCopyInto(out *DirectoryWatchList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DirectoryWatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectoryWatchList.
+func (in *DirectoryWatchList) DeepCopy() *DirectoryWatchList {
+	if in == nil {
+		return nil
+	}
+	out := new(DirectoryWatchList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DirectoryWatchList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DirectoryWatchSpec) DeepCopyInto(out *DirectoryWatchSpec) {
+	*out = *in
+	out.DirectoryRef = in.DirectoryRef
+	out.BindingRef = in.BindingRef
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]WatchChangeType, len(*in))
+		copy(*out, *in)
+	}
+	in.Sink.DeepCopyInto(&out.Sink)
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(DeliveryRetrySpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectoryWatchSpec.
+func (in *DirectoryWatchSpec) DeepCopy() *DirectoryWatchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DirectoryWatchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DirectoryWatchStatus) DeepCopyInto(out *DirectoryWatchStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastDelivered != nil {
+		in, out := &in.LastDelivered, &out.LastDelivered
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectoryWatchStatus.
+func (in *DirectoryWatchStatus) DeepCopy() *DirectoryWatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DirectoryWatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalProviderSpec) DeepCopyInto(out *ExternalProviderSpec) {
+	*out = *in
+	in.PasswordSecretRef.DeepCopyInto(&out.PasswordSecretRef)
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalProviderSpec.
+func (in *ExternalProviderSpec) DeepCopy() *ExternalProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalReplicationStatus) DeepCopyInto(out *ExternalReplicationStatus) {
+	*out = *in
+	if in.ContextCSN != nil {
+		in, out := &in.ContextCSN, &out.ContextCSN
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CutOverAt != nil {
+		in, out := &in.CutOverAt, &out.CutOverAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalReplicationStatus.
+func (in *ExternalReplicationStatus) DeepCopy() *ExternalReplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalReplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverStatus) DeepCopyInto(out *FailoverStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverStatus.
+func (in *FailoverStatus) DeepCopy() *FailoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FrontendDatabaseConfig) DeepCopyInto(out *FrontendDatabaseConfig) {
+	*out = *in
+	if in.Access != nil {
+		in, out := &in.Access, &out.Access
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendDatabaseConfig.
+func (in *FrontendDatabaseConfig) DeepCopy() *FrontendDatabaseConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FrontendDatabaseConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupRoleBinding) DeepCopyInto(out *GroupRoleBinding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupRoleBinding.
+func (in *GroupRoleBinding) DeepCopy() *GroupRoleBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupRoleBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GroupRoleBinding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupRoleBindingList) DeepCopyInto(out *GroupRoleBindingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GroupRoleBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupRoleBindingList.
+func (in *GroupRoleBindingList) DeepCopy() *GroupRoleBindingList {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupRoleBindingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GroupRoleBindingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupRoleBindingSpec) DeepCopyInto(out *GroupRoleBindingSpec) {
+	*out = *in
+	out.DirectoryRef = in.DirectoryRef
+	out.RoleRef = in.RoleRef
+	if in.MaxNestingDepth != nil {
+		in, out := &in.MaxNestingDepth, &out.MaxNestingDepth
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ResyncInterval != nil {
+		in, out := &in.ResyncInterval, &out.ResyncInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupRoleBindingSpec.
+func (in *GroupRoleBindingSpec) DeepCopy() *GroupRoleBindingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupRoleBindingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupRoleBindingStatus) DeepCopyInto(out *GroupRoleBindingStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupRoleBindingStatus.
+func (in *GroupRoleBindingStatus) DeepCopy() *GroupRoleBindingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupRoleBindingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupRoleRef) DeepCopyInto(out *GroupRoleRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupRoleRef.
+func (in *GroupRoleRef) DeepCopy() *GroupRoleRef {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupRoleRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IDAllocationSpec) DeepCopyInto(out *IDAllocationSpec) {
+	*out = *in
+	out.UIDs = in.UIDs
+	out.GIDs = in.GIDs
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IDAllocationSpec.
+func (in *IDAllocationSpec) DeepCopy() *IDAllocationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IDAllocationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IDRange) DeepCopyInto(out *IDRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IDRange.
+func (in *IDRange) DeepCopy() *IDRange {
+	if in == nil {
+		return nil
+	}
+	out := new(IDRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexAdviceStatus) DeepCopyInto(out *IndexAdviceStatus) {
+	*out = *in
+	if in.LastChecked != nil {
+		in, out := &in.LastChecked, &out.LastChecked
+		*out = (*in).DeepCopy()
+	}
+	if in.Positions != nil {
+		in, out := &in.Positions, &out.Positions
+		*out = make([]LogPosition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Suggestions != nil {
+		in, out := &in.Suggestions, &out.Suggestions
+		*out = make([]IndexSuggestion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexAdviceStatus.
+func (in *IndexAdviceStatus) DeepCopy() *IndexAdviceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexAdviceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexAdvisorSpec) DeepCopyInto(out *IndexAdvisorSpec) {
+	*out = *in
+	if in.SlowThreshold != nil {
+		in, out := &in.SlowThreshold, &out.SlowThreshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexAdvisorSpec.
+func (in *IndexAdvisorSpec) DeepCopy() *IndexAdvisorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexAdvisorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexSpec) DeepCopyInto(out *IndexSpec) {
+	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]IndexType, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexSpec.
+func (in *IndexSpec) DeepCopy() *IndexSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexSuggestion) DeepCopyInto(out *IndexSuggestion) {
+	*out = *in
+	in.LastSeen.DeepCopyInto(&out.LastSeen)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexSuggestion.
+func (in *IndexSuggestion) DeepCopy() *IndexSuggestion {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexSuggestion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapBinding) DeepCopyInto(out *LdapBinding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapBinding.
+func (in *LdapBinding) DeepCopy() *LdapBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LdapBinding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapBindingList) DeepCopyInto(out *LdapBindingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LdapBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapBindingList.
+func (in *LdapBindingList) DeepCopy() *LdapBindingList {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapBindingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LdapBindingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapBindingSpec) DeepCopyInto(out *LdapBindingSpec) {
+	*out = *in
+	out.DirectoryRef = in.DirectoryRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapBindingSpec.
+func (in *LdapBindingSpec) DeepCopy() *LdapBindingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapBindingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapBindingStatus) DeepCopyInto(out *LdapBindingStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapBindingStatus.
+func (in *LdapBindingStatus) DeepCopy() *LdapBindingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapBindingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapClientConfig) DeepCopyInto(out *LdapClientConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapClientConfig.
+func (in *LdapClientConfig) DeepCopy() *LdapClientConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapClientConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LdapClientConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapClientConfigList) DeepCopyInto(out *LdapClientConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LdapClientConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapClientConfigList.
+func (in *LdapClientConfigList) DeepCopy() *LdapClientConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapClientConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LdapClientConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapClientConfigSpec) DeepCopyInto(out *LdapClientConfigSpec) {
+	*out = *in
+	out.DirectoryRef = in.DirectoryRef
+	if in.BindingRef != nil {
+		in, out := &in.BindingRef, &out.BindingRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Formats != nil {
+		in, out := &in.Formats, &out.Formats
+		*out = make([]ClientConfigFormat, len(*in))
+		copy(*out, *in)
+	}
+	if in.Dex != nil {
+		in, out := &in.Dex, &out.Dex
+		*out = new(DexConnectorSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapClientConfigSpec.
+func (in *LdapClientConfigSpec) DeepCopy() *LdapClientConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapClientConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapClientConfigStatus) DeepCopyInto(out *LdapClientConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.URIs != nil {
+		in, out := &in.URIs, &out.URIs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapClientConfigStatus.
+func (in *LdapClientConfigStatus) DeepCopy() *LdapClientConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapClientConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapGroup) DeepCopyInto(out *LdapGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapGroup.
+func (in *LdapGroup) DeepCopy() *LdapGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LdapGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapGroupList) DeepCopyInto(out *LdapGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LdapGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapGroupList.
+func (in *LdapGroupList) DeepCopy() *LdapGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LdapGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapGroupSpec) DeepCopyInto(out *LdapGroupSpec) {
+	*out = *in
+	out.DirectoryRef = in.DirectoryRef
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Posix != nil {
+		in, out := &in.Posix, &out.Posix
+		*out = new(PosixGroupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapGroupSpec.
+func (in *LdapGroupSpec) DeepCopy() *LdapGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapGroupStatus) DeepCopyInto(out *LdapGroupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GIDNumber != nil {
+		in, out := &in.GIDNumber, &out.GIDNumber
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapGroupStatus.
+func (in *LdapGroupStatus) DeepCopy() *LdapGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapUser) DeepCopyInto(out *LdapUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapUser.
+func (in *LdapUser) DeepCopy() *LdapUser {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LdapUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapUserList) DeepCopyInto(out *LdapUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LdapUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapUserList.
+func (in *LdapUserList) DeepCopy() *LdapUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LdapUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapUserSpec) DeepCopyInto(out *LdapUserSpec) {
+	*out = *in
+	out.DirectoryRef = in.DirectoryRef
+	if in.Mail != nil {
+		in, out := &in.Mail, &out.Mail
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Posix != nil {
+		in, out := &in.Posix, &out.Posix
+		*out = new(PosixAccountSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SSHPublicKeys != nil {
+		in, out := &in.SSHPublicKeys, &out.SSHPublicKeys
+		*out = make([]SSHPublicKeySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapUserSpec.
+func (in *LdapUserSpec) DeepCopy() *LdapUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapUserStatus) DeepCopyInto(out *LdapUserStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UIDNumber != nil {
+		in, out := &in.UIDNumber, &out.UIDNumber
+		*out = new(int64)
+		**out = **in
+	}
+	if in.GIDNumber != nil {
+		in, out := &in.GIDNumber, &out.GIDNumber
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SSHPublicKeys != nil {
+		in, out := &in.SSHPublicKeys, &out.SSHPublicKeys
+		*out = make([]SSHPublicKeyStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.RevokedSSHPublicKeys != nil {
+		in, out := &in.RevokedSSHPublicKeys, &out.RevokedSSHPublicKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapUserStatus.
+func (in *LdapUserStatus) DeepCopy() *LdapUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogPosition) DeepCopyInto(out *LogPosition) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogPosition.
+func (in *LogPosition) DeepCopy() *LogPosition {
+	if in == nil {
+		return nil
+	}
+	out := new(LogPosition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingSpec) DeepCopyInto(out *LoggingSpec) {
+	*out = *in
+	if in.Level != nil {
+		in, out := &in.Level, &out.Level
+		*out = make([]LogLevel, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingSpec.
+func (in *LoggingSpec) DeepCopy() *LoggingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MdbCheckpointSpec) DeepCopyInto(out *MdbCheckpointSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MdbCheckpointSpec.
+func (in *MdbCheckpointSpec) DeepCopy() *MdbCheckpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MdbCheckpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberOfOverlaySpec) DeepCopyInto(out *MemberOfOverlaySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberOfOverlaySpec.
+func (in *MemberOfOverlaySpec) DeepCopy() *MemberOfOverlaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberOfOverlaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeerStatus) DeepCopyInto(out *PeerStatus) {
+	*out = *in
+	if in.ContextCSN != nil {
+		in, out := &in.ContextCSN, &out.ContextCSN
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeerStatus.
+func (in *PeerStatus) DeepCopy() *PeerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PosixAccountSpec) DeepCopyInto(out *PosixAccountSpec) {
+	*out = *in
+	if in.UIDNumber != nil {
+		in, out := &in.UIDNumber, &out.UIDNumber
+		*out = new(int64)
+		**out = **in
+	}
+	if in.GIDNumber != nil {
+		in, out := &in.GIDNumber, &out.GIDNumber
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PosixAccountSpec.
+func (in *PosixAccountSpec) DeepCopy() *PosixAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PosixAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PosixGroupSpec) DeepCopyInto(out *PosixGroupSpec) {
+	*out = *in
+	if in.GIDNumber != nil {
+		in, out := &in.GIDNumber, &out.GIDNumber
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PosixGroupSpec.
+func (in *PosixGroupSpec) DeepCopy() *PosixGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PosixGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RebuildStatus) DeepCopyInto(out *RebuildStatus) {
+	*out = *in
+	if in.Remaining != nil {
+		in, out := &in.Remaining, &out.Remaining
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeletedAt != nil {
+		in, out := &in.DeletedAt, &out.DeletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RebuildStatus.
+func (in *RebuildStatus) DeepCopy() *RebuildStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RebuildStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RefIntOverlaySpec) DeepCopyInto(out *RefIntOverlaySpec) {
+	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RefIntOverlaySpec.
+func (in *RefIntOverlaySpec) DeepCopy() *RefIntOverlaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RefIntOverlaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaStatus) DeepCopyInto(out *ReplicaStatus) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]DatabaseSyncStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaStatus.
+func (in *ReplicaStatus) DeepCopy() *ReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationMember) DeepCopyInto(out *ReplicationMember) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationMember.
+func (in *ReplicationMember) DeepCopy() *ReplicationMember {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationPeerSpec) DeepCopyInto(out *ReplicationPeerSpec) {
+	*out = *in
+	in.PasswordSecretRef.DeepCopyInto(&out.PasswordSecretRef)
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationPeerSpec.
+func (in *ReplicationPeerSpec) DeepCopy() *ReplicationPeerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationPeerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationSpec) DeepCopyInto(out *ReplicationSpec) {
+	*out = *in
+	if in.Provider != nil {
+		in, out := &in.Provider, &out.Provider
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AccessLog != nil {
+		in, out := &in.AccessLog, &out.AccessLog
+		*out = new(AccessLogSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxLag != nil {
+		in, out := &in.MaxLag, &out.MaxLag
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalProviderSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = make([]ReplicationPeerSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServerIDs != nil {
+		in, out := &in.ServerIDs, &out.ServerIDs
+		*out = new(ServerIDRange)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationSpec.
+func (in *ReplicationSpec) DeepCopy() *ReplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationStatus) DeepCopyInto(out *ReplicationStatus) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]ReplicationMember, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]ReplicaStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastChecked != nil {
+		in, out := &in.LastChecked, &out.LastChecked
+		*out = (*in).DeepCopy()
+	}
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = make([]PeerStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Provider != nil {
+		in, out := &in.Provider, &out.Provider
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ObservedProvider != nil {
+		in, out := &in.ObservedProvider, &out.ObservedProvider
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LastFailover != nil {
+		in, out := &in.LastFailover, &out.LastFailover
+		*out = new(FailoverStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationStatus.
+func (in *ReplicationStatus) DeepCopy() *ReplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHPublicKeySpec) DeepCopyInto(out *SSHPublicKeySpec) {
+	*out = *in
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHPublicKeySpec.
+func (in *SSHPublicKeySpec) DeepCopy() *SSHPublicKeySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHPublicKeySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHPublicKeyStatus) DeepCopyInto(out *SSHPublicKeyStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHPublicKeyStatus.
+func (in *SSHPublicKeyStatus) DeepCopy() *SSHPublicKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHPublicKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in SchemaList) DeepCopyInto(out *SchemaList) {
+	{
+		in := &in
+		*out = make(SchemaList, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaList.
+func (in SchemaList) DeepCopy() SchemaList {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaList)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedSource) DeepCopyInto(out *SeedSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedSource.
+func (in *SeedSource) DeepCopy() *SeedSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedSpec) DeepCopyInto(out *SeedSpec) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]SeedSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedSpec.
+func (in *SeedSpec) DeepCopy() *SeedSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerIDRange) DeepCopyInto(out *ServerIDRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerIDRange.
+func (in *ServerIDRange) DeepCopy() *ServerIDRange {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerIDRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceBindingSpec) DeepCopyInto(out *ServiceBindingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceBindingSpec.
+func (in *ServiceBindingSpec) DeepCopy() *ServiceBindingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceBindingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlapdConfigSpec) DeepCopyInto(out *SlapdConfigSpec) {
+	*out = *in
 	if in.Schemas != nil {
 		in, out := &in.Schemas, &out.Schemas
 		*out = make(SchemaList, len(*in))
@@ -228,6 +2004,18 @@ func (in *SlapdConfigSpec) DeepCopyInto(out *SlapdConfigSpec) {
 		*out = new(ConfigDatabaseConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]DatabaseSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tuning != nil {
+		in, out := &in.Tuning, &out.Tuning
+		*out = new(TuningSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlapdConfigSpec.
@@ -239,3 +2027,234 @@ func (in *SlapdConfigSpec) DeepCopy() *SlapdConfigSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
+func (in *StorageSpec) DeepCopy() *StorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SudoRule) DeepCopyInto(out *SudoRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SudoRule.
+func (in *SudoRule) DeepCopy() *SudoRule {
+	if in == nil {
+		return nil
+	}
+	out := new(SudoRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SudoRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SudoRuleList) DeepCopyInto(out *SudoRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SudoRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SudoRuleList.
+func (in *SudoRuleList) DeepCopy() *SudoRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(SudoRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SudoRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SudoRuleSpec) DeepCopyInto(out *SudoRuleSpec) {
+	*out = *in
+	out.DirectoryRef = in.DirectoryRef
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Commands != nil {
+		in, out := &in.Commands, &out.Commands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RunAsUsers != nil {
+		in, out := &in.RunAsUsers, &out.RunAsUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RunAsGroups != nil {
+		in, out := &in.RunAsGroups, &out.RunAsGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotBefore != nil {
+		in, out := &in.NotBefore, &out.NotBefore
+		*out = (*in).DeepCopy()
+	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.Order != nil {
+		in, out := &in.Order, &out.Order
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SudoRuleSpec.
+func (in *SudoRuleSpec) DeepCopy() *SudoRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SudoRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SudoRuleStatus) DeepCopyInto(out *SudoRuleStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SudoRuleStatus.
+func (in *SudoRuleStatus) DeepCopy() *SudoRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SudoRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TuningSpec) DeepCopyInto(out *TuningSpec) {
+	*out = *in
+	if in.Threads != nil {
+		in, out := &in.Threads, &out.Threads
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ToolThreads != nil {
+		in, out := &in.ToolThreads, &out.ToolThreads
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IdleTimeout != nil {
+		in, out := &in.IdleTimeout, &out.IdleTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConnMaxPending != nil {
+		in, out := &in.ConnMaxPending, &out.ConnMaxPending
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeLimit != nil {
+		in, out := &in.TimeLimit, &out.TimeLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningSpec.
+func (in *TuningSpec) DeepCopy() *TuningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TuningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookSinkSpec) DeepCopyInto(out *WebhookSinkSpec) {
+	*out = *in
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HeadersSecretRef != nil {
+		in, out := &in.HeadersSecretRef, &out.HeadersSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookSinkSpec.
+func (in *WebhookSinkSpec) DeepCopy() *WebhookSinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookSinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/paddyoneill/openldap-operator/internal/logshipper"
+)
+
+func main() {
+	var file string
+	var audit string
+	var maxSize int64
+	var maxFiles int
+	var redact string
+	flag.StringVar(&file, "file", "/var/log/slapd/slapd.log", "The slapd log file to ship.")
+	flag.StringVar(&audit, "audit", "", "Ship the file as the audit log of the named database instead of the slapd log.")
+	flag.Int64Var(&maxSize, "max-size", 10*1024*1024, "The size at which the audit log is rotated.")
+	flag.IntVar(&maxFiles, "max-files", 3, "The number of rotated audit logs to keep.")
+	flag.StringVar(&redact, "redact", "", "Comma separated attributes whose values are redacted from audit records "+
+		"in addition to password attributes.")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	encoder := json.NewEncoder(os.Stdout)
+	encode := func(record any) {
+		if err := encoder.Encode(record); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to write log record: %s\n", err)
+		}
+	}
+
+	emit := func(line string) {
+		if line != "" {
+			encode(logshipper.Parse(line))
+		}
+	}
+	if audit != "" {
+		parser := &logshipper.AuditParser{Database: audit}
+		if redact != "" {
+			parser.Sensitive = strings.Split(redact, ",")
+		}
+		emit = func(line string) {
+			if record := parser.Line(line); record != nil {
+				encode(record)
+			}
+		}
+		go rotate(ctx, file, maxSize, maxFiles)
+	}
+
+	if err := logshipper.Follow(ctx, file, emit); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to follow %s: %s\n", file, err)
+		os.Exit(1)
+	}
+}
+
+// rotate rotates the audit log until ctx is cancelled. slapd doesn't rotate audit logs itself
+func rotate(ctx context.Context, file string, maxSize int64, maxFiles int) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := logshipper.Rotate(file, maxSize, maxFiles); err != nil {
+				fmt.Fprintf(os.Stderr, "unable to rotate %s: %s\n", file, err)
+			}
+		}
+	}
+}
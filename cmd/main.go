@@ -28,6 +28,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
@@ -64,6 +65,7 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var bindableRoles string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -82,6 +84,9 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&bindableRoles, "bindable-roles", "ClusterRole/view",
+		"Comma-separated roles GroupRoleBindings may grant, as Role/<name> or ClusterRole/<name>. "+
+			"The manager is allowed to bind any role, so this list alone limits what GroupRoleBindings can grant.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -90,6 +95,12 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	bindable, err := controller.ParseBindableRoles(bindableRoles)
+	if err != nil {
+		setupLog.Error(err, "invalid --bindable-roles")
+		os.Exit(1)
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -203,13 +214,82 @@ func main() {
 		os.Exit(1)
 	}
 
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create clientset")
+		os.Exit(1)
+	}
+
 	if err = (&controller.DirectoryReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Recorder:  mgr.GetEventRecorderFor("directory-controller"),
+		Builder:   builder.NewBuilder(mgr.GetScheme()),
+		Clientset: clientset,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Directory")
+		os.Exit(1)
+	}
+	if err = (&controller.LdapBindingReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("directory-controller"),
+		Recorder: mgr.GetEventRecorderFor("ldapbinding-controller"),
 		Builder:  builder.NewBuilder(mgr.GetScheme()),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "Directory")
+		setupLog.Error(err, "unable to create controller", "controller", "LdapBinding")
+		os.Exit(1)
+	}
+	if err = (&controller.DirectoryWatchReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("directorywatch-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DirectoryWatch")
+		os.Exit(1)
+	}
+	if err = (&controller.LdapUserReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("ldapuser-controller"),
+		Builder:  builder.NewBuilder(mgr.GetScheme()),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "LdapUser")
+		os.Exit(1)
+	}
+	if err = (&controller.LdapGroupReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("ldapgroup-controller"),
+		Builder:  builder.NewBuilder(mgr.GetScheme()),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "LdapGroup")
+		os.Exit(1)
+	}
+	if err = (&controller.SudoRuleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("sudorule-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SudoRule")
+		os.Exit(1)
+	}
+	if err = (&controller.LdapClientConfigReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("ldapclientconfig-controller"),
+		Builder:  builder.NewBuilder(mgr.GetScheme()),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "LdapClientConfig")
+		os.Exit(1)
+	}
+	if err = (&controller.GroupRoleBindingReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		Recorder:      mgr.GetEventRecorderFor("grouprolebinding-controller"),
+		Builder:       builder.NewBuilder(mgr.GetScheme()),
+		BindableRoles: bindable,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GroupRoleBinding")
 		os.Exit(1)
 	}
 	// +kubebuilder:scaffold:builder
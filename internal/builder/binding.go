@@ -0,0 +1,37 @@
+package builder
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+// ServiceBindingLdapBinding builds the LdapBinding managing the service account published in the servicebinding.io
+// secret of a directory
+func (builder *Builder) ServiceBindingLdapBinding(directory *v1alpha1.Directory) (*v1alpha1.LdapBinding, error) {
+	spec := directory.Spec.ServiceBinding
+	binding := &v1alpha1.LdapBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      directory.ServiceBindingName(),
+			Namespace: directory.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "openldap",
+				"app.kubernetes.io/instance":  directory.Name,
+				"app.kubernetes.io/component": "directory",
+			},
+		},
+		Spec: v1alpha1.LdapBindingSpec{
+			DirectoryRef: corev1.LocalObjectReference{Name: directory.Name},
+			Database:     spec.Database,
+			Access:       spec.Access,
+			Scope:        spec.Scope,
+		},
+	}
+	if binding.Spec.Access == "" {
+		binding.Spec.Access = v1alpha1.BindingAccessRead
+	}
+
+	return binding, controllerutil.SetControllerReference(directory, binding, builder.Scheme)
+}
@@ -0,0 +1,52 @@
+package builder_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/builder"
+)
+
+var _ = Describe("LdapBinding", func() {
+	var Builder *builder.Builder
+	var directory *v1alpha1.Directory
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Builder = builder.NewBuilder(scheme)
+		directory = &v1alpha1.Directory{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-directory",
+				Namespace: "bar",
+			},
+			Spec: v1alpha1.DirectorySpec{
+				ServiceBinding: &v1alpha1.ServiceBindingSpec{Enabled: true, Database: "example"},
+			},
+		}
+	})
+
+	It("grants read access to the service binding account by default", func() {
+		binding, err := Builder.ServiceBindingLdapBinding(directory)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(binding.Name).To(Equal("foo-directory-servicebinding"))
+		Expect(binding.Namespace).To(Equal("bar"))
+		Expect(binding.Spec.DirectoryRef.Name).To(Equal("foo-directory"))
+		Expect(binding.Spec.Database).To(Equal("example"))
+		Expect(binding.Spec.Access).To(Equal(v1alpha1.BindingAccessRead))
+		Expect(binding.OwnerReferences[0].Name).To(Equal("foo-directory"))
+	})
+
+	It("passes on the configured access and scope", func() {
+		directory.Spec.ServiceBinding.Access = v1alpha1.BindingAccessWrite
+		directory.Spec.ServiceBinding.Scope = "ou=people,dc=example,dc=com"
+		binding, err := Builder.ServiceBindingLdapBinding(directory)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(binding.Spec.Access).To(Equal(v1alpha1.BindingAccessWrite))
+		Expect(binding.Spec.Scope).To(Equal("ou=people,dc=example,dc=com"))
+	})
+})
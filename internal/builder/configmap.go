@@ -0,0 +1,45 @@
+package builder
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+// IDAllocationConfigMap builds the ConfigMap recording the next free POSIX ids of a directory
+func (builder *Builder) IDAllocationConfigMap(directory *v1alpha1.Directory) (*corev1.ConfigMap, error) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      directory.IDAllocationConfigMapName(),
+			Namespace: directory.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "openldap",
+				"app.kubernetes.io/instance":  directory.Name,
+				"app.kubernetes.io/component": "directory",
+			},
+		},
+		Data: map[string]string{},
+	}
+
+	return configMap, controllerutil.SetControllerReference(directory, configMap, builder.Scheme)
+}
+
+// ClientConfigConfigMap builds the ConfigMap holding the client configuration files without credentials
+func (builder *Builder) ClientConfigConfigMap(config *v1alpha1.LdapClientConfig, data map[string]string) (*corev1.ConfigMap, error) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.Name,
+			Namespace: config.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "openldap",
+				"app.kubernetes.io/instance":  config.Spec.DirectoryRef.Name,
+				"app.kubernetes.io/component": "client-config",
+			},
+		},
+		Data: data,
+	}
+
+	return configMap, controllerutil.SetControllerReference(config, configMap, builder.Scheme)
+}
@@ -0,0 +1,84 @@
+package builder
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+// slapindexScript runs slapindex against each suffix passed as an argument
+const slapindexScript = `for suffix in "$@"; do slapindex -F %s -b "$suffix" || exit 1; done`
+
+// SlapindexJob returns a job rebuilding every index of the databases of the instance with the given ordinal.
+// The job mounts the persistent volume of the instance the same way its slapd container does, so the instance
+// must be stopped while the job runs
+func (builder *Builder) SlapindexJob(directory *v1alpha1.Directory, ordinal int32) (*batchv1.Job, error) {
+	if directory.Spec.Storage == nil {
+		return nil, fmt.Errorf("directory %s has no persistent storage", directory.Name)
+	}
+
+	sts, err := builder.DirectoryStatefulSet(directory)
+	if err != nil {
+		return nil, err
+	}
+	slapdContainer := sts.Spec.Template.Spec.Containers[0]
+
+	command := []string{"sh", "-c", fmt.Sprintf(slapindexScript, slapd.ConfigDir), "slapindex"}
+	for _, db := range directory.Spec.SlapdConfig.Databases {
+		command = append(command, db.Suffix)
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      directory.SlapindexJobName(ordinal),
+			Namespace: directory.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "openldap",
+				"app.kubernetes.io/instance":  directory.Name,
+				"app.kubernetes.io/component": "slapindex",
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To(int32(0)),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app.kubernetes.io/name":      "openldap",
+						"app.kubernetes.io/instance":  directory.Name,
+						"app.kubernetes.io/component": "slapindex",
+					},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Volumes: append(sts.Spec.Template.Spec.Volumes, corev1.Volume{
+						Name: v1alpha1.DataVolumeName,
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+								ClaimName: directory.DataClaimName(ordinal),
+							},
+						},
+					}),
+					Containers: []corev1.Container{
+						{
+							Name:            "slapindex",
+							Image:           slapdContainer.Image,
+							ImagePullPolicy: slapdContainer.ImagePullPolicy,
+							Command:         command,
+							Resources:       slapdContainer.Resources,
+							VolumeMounts:    slapdContainer.VolumeMounts,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return job, controllerutil.SetControllerReference(directory, job, builder.Scheme)
+}
@@ -0,0 +1,95 @@
+package builder_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/builder"
+)
+
+var _ = Describe("Job", func() {
+	var scheme *runtime.Scheme
+	var Builder *builder.Builder
+	var directory *v1alpha1.Directory
+	var job *batchv1.Job
+	var err error
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Builder = builder.NewBuilder(scheme)
+		directory = &v1alpha1.Directory{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-directory",
+				Namespace: "bar",
+			},
+			Spec: v1alpha1.DirectorySpec{
+				Image: "test-image:some-tag",
+				Storage: &v1alpha1.StorageSpec{
+					Size: resource.MustParse("1Gi"),
+				},
+				SlapdConfig: &v1alpha1.SlapdConfigSpec{
+					Databases: []v1alpha1.DatabaseSpec{
+						{Name: "example", Suffix: "dc=example,dc=com"},
+						{Name: "other", Suffix: "dc=other,dc=com"},
+					},
+				},
+			},
+		}
+		job, err = Builder.SlapindexJob(directory, 1)
+	})
+
+	Context("create slapindex job", func() {
+		It("doesn't return an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns a job with correct metadata", func() {
+			Expect(job.Name).To(Equal("foo-directory-slapd-slapindex-1"))
+			Expect(job.Namespace).To(Equal("bar"))
+			Expect(job.Labels).To(HaveKeyWithValue("app.kubernetes.io/component", "slapindex"))
+		})
+
+		It("sets controller reference", func() {
+			Expect(job.ObjectMeta.OwnerReferences[0].Name).To(Equal("foo-directory"))
+		})
+
+		It("mounts the volume of the instance", func() {
+			Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "slapd-data",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: "slapd-data-foo-directory-slapd-1",
+					},
+				},
+			}))
+			Expect(job.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "slapd-data",
+				MountPath: "/etc/openldap/slapd.d",
+				SubPath:   "slapd.d",
+			}))
+		})
+
+		It("runs slapindex against every database", func() {
+			container := job.Spec.Template.Spec.Containers[0]
+			Expect(container.Image).To(Equal("test-image:some-tag"))
+			Expect(container.Command[:2]).To(Equal([]string{"sh", "-c"}))
+			Expect(container.Command[2]).To(ContainSubstring("slapindex -F /etc/openldap/slapd.d"))
+			Expect(container.Command[3:]).To(Equal([]string{"slapindex", "dc=example,dc=com", "dc=other,dc=com"}))
+			Expect(job.Spec.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		})
+
+		It("requires persistent storage", func() {
+			directory.Spec.Storage = nil
+			_, err = Builder.SlapindexJob(directory, 1)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
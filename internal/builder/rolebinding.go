@@ -0,0 +1,41 @@
+package builder
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+// GroupRoleBinding builds the RoleBinding granting the role of a GroupRoleBinding to the members of its group
+func (builder *Builder) GroupRoleBinding(binding *v1alpha1.GroupRoleBinding, usernames []string) (*rbacv1.RoleBinding, error) {
+	subjects := []rbacv1.Subject{}
+	for _, username := range usernames {
+		subjects = append(subjects, rbacv1.Subject{
+			Kind:     rbacv1.UserKind,
+			APIGroup: rbacv1.GroupName,
+			Name:     binding.Spec.UsernamePrefix + username,
+		})
+	}
+
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      binding.Name,
+			Namespace: binding.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "openldap",
+				"app.kubernetes.io/instance":  binding.Spec.DirectoryRef.Name,
+				"app.kubernetes.io/component": "group-role-binding",
+			},
+		},
+		Subjects: subjects,
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     string(binding.RoleKind()),
+			Name:     binding.Spec.RoleRef.Name,
+		},
+	}
+
+	return roleBinding, controllerutil.SetControllerReference(binding, roleBinding, builder.Scheme)
+}
@@ -0,0 +1,59 @@
+package builder_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/builder"
+)
+
+var _ = Describe("RoleBinding", func() {
+	var Builder *builder.Builder
+	var binding *v1alpha1.GroupRoleBinding
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Builder = builder.NewBuilder(scheme)
+		binding = &v1alpha1.GroupRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "developers-edit",
+				Namespace: "bar",
+			},
+			Spec: v1alpha1.GroupRoleBindingSpec{
+				DirectoryRef: corev1.LocalObjectReference{Name: "foo-directory"},
+				GroupDN:      "cn=developers,ou=groups,dc=example,dc=com",
+				RoleRef:      v1alpha1.GroupRoleRef{Name: "edit"},
+			},
+		}
+	})
+
+	It("binds the members of the group to the role", func() {
+		roleBinding, err := Builder.GroupRoleBinding(binding, []string{"asmith", "jdoe"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(roleBinding.Name).To(Equal("developers-edit"))
+		Expect(roleBinding.Namespace).To(Equal("bar"))
+		Expect(roleBinding.RoleRef).To(Equal(rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "edit"}))
+		Expect(roleBinding.Subjects).To(Equal([]rbacv1.Subject{
+			{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "asmith"},
+			{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "jdoe"},
+		}))
+		Expect(roleBinding.OwnerReferences).To(HaveLen(1))
+		Expect(roleBinding.OwnerReferences[0].Name).To(Equal("developers-edit"))
+	})
+
+	It("prefixes usernames", func() {
+		binding.Spec.UsernamePrefix = "oidc:"
+		binding.Spec.RoleRef.Kind = v1alpha1.RoleKindRole
+		roleBinding, err := Builder.GroupRoleBinding(binding, []string{"jdoe"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(roleBinding.RoleRef.Kind).To(Equal("Role"))
+		Expect(roleBinding.Subjects[0].Name).To(Equal("oidc:jdoe"))
+	})
+})
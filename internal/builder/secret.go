@@ -32,3 +32,72 @@ func (builder *Builder) DirectorySecret(directory *v1alpha1.Directory) (*corev1.
 
 	return secret, controllerutil.SetControllerReference(directory, secret, builder.Scheme)
 }
+
+func (builder *Builder) SeedPasswordSecret(directory *v1alpha1.Directory) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      directory.SeedSecretName(),
+			Namespace: directory.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "openldap",
+				"app.kubernetes.io/instance":  directory.Name,
+				"app.kubernetes.io/component": "directory",
+			},
+		},
+		Data: map[string][]byte{},
+	}
+
+	return secret, controllerutil.SetControllerReference(directory, secret, builder.Scheme)
+}
+
+func (builder *Builder) BindingSecret(binding *v1alpha1.LdapBinding, data map[string][]byte) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      binding.SecretName(),
+			Namespace: binding.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "openldap",
+				"app.kubernetes.io/instance":  binding.Spec.DirectoryRef.Name,
+				"app.kubernetes.io/component": "binding",
+			},
+		},
+		Data: data,
+	}
+
+	return secret, controllerutil.SetControllerReference(binding, secret, builder.Scheme)
+}
+
+func (builder *Builder) ServiceBindingSecret(directory *v1alpha1.Directory, data map[string][]byte) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      directory.BindingSecretName(),
+			Namespace: directory.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "openldap",
+				"app.kubernetes.io/instance":  directory.Name,
+				"app.kubernetes.io/component": "directory",
+			},
+		},
+		Type: "servicebinding.io/ldap",
+		Data: data,
+	}
+
+	return secret, controllerutil.SetControllerReference(directory, secret, builder.Scheme)
+}
+
+func (builder *Builder) ClientConfigSecret(config *v1alpha1.LdapClientConfig, data map[string][]byte) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.Name,
+			Namespace: config.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "openldap",
+				"app.kubernetes.io/instance":  config.Spec.DirectoryRef.Name,
+				"app.kubernetes.io/component": "client-config",
+			},
+		},
+		Data: data,
+	}
+
+	return secret, controllerutil.SetControllerReference(config, secret, builder.Scheme)
+}
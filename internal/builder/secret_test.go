@@ -67,4 +67,105 @@ var _ = Describe("Secret", func() {
 			Expect(secret.Data["password"]).ToNot(Equal([]byte{}))
 		})
 	})
+
+	Context("create seed password secret", func() {
+		BeforeEach(func() {
+			secret, err = Builder.SeedPasswordSecret(directory)
+		})
+
+		It("doesn't return an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns a secret with correct metadata", func() {
+			Expect(secret.Name).To(Equal("foo-directory-seed-passwords"))
+			Expect(secret.Namespace).To(Equal(directory.Namespace))
+			Expect(secret.Labels).To(Equal(expectedLabels))
+		})
+
+		It("sets controller reference", func() {
+			Expect(secret.ObjectMeta.OwnerReferences[0].Name).To(Equal("foo-directory"))
+		})
+
+		It("doesn't contain any passwords", func() {
+			Expect(secret.Data).To(BeEmpty())
+		})
+	})
+
+	Context("create binding secret", func() {
+		var binding *v1alpha1.LdapBinding
+
+		BeforeEach(func() {
+			binding = &v1alpha1.LdapBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-binding",
+					Namespace: "bar",
+				},
+				Spec: v1alpha1.LdapBindingSpec{
+					DirectoryRef: corev1.LocalObjectReference{Name: "foo-directory"},
+				},
+			}
+			secret, err = Builder.BindingSecret(binding, map[string][]byte{"password": []byte("secret")})
+		})
+
+		It("doesn't return an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("is named after the binding by default", func() {
+			Expect(secret.Name).To(Equal("foo-binding"))
+			Expect(secret.Namespace).To(Equal("bar"))
+		})
+
+		It("uses the secret name from the binding spec", func() {
+			binding.Spec.SecretName = "custom"
+			secret, err = Builder.BindingSecret(binding, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secret.Name).To(Equal("custom"))
+		})
+
+		It("adds expected labels", func() {
+			Expect(secret.Labels).To(Equal(map[string]string{
+				"app.kubernetes.io/name":      "openldap",
+				"app.kubernetes.io/instance":  "foo-directory",
+				"app.kubernetes.io/component": "binding",
+			}))
+		})
+
+		It("sets controller reference to the binding", func() {
+			Expect(secret.ObjectMeta.OwnerReferences[0].Name).To(Equal("foo-binding"))
+		})
+
+		It("contains the given data", func() {
+			Expect(secret.Data).To(Equal(map[string][]byte{"password": []byte("secret")}))
+		})
+	})
+
+	Context("create service binding secret", func() {
+		BeforeEach(func() {
+			secret, err = Builder.ServiceBindingSecret(directory, map[string][]byte{"type": []byte("ldap")})
+		})
+
+		It("doesn't return an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns a secret with correct metadata", func() {
+			Expect(secret.Name).To(Equal("foo-directory-binding"))
+			Expect(secret.Namespace).To(Equal(directory.Namespace))
+			Expect(secret.Labels).To(Equal(expectedLabels))
+		})
+
+		It("uses the servicebinding.io secret type", func() {
+			Expect(secret.Type).To(Equal(corev1.SecretType("servicebinding.io/ldap")))
+		})
+
+		It("sets controller reference", func() {
+			Expect(secret.ObjectMeta.OwnerReferences[0].Name).To(Equal("foo-directory"))
+		})
+
+		It("contains the given data", func() {
+			Expect(secret.Data).To(Equal(map[string][]byte{"type": []byte("ldap")}))
+		})
+	})
 })
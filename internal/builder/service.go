@@ -11,9 +11,85 @@ import (
 )
 
 func (builder *Builder) DirectoryService(directory *v1alpha1.Directory) (*corev1.Service, error) {
-	service := &corev1.Service{
+	service := directoryService(directory, directory.ServiceName())
+
+	service.Spec.Selector = map[string]string{
+		"app.kubernetes.io/instance": directory.Name,
+	}
+	service.Spec.Type = directory.Spec.Service.Type
+	service.Spec.Ports = servicePorts(directory)
+
+	return service, controllerutil.SetControllerReference(directory, service, builder.Scheme)
+}
+
+// DirectoryWriteService selects the instances of a directory which accept writes
+func (builder *Builder) DirectoryWriteService(directory *v1alpha1.Directory) (*corev1.Service, error) {
+	service := directoryService(directory, directory.WriteServiceName())
+
+	service.Spec.Selector = map[string]string{
+		"app.kubernetes.io/instance": directory.Name,
+		v1alpha1.RoleLabel:           v1alpha1.RoleProvider,
+	}
+	service.Spec.Type = corev1.ServiceTypeClusterIP
+	service.Spec.Ports = servicePorts(directory)
+
+	return service, controllerutil.SetControllerReference(directory, service, builder.Scheme)
+}
+
+// DirectoryReadService selects the instances of a directory which serve searches. These are the consumers in
+// provider/consumer topologies and every instance otherwise
+func (builder *Builder) DirectoryReadService(directory *v1alpha1.Directory) (*corev1.Service, error) {
+	service := directoryService(directory, directory.ReadServiceName())
+
+	service.Spec.Selector = map[string]string{
+		"app.kubernetes.io/instance": directory.Name,
+	}
+	if directory.Replicated() && directory.ReplicationTopology() == v1alpha1.ReplicationTopologyProviderConsumer {
+		service.Spec.Selector[v1alpha1.RoleLabel] = v1alpha1.RoleConsumer
+	}
+	service.Spec.Type = corev1.ServiceTypeClusterIP
+	service.Spec.Ports = servicePorts(directory)
+
+	return service, controllerutil.SetControllerReference(directory, service, builder.Scheme)
+}
+
+// DirectoryHeadlessService governs the directory statefulset, giving each instance a stable DNS name
+func (builder *Builder) DirectoryHeadlessService(directory *v1alpha1.Directory) (*corev1.Service, error) {
+	service := directoryService(directory, directory.HeadlessServiceName())
+
+	service.Spec.Selector = map[string]string{
+		"app.kubernetes.io/instance": directory.Name,
+	}
+	service.Spec.Type = corev1.ServiceTypeClusterIP
+	service.Spec.ClusterIP = corev1.ClusterIPNone
+	service.Spec.PublishNotReadyAddresses = true
+	service.Spec.Ports = []corev1.ServicePort{
+		{
+			Name:        "ldap",
+			Protocol:    corev1.ProtocolTCP,
+			Port:        3389,
+			TargetPort:  intstr.FromInt(3389),
+			AppProtocol: ptr.To("ldap"),
+		},
+	}
+
+	if directory.Spec.TLS != nil {
+		service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{
+			Name:        "ldaps",
+			Protocol:    corev1.ProtocolTCP,
+			Port:        3636,
+			TargetPort:  intstr.FromInt(3636),
+			AppProtocol: ptr.To("ldaps"),
+		})
+	}
+
+	return service, controllerutil.SetControllerReference(directory, service, builder.Scheme)
+}
+
+func directoryService(directory *v1alpha1.Directory, name string) *corev1.Service {
+	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      directory.ServiceName(),
+			Name:      name,
 			Namespace: directory.Namespace,
 			Labels: map[string]string{
 				"app.kubernetes.io/name":      "openldap",
@@ -22,12 +98,10 @@ func (builder *Builder) DirectoryService(directory *v1alpha1.Directory) (*corev1
 			},
 		},
 	}
+}
 
-	service.Spec.Selector = map[string]string{
-		"app.kubernetes.io/instance": directory.Name,
-	}
-	service.Spec.Type = directory.Spec.Service.Type
-	service.Spec.Ports = []corev1.ServicePort{
+func servicePorts(directory *v1alpha1.Directory) []corev1.ServicePort {
+	ports := []corev1.ServicePort{
 		{
 			Name:        "ldap",
 			Protocol:    corev1.ProtocolTCP,
@@ -37,5 +111,15 @@ func (builder *Builder) DirectoryService(directory *v1alpha1.Directory) (*corev1
 		},
 	}
 
-	return service, controllerutil.SetControllerReference(directory, service, builder.Scheme)
+	if directory.Spec.TLS != nil {
+		ports = append(ports, corev1.ServicePort{
+			Name:        "ldaps",
+			Protocol:    corev1.ProtocolTCP,
+			Port:        636,
+			TargetPort:  intstr.FromInt(3636),
+			AppProtocol: ptr.To("ldaps"),
+		})
+	}
+
+	return ports
 }
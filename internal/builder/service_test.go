@@ -85,4 +85,102 @@ var _ = Describe("DirectoryService", func() {
 			Expect(service.Spec.Type).To(Equal(directory.Spec.Service.Type))
 		})
 	})
+
+	Context("creates directory service with tls", func() {
+		BeforeEach(func() {
+			directory.Spec.TLS = &v1alpha1.DirectoryTLSSpec{
+				SecretName: "foo-tls",
+			}
+			service, err = Builder.DirectoryService(directory)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("adds the ldaps port", func() {
+			Expect(service.Spec.Ports).To(HaveLen(2))
+			Expect(service.Spec.Ports[1]).To(Equal(corev1.ServicePort{
+				Name:        "ldaps",
+				Protocol:    corev1.ProtocolTCP,
+				Port:        636,
+				TargetPort:  intstr.FromInt(3636),
+				AppProtocol: ptr.To("ldaps"),
+			}))
+		})
+	})
+
+	Context("creates directory write service", func() {
+		BeforeEach(func() {
+			service, err = Builder.DirectoryWriteService(directory)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("contains correct metadata", func() {
+			Expect(service.Name).To(Equal("foo-directory-write"))
+			Expect(service.Labels).To(Equal(expectedLabels))
+		})
+
+		It("selects providers", func() {
+			Expect(service.Spec.Selector).To(Equal(map[string]string{
+				"app.kubernetes.io/instance": "foo-directory",
+				"openldap.my.domain/role":    "provider",
+			}))
+		})
+
+		It("is a cluster ip service", func() {
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+		})
+	})
+
+	Context("creates directory read service", func() {
+		It("selects every instance of multi-provider directories", func() {
+			directory.Spec.Replicas = 3
+			service, err = Builder.DirectoryReadService(directory)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(service.Name).To(Equal("foo-directory-read"))
+			Expect(service.Spec.Selector).To(Equal(map[string]string{
+				"app.kubernetes.io/instance": "foo-directory",
+			}))
+		})
+
+		It("selects consumers of provider/consumer directories", func() {
+			directory.Spec.Replicas = 3
+			directory.Spec.Replication = &v1alpha1.ReplicationSpec{
+				Topology: v1alpha1.ReplicationTopologyProviderConsumer,
+			}
+			service, err = Builder.DirectoryReadService(directory)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(service.Spec.Selector).To(Equal(map[string]string{
+				"app.kubernetes.io/instance": "foo-directory",
+				"openldap.my.domain/role":    "consumer",
+			}))
+		})
+	})
+
+	Context("creates directory headless service", func() {
+		BeforeEach(func() {
+			service, err = Builder.DirectoryHeadlessService(directory)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("contains correct metadata", func() {
+			Expect(service.Name).To(Equal("foo-directory-headless"))
+			Expect(service.Labels).To(Equal(expectedLabels))
+		})
+
+		It("doesn't allocate a cluster ip", func() {
+			Expect(service.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+			Expect(service.Spec.PublishNotReadyAddresses).To(BeTrue())
+		})
+
+		It("exposes the slapd port", func() {
+			Expect(service.Spec.Ports).To(Equal([]corev1.ServicePort{
+				{
+					Name:        "ldap",
+					Protocol:    corev1.ProtocolTCP,
+					Port:        3389,
+					TargetPort:  intstr.FromInt(3389),
+					AppProtocol: ptr.To("ldap"),
+				},
+			}))
+		})
+	})
 })
@@ -1,13 +1,19 @@
 package builder
 
 import (
+	"fmt"
+	"path"
+	"strings"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
 )
 
 func (builder *Builder) DirectoryStatefulSet(directory *v1alpha1.Directory) (*appsv1.StatefulSet, error) {
@@ -27,7 +33,7 @@ func (builder *Builder) DirectoryStatefulSet(directory *v1alpha1.Directory) (*ap
 					"app.kubernetes.io/instance": directory.Name,
 				},
 			},
-			ServiceName: directory.ServiceName(),
+			ServiceName: directory.HeadlessServiceName(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{
@@ -41,15 +47,46 @@ func (builder *Builder) DirectoryStatefulSet(directory *v1alpha1.Directory) (*ap
 	}
 
 	var replicas int32 = 1
+	if directory.Spec.Replicas > 0 {
+		replicas = directory.Spec.Replicas
+	}
 	sts.Spec.Replicas = ptr.To(replicas)
 
-	sts.Spec.Template.Spec.Volumes = []corev1.Volume{
-		{
-			Name: "slapd-data-dir",
-			VolumeSource: corev1.VolumeSource{
-				EmptyDir: &corev1.EmptyDirVolumeSource{},
+	configMount := corev1.VolumeMount{
+		Name:      "slapd-data-dir",
+		MountPath: slapd.ConfigDir,
+	}
+	if directory.Spec.Storage != nil {
+		sts.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: v1alpha1.DataVolumeName,
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					StorageClassName: directory.Spec.Storage.StorageClassName,
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: directory.Spec.Storage.Size,
+						},
+					},
+				},
 			},
-		},
+		}
+		configMount = corev1.VolumeMount{
+			Name:      v1alpha1.DataVolumeName,
+			MountPath: slapd.ConfigDir,
+			SubPath:   "slapd.d",
+		}
+	} else {
+		sts.Spec.Template.Spec.Volumes = []corev1.Volume{
+			{
+				Name: "slapd-data-dir",
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			},
+		}
 	}
 
 	sts.Spec.Template.Spec.Containers = []corev1.Container{
@@ -71,7 +108,7 @@ func (builder *Builder) DirectoryStatefulSet(directory *v1alpha1.Directory) (*ap
 				},
 				{
 					Name:  "OPENLDAP_SCHEMAS",
-					Value: directory.Spec.SlapdConfig.Schemas.Join(),
+					Value: directory.Spec.SlapdConfig.Schemas.Bundled().Join(),
 				},
 			},
 			Ports: []corev1.ContainerPort{
@@ -81,13 +118,171 @@ func (builder *Builder) DirectoryStatefulSet(directory *v1alpha1.Directory) (*ap
 					Protocol:      corev1.ProtocolTCP,
 				},
 			},
+			VolumeMounts: []corev1.VolumeMount{configMount},
+		},
+	}
+
+	if directory.Spec.Resources != nil {
+		sts.Spec.Template.Spec.Containers[0].Resources = *directory.Spec.Resources
+	}
+
+	if directory.Spec.TLS != nil {
+		sts.Spec.Template.Spec.Volumes = append(sts.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: "slapd-tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: directory.Spec.TLS.SecretName,
+				},
+			},
+		})
+		sts.Spec.Template.Spec.Containers[0].VolumeMounts = append(sts.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      "slapd-tls",
+			MountPath: slapd.TLSDir,
+			ReadOnly:  true,
+		})
+		sts.Spec.Template.Spec.Containers[0].Ports = append(sts.Spec.Template.Spec.Containers[0].Ports, corev1.ContainerPort{
+			Name:          "ldaps",
+			ContainerPort: 636,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+
+	if directory.Spec.Replication != nil && directory.Spec.Replication.External != nil &&
+		directory.Spec.Replication.External.CASecretRef != nil {
+		external := directory.Spec.Replication.External
+		sts.Spec.Template.Spec.Volumes = append(sts.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: "external-ca",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: external.CASecretRef.Name,
+					Items: []corev1.KeyToPath{
+						{Key: external.CASecretRef.Key, Path: "ca.crt"},
+					},
+				},
+			},
+		})
+		sts.Spec.Template.Spec.Containers[0].VolumeMounts = append(sts.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      "external-ca",
+			MountPath: slapd.ExternalCADir,
+			ReadOnly:  true,
+		})
+	}
+
+	for _, peer := range directory.ReplicationPeers() {
+		if peer.CASecretRef == nil {
+			continue
+		}
+		volumeName := fmt.Sprintf("peer-ca-%s", peer.Name)
+		sts.Spec.Template.Spec.Volumes = append(sts.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: peer.CASecretRef.Name,
+					Items: []corev1.KeyToPath{
+						{Key: peer.CASecretRef.Key, Path: "ca.crt"},
+					},
+				},
+			},
+		})
+		sts.Spec.Template.Spec.Containers[0].VolumeMounts = append(sts.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: fmt.Sprintf("%s/%s", slapd.PeerCADir, peer.Name),
+			ReadOnly:  true,
+		})
+	}
+
+	if directory.JSONLogging() || len(directory.AuditedDatabases()) > 0 {
+		sts.Spec.Template.Spec.Volumes = append(sts.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: "slapd-logs",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+		sts.Spec.Template.Spec.Containers[0].VolumeMounts = append(sts.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      "slapd-logs",
+			MountPath: slapd.LogDir,
+		})
+	}
+
+	if directory.JSONLogging() {
+		sts.Spec.Template.Spec.Containers = append(sts.Spec.Template.Spec.Containers, corev1.Container{
+			Name:            "log-shipper",
+			Image:           directory.ShipperImage(),
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{"/logshipper", fmt.Sprintf("--file=%s", slapd.LogFile())},
 			VolumeMounts: []corev1.VolumeMount{
 				{
-					Name:      "slapd-data-dir",
-					MountPath: "/etc/openldap/slapd.d",
+					Name:      "slapd-logs",
+					MountPath: slapd.LogDir,
+					ReadOnly:  true,
 				},
 			},
-		},
+		})
+	}
+
+	for _, db := range directory.AuditedDatabases() {
+		maxSize := resource.MustParse("10Mi")
+		if db.Audit.MaxSize != nil {
+			maxSize = *db.Audit.MaxSize
+		}
+		maxFiles := db.Audit.MaxFiles
+		if maxFiles < 1 {
+			maxFiles = 3
+		}
+		command := []string{
+			"/logshipper",
+			fmt.Sprintf("--file=%s", slapd.AuditLogFile(&db)),
+			fmt.Sprintf("--audit=%s", db.Name),
+			fmt.Sprintf("--max-size=%d", maxSize.Value()),
+			fmt.Sprintf("--max-files=%d", maxFiles),
+		}
+		if len(db.Audit.RedactAttributes) > 0 {
+			command = append(command, fmt.Sprintf("--redact=%s", strings.Join(db.Audit.RedactAttributes, ",")))
+		}
+		// The audit sidecar rotates the audit log, so it mounts the log volume read-write
+		sts.Spec.Template.Spec.Containers = append(sts.Spec.Template.Spec.Containers, corev1.Container{
+			Name:            fmt.Sprintf("audit-%s", db.Name),
+			Image:           directory.ShipperImage(),
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         command,
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      "slapd-logs",
+					MountPath: slapd.LogDir,
+				},
+			},
+		})
+	}
+
+	// Databases are kept on a volume of their own each, or on subdirectories of the persistent volume of the
+	// instance when it has one
+	for _, db := range directory.Spec.SlapdConfig.Databases {
+		volumeName := fmt.Sprintf("db-%s", db.Name)
+		subPath := ""
+		if directory.Spec.Storage != nil {
+			volumeName = v1alpha1.DataVolumeName
+			subPath = db.Name
+		} else {
+			sts.Spec.Template.Spec.Volumes = append(sts.Spec.Template.Spec.Volumes, corev1.Volume{
+				Name: volumeName,
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			})
+		}
+		sts.Spec.Template.Spec.Containers[0].VolumeMounts = append(sts.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: slapd.DatabaseDirectory(&db),
+			SubPath:   subPath,
+		})
+		if (directory.Replicated() || len(directory.ReplicationPeers()) > 0) &&
+			directory.ReplicationMode() == v1alpha1.ReplicationModeDeltaSyncrepl {
+			sts.Spec.Template.Spec.Containers[0].VolumeMounts = append(sts.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+				Name:      volumeName,
+				MountPath: slapd.AccessLogDirectory(&db),
+				SubPath:   path.Join(subPath, "accesslog"),
+			})
+		}
 	}
 
 	return sts, controllerutil.SetControllerReference(directory, sts, builder.Scheme)
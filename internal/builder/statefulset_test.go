@@ -6,8 +6,10 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 
 	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
 	"github.com/paddyoneill/openldap-operator/internal/builder"
@@ -78,7 +80,7 @@ var _ = Describe("Statefulset", func() {
 		})
 
 		It("sets the correct service name", func() {
-			Expect(sts.Spec.ServiceName).To(Equal("foo-directory"))
+			Expect(sts.Spec.ServiceName).To(Equal("foo-directory-headless"))
 		})
 
 		It("sets correct volumes", func() {
@@ -113,4 +115,323 @@ var _ = Describe("Statefulset", func() {
 			}))
 		})
 	})
+
+	Context("create directory statefulset with databases", func() {
+		BeforeEach(func() {
+			directory.Spec.SlapdConfig.Databases = []v1alpha1.DatabaseSpec{
+				{
+					Name:   "example",
+					Suffix: "dc=example,dc=com",
+				},
+			}
+			sts, err = Builder.DirectoryStatefulSet(directory)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("adds a volume for each database", func() {
+			Expect(sts.Spec.Template.Spec.Volumes).To(HaveLen(2))
+			Expect(sts.Spec.Template.Spec.Volumes[1]).To(Equal(corev1.Volume{
+				Name: "db-example",
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			}))
+		})
+
+		It("mounts the database volume at the database directory", func() {
+			Expect(sts.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "db-example",
+				MountPath: "/var/lib/openldap/example",
+			}))
+		})
+	})
+
+	Context("create directory statefulset with tls", func() {
+		BeforeEach(func() {
+			directory.Spec.TLS = &v1alpha1.DirectoryTLSSpec{
+				SecretName: "foo-tls",
+			}
+			sts, err = Builder.DirectoryStatefulSet(directory)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("mounts the tls secret", func() {
+			Expect(sts.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "slapd-tls",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: "foo-tls",
+					},
+				},
+			}))
+			Expect(sts.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "slapd-tls",
+				MountPath: "/etc/openldap/tls",
+				ReadOnly:  true,
+			}))
+		})
+
+		It("exposes the ldaps port", func() {
+			Expect(sts.Spec.Template.Spec.Containers[0].Ports).To(ContainElement(corev1.ContainerPort{
+				Name:          "ldaps",
+				ContainerPort: 636,
+				Protocol:      corev1.ProtocolTCP,
+			}))
+		})
+	})
+
+	Context("create replicated directory statefulset", func() {
+		BeforeEach(func() {
+			directory.Spec.Replicas = 3
+			directory.Spec.Replication = &v1alpha1.ReplicationSpec{
+				Mode: v1alpha1.ReplicationModeDeltaSyncrepl,
+			}
+			directory.Spec.SlapdConfig.Databases = []v1alpha1.DatabaseSpec{
+				{
+					Name:   "example",
+					Suffix: "dc=example,dc=com",
+				},
+			}
+			sts, err = Builder.DirectoryStatefulSet(directory)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("sets the number of replicas from the spec", func() {
+			Expect(*sts.Spec.Replicas).To(Equal(int32(3)))
+		})
+
+		It("mounts a directory for the accesslog database", func() {
+			Expect(sts.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "db-example",
+				MountPath: "/var/lib/openldap/example.accesslog",
+				SubPath:   "accesslog",
+			}))
+		})
+	})
+
+	Context("create directory statefulset replicating from an external provider", func() {
+		BeforeEach(func() {
+			directory.Spec.Replication = &v1alpha1.ReplicationSpec{
+				External: &v1alpha1.ExternalProviderSpec{
+					URI: "ldaps://ldap.example.com",
+					CASecretRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "external-ca"},
+						Key:                  "ca.pem",
+					},
+				},
+			}
+			sts, err = Builder.DirectoryStatefulSet(directory)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("mounts the CA certificate of the provider", func() {
+			Expect(sts.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "external-ca",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: "external-ca",
+						Items:      []corev1.KeyToPath{{Key: "ca.pem", Path: "ca.crt"}},
+					},
+				},
+			}))
+			Expect(sts.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "external-ca",
+				MountPath: "/etc/openldap/external-ca",
+				ReadOnly:  true,
+			}))
+		})
+	})
+
+	Context("create directory statefulset with replication peers", func() {
+		BeforeEach(func() {
+			directory.Spec.Replication = &v1alpha1.ReplicationSpec{
+				Peers: []v1alpha1.ReplicationPeerSpec{
+					{
+						Name:     "eu-west",
+						URI:      "ldaps://ldap.eu-west.example.com",
+						ServerID: 101,
+						CASecretRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "eu-west-ca"},
+							Key:                  "ca.crt",
+						},
+					},
+					{
+						Name:     "us-east",
+						URI:      "ldap://ldap.us-east.example.com",
+						ServerID: 201,
+					},
+				},
+			}
+			sts, err = Builder.DirectoryStatefulSet(directory)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("mounts the CA certificate of each peer", func() {
+			Expect(sts.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "peer-ca-eu-west",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: "eu-west-ca",
+						Items:      []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+					},
+				},
+			}))
+			Expect(sts.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "peer-ca-eu-west",
+				MountPath: "/etc/openldap/peer-ca/eu-west",
+				ReadOnly:  true,
+			}))
+		})
+
+		It("doesn't add volumes for peers without a CA certificate", func() {
+			Expect(sts.Spec.Template.Spec.Volumes).ToNot(ContainElement(HaveField("Name", "peer-ca-us-east")))
+		})
+	})
+
+	Context("create directory statefulset with resources", func() {
+		BeforeEach(func() {
+			directory.Spec.Resources = &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			}
+			sts, err = Builder.DirectoryStatefulSet(directory)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("sets the resources of the slapd container", func() {
+			Expect(sts.Spec.Template.Spec.Containers[0].Resources).To(Equal(*directory.Spec.Resources))
+		})
+	})
+
+	Context("create directory statefulset shipping logs as json", func() {
+		BeforeEach(func() {
+			directory.Spec.Logging = &v1alpha1.LoggingSpec{
+				Format:       v1alpha1.LogFormatJSON,
+				ShipperImage: "operator-image:some-tag",
+			}
+			sts, err = Builder.DirectoryStatefulSet(directory)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("mounts a log volume in the slapd container", func() {
+			Expect(sts.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "slapd-logs",
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			}))
+			Expect(sts.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "slapd-logs",
+				MountPath: "/var/log/slapd",
+			}))
+		})
+
+		It("adds the log shipping sidecar", func() {
+			Expect(sts.Spec.Template.Spec.Containers).To(HaveLen(2))
+			shipper := sts.Spec.Template.Spec.Containers[1]
+			Expect(shipper.Name).To(Equal("log-shipper"))
+			Expect(shipper.Image).To(Equal("operator-image:some-tag"))
+			Expect(shipper.Command).To(Equal([]string{"/logshipper", "--file=/var/log/slapd/slapd.log"}))
+			Expect(shipper.VolumeMounts).To(Equal([]corev1.VolumeMount{
+				{
+					Name:      "slapd-logs",
+					MountPath: "/var/log/slapd",
+					ReadOnly:  true,
+				},
+			}))
+		})
+	})
+
+	Context("create directory statefulset with an audited database", func() {
+		BeforeEach(func() {
+			directory.Spec.Logging = &v1alpha1.LoggingSpec{ShipperImage: "operator-image:some-tag"}
+			directory.Spec.SlapdConfig.Databases = []v1alpha1.DatabaseSpec{
+				{
+					Name:   "example",
+					Suffix: "dc=example,dc=com",
+					Audit: &v1alpha1.AuditSpec{
+						MaxSize:  ptr.To(resource.MustParse("1Mi")),
+						MaxFiles: 5,
+					},
+				},
+			}
+			sts, err = Builder.DirectoryStatefulSet(directory)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("mounts the log volume in the slapd container", func() {
+			Expect(sts.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "slapd-logs",
+				MountPath: "/var/log/slapd",
+			}))
+		})
+
+		It("passes the attributes to redact to the audit sidecar", func() {
+			directory.Spec.SlapdConfig.Databases[0].Audit.RedactAttributes = []string{"mail", "carLicense"}
+			sts, err = Builder.DirectoryStatefulSet(directory)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sts.Spec.Template.Spec.Containers[1].Command).To(ContainElement("--redact=mail,carLicense"))
+		})
+
+		It("adds an audit sidecar for the database", func() {
+			Expect(sts.Spec.Template.Spec.Containers).To(HaveLen(2))
+			audit := sts.Spec.Template.Spec.Containers[1]
+			Expect(audit.Name).To(Equal("audit-example"))
+			Expect(audit.Image).To(Equal("operator-image:some-tag"))
+			Expect(audit.Command).To(Equal([]string{
+				"/logshipper",
+				"--file=/var/log/slapd/audit-example.ldif",
+				"--audit=example",
+				"--max-size=1048576",
+				"--max-files=5",
+			}))
+			Expect(audit.VolumeMounts).To(Equal([]corev1.VolumeMount{
+				{
+					Name:      "slapd-logs",
+					MountPath: "/var/log/slapd",
+				},
+			}))
+		})
+	})
+
+	Context("create directory statefulset with persistent storage", func() {
+		BeforeEach(func() {
+			directory.Spec.Storage = &v1alpha1.StorageSpec{
+				Size:             resource.MustParse("10Gi"),
+				StorageClassName: ptr.To("fast"),
+			}
+			directory.Spec.SlapdConfig.Databases = []v1alpha1.DatabaseSpec{
+				{
+					Name:   "example",
+					Suffix: "dc=example,dc=com",
+				},
+			}
+			sts, err = Builder.DirectoryStatefulSet(directory)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("claims a volume for each instance", func() {
+			Expect(sts.Spec.VolumeClaimTemplates).To(HaveLen(1))
+			claim := sts.Spec.VolumeClaimTemplates[0]
+			Expect(claim.Name).To(Equal("slapd-data"))
+			Expect(claim.Spec.StorageClassName).To(Equal(ptr.To("fast")))
+			Expect(claim.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("10Gi")))
+		})
+
+		It("keeps the config and databases on the claimed volume", func() {
+			Expect(sts.Spec.Template.Spec.Volumes).To(BeEmpty())
+			Expect(sts.Spec.Template.Spec.Containers[0].VolumeMounts).To(Equal([]corev1.VolumeMount{
+				{
+					Name:      "slapd-data",
+					MountPath: "/etc/openldap/slapd.d",
+					SubPath:   "slapd.d",
+				},
+				{
+					Name:      "slapd-data",
+					MountPath: "/var/lib/openldap/example",
+					SubPath:   "example",
+				},
+			}))
+		})
+	})
 })
@@ -0,0 +1,119 @@
+package clientconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// LdapConf is the key of the OpenLDAP client configuration
+	LdapConf = "ldap.conf"
+	// SSSDConf is the key of the sssd configuration
+	SSSDConf = "sssd.conf"
+	// NslcdConf is the key of the nslcd configuration
+	NslcdConf = "nslcd.conf"
+	// DexConnector is the key of the Dex connector
+	DexConnector = "dex-connector.yaml"
+)
+
+// Connection holds everything a client needs to reach the directory
+type Connection struct {
+	// URIs of the directory, tried in order
+	URIs []string
+	// Base DN of searches
+	Base string
+	// DN to bind as, empty for anonymous binds
+	BindDN string
+	// Password of BindDN
+	BindPassword string
+	// Path of the CA certificate on the client, empty if the directory doesn't use TLS
+	CACertFile string
+	// CA certificate for clients embedding it rather than reading CACertFile
+	CACert []byte
+	// Base DN of user entries
+	PeopleBase string
+	// Base DN of group entries
+	GroupsBase string
+	// Name of the sssd domain
+	Domain string
+	// Base DN of sudoRole entries, empty if the directory doesn't load the sudo schema
+	SudoBase string
+	// Whether users have an sshPublicKey attribute
+	SSHPublicKeys bool
+}
+
+// LdapConfig renders ldap.conf, which holds no credentials
+func LdapConfig(conn *Connection) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "URI %s\n", strings.Join(conn.URIs, " "))
+	fmt.Fprintf(&b, "BASE %s\n", conn.Base)
+	if conn.CACertFile != "" {
+		fmt.Fprintf(&b, "TLS_CACERT %s\n", conn.CACertFile)
+		b.WriteString("TLS_REQCERT demand\n")
+	}
+	return b.String()
+}
+
+// SSSDConfig renders sssd.conf. sssd refuses to start unless the file is owned by root with mode 0600
+func SSSDConfig(conn *Connection) string {
+	services := []string{"nss", "pam"}
+	if conn.SudoBase != "" {
+		services = append(services, "sudo")
+	}
+	if conn.SSHPublicKeys {
+		services = append(services, "ssh")
+	}
+
+	var b strings.Builder
+	b.WriteString("[sssd]\n")
+	b.WriteString("config_file_version = 2\n")
+	fmt.Fprintf(&b, "services = %s\n", strings.Join(services, ", "))
+	fmt.Fprintf(&b, "domains = %s\n", conn.Domain)
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "[domain/%s]\n", conn.Domain)
+	b.WriteString("id_provider = ldap\n")
+	b.WriteString("auth_provider = ldap\n")
+	b.WriteString("chpass_provider = ldap\n")
+	if conn.SudoBase != "" {
+		b.WriteString("sudo_provider = ldap\n")
+		fmt.Fprintf(&b, "ldap_sudo_search_base = %s\n", conn.SudoBase)
+	}
+	fmt.Fprintf(&b, "ldap_uri = %s\n", strings.Join(conn.URIs, ", "))
+	fmt.Fprintf(&b, "ldap_search_base = %s\n", conn.Base)
+	b.WriteString("ldap_schema = rfc2307\n")
+	if conn.BindDN != "" {
+		fmt.Fprintf(&b, "ldap_default_bind_dn = %s\n", conn.BindDN)
+		b.WriteString("ldap_default_authtok_type = password\n")
+		fmt.Fprintf(&b, "ldap_default_authtok = %s\n", conn.BindPassword)
+	}
+	if conn.CACertFile != "" {
+		fmt.Fprintf(&b, "ldap_tls_cacert = %s\n", conn.CACertFile)
+		b.WriteString("ldap_tls_reqcert = demand\n")
+	}
+	if conn.SSHPublicKeys {
+		b.WriteString("ldap_user_ssh_public_key = sshPublicKey\n")
+	}
+	b.WriteString("cache_credentials = true\n")
+	b.WriteString("enumerate = false\n")
+	return b.String()
+}
+
+// NslcdConfig renders nslcd.conf. nslcd expects the file to be readable only by root
+func NslcdConfig(conn *Connection) string {
+	var b strings.Builder
+	b.WriteString("uid nslcd\n")
+	b.WriteString("gid nslcd\n")
+	for _, uri := range conn.URIs {
+		fmt.Fprintf(&b, "uri %s\n", uri)
+	}
+	fmt.Fprintf(&b, "base %s\n", conn.Base)
+	if conn.BindDN != "" {
+		fmt.Fprintf(&b, "binddn %s\n", conn.BindDN)
+		fmt.Fprintf(&b, "bindpw %s\n", conn.BindPassword)
+	}
+	if conn.CACertFile != "" {
+		fmt.Fprintf(&b, "tls_cacertfile %s\n", conn.CACertFile)
+		b.WriteString("tls_reqcert demand\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,13 @@
+package clientconfig_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestClientconfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Clientconfig Suite")
+}
@@ -0,0 +1,130 @@
+package clientconfig_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/paddyoneill/openldap-operator/internal/clientconfig"
+)
+
+var _ = Describe("Clientconfig", func() {
+	var conn *clientconfig.Connection
+
+	BeforeEach(func() {
+		conn = &clientconfig.Connection{
+			URIs:   []string{"ldaps://ldap-a.example.com:636", "ldaps://ldap-b.example.com:636"},
+			Base:   "dc=example,dc=com",
+			Domain: "example",
+		}
+	})
+
+	Context("ldap.conf", func() {
+		It("lists all URIs", func() {
+			Expect(clientconfig.LdapConfig(conn)).To(Equal(
+				"URI ldaps://ldap-a.example.com:636 ldaps://ldap-b.example.com:636\n" +
+					"BASE dc=example,dc=com\n"))
+		})
+
+		It("requires a certificate signed by the CA when TLS is used", func() {
+			conn.CACertFile = "/etc/ldap/ca.crt"
+			Expect(clientconfig.LdapConfig(conn)).To(ContainSubstring("TLS_CACERT /etc/ldap/ca.crt\nTLS_REQCERT demand\n"))
+		})
+
+		It("never contains credentials", func() {
+			conn.BindDN = "cn=hosts,ou=services,dc=example,dc=com"
+			conn.BindPassword = "secret"
+			Expect(clientconfig.LdapConfig(conn)).NotTo(ContainSubstring("secret"))
+		})
+	})
+
+	Context("sssd.conf", func() {
+		It("binds anonymously without a bind DN", func() {
+			config := clientconfig.SSSDConfig(conn)
+			Expect(config).To(ContainSubstring("services = nss, pam\n"))
+			Expect(config).To(ContainSubstring("[domain/example]\n"))
+			Expect(config).To(ContainSubstring("ldap_uri = ldaps://ldap-a.example.com:636, ldaps://ldap-b.example.com:636\n"))
+			Expect(config).To(ContainSubstring("ldap_search_base = dc=example,dc=com\n"))
+			Expect(config).NotTo(ContainSubstring("ldap_default_bind_dn"))
+		})
+
+		It("binds with the credentials of the binding", func() {
+			conn.BindDN = "cn=hosts,ou=services,dc=example,dc=com"
+			conn.BindPassword = "secret"
+			config := clientconfig.SSSDConfig(conn)
+			Expect(config).To(ContainSubstring("ldap_default_bind_dn = cn=hosts,ou=services,dc=example,dc=com\n"))
+			Expect(config).To(ContainSubstring("ldap_default_authtok = secret\n"))
+		})
+
+		It("enables sudo and ssh when the schemas are loaded", func() {
+			conn.SudoBase = "ou=SUDOers,dc=example,dc=com"
+			conn.SSHPublicKeys = true
+			config := clientconfig.SSSDConfig(conn)
+			Expect(config).To(ContainSubstring("services = nss, pam, sudo, ssh\n"))
+			Expect(config).To(ContainSubstring("ldap_sudo_search_base = ou=SUDOers,dc=example,dc=com\n"))
+			Expect(config).To(ContainSubstring("ldap_user_ssh_public_key = sshPublicKey\n"))
+		})
+	})
+
+	Context("nslcd.conf", func() {
+		It("lists one URI per line", func() {
+			conn.BindDN = "cn=hosts,ou=services,dc=example,dc=com"
+			conn.BindPassword = "secret"
+			conn.CACertFile = "/etc/ldap/ca.crt"
+			Expect(clientconfig.NslcdConfig(conn)).To(Equal(
+				"uid nslcd\n" +
+					"gid nslcd\n" +
+					"uri ldaps://ldap-a.example.com:636\n" +
+					"uri ldaps://ldap-b.example.com:636\n" +
+					"base dc=example,dc=com\n" +
+					"binddn cn=hosts,ou=services,dc=example,dc=com\n" +
+					"bindpw secret\n" +
+					"tls_cacertfile /etc/ldap/ca.crt\n" +
+					"tls_reqcert demand\n"))
+		})
+	})
+
+	Context("Dex connector", func() {
+		var dex *clientconfig.Dex
+
+		BeforeEach(func() {
+			conn.PeopleBase = "ou=people,dc=example,dc=com"
+			conn.GroupsBase = "ou=groups,dc=example,dc=com"
+			dex = &clientconfig.Dex{ID: "ldap", Name: "OpenLDAP", UsernamePrompt: "Username"}
+		})
+
+		It("connects to the first URI", func() {
+			conn.CACert = []byte("certificate")
+			config, err := clientconfig.DexConnectorConfig(conn, dex)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(ContainSubstring("host: ldap-a.example.com:636\n"))
+			Expect(config).To(ContainSubstring("insecureNoSSL: false\n"))
+			Expect(config).To(ContainSubstring("rootCAData: Y2VydGlmaWNhdGU=\n"))
+		})
+
+		It("disables TLS for plain LDAP URIs", func() {
+			conn.URIs = []string{"ldap://ldap.example.com:389"}
+			config, err := clientconfig.DexConnectorConfig(conn, dex)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(ContainSubstring("insecureNoSSL: true\n"))
+			Expect(config).NotTo(ContainSubstring("rootCAData"))
+		})
+
+		It("searches users and groups below their organizational units", func() {
+			conn.BindDN = "cn=dex,ou=services,dc=example,dc=com"
+			conn.BindPassword = "secret"
+			config, err := clientconfig.DexConnectorConfig(conn, dex)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(ContainSubstring("bindDN: cn=dex,ou=services,dc=example,dc=com\n"))
+			Expect(config).To(ContainSubstring("bindPW: secret\n"))
+			Expect(config).To(ContainSubstring("baseDN: ou=people,dc=example,dc=com\n"))
+			Expect(config).To(ContainSubstring("baseDN: ou=groups,dc=example,dc=com\n"))
+			Expect(config).To(ContainSubstring("groupAttr: memberUid\n"))
+		})
+
+		It("rejects URIs of other schemes", func() {
+			conn.URIs = []string{"https://ldap.example.com"}
+			_, err := clientconfig.DexConnectorConfig(conn, dex)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
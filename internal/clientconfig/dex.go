@@ -0,0 +1,112 @@
+package clientconfig
+
+import (
+	"fmt"
+	"net/url"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Dex holds the settings of a Dex connector that don't depend on the directory
+type Dex struct {
+	// ID of the connector
+	ID string
+	// Name of the connector shown on the login page
+	Name string
+	// Prompt of the username field on the login page
+	UsernamePrompt string
+}
+
+type dexConnector struct {
+	Type   string             `json:"type"`
+	ID     string             `json:"id"`
+	Name   string             `json:"name"`
+	Config dexConnectorConfig `json:"config"`
+}
+
+type dexConnectorConfig struct {
+	Host           string         `json:"host"`
+	InsecureNoSSL  bool           `json:"insecureNoSSL"`
+	RootCAData     []byte         `json:"rootCAData,omitempty"`
+	BindDN         string         `json:"bindDN,omitempty"`
+	BindPW         string         `json:"bindPW,omitempty"`
+	UsernamePrompt string         `json:"usernamePrompt,omitempty"`
+	UserSearch     dexUserSearch  `json:"userSearch"`
+	GroupSearch    dexGroupSearch `json:"groupSearch"`
+}
+
+type dexUserSearch struct {
+	BaseDN                string `json:"baseDN"`
+	Filter                string `json:"filter"`
+	Username              string `json:"username"`
+	IDAttr                string `json:"idAttr"`
+	EmailAttr             string `json:"emailAttr"`
+	NameAttr              string `json:"nameAttr"`
+	PreferredUsernameAttr string `json:"preferredUsernameAttr"`
+}
+
+type dexGroupSearch struct {
+	BaseDN       string           `json:"baseDN"`
+	Filter       string           `json:"filter"`
+	UserMatchers []dexUserMatcher `json:"userMatchers"`
+	NameAttr     string           `json:"nameAttr"`
+}
+
+type dexUserMatcher struct {
+	UserAttr  string `json:"userAttr"`
+	GroupAttr string `json:"groupAttr"`
+}
+
+// DexConnectorConfig renders an LDAP connector to add to the connectors of a Dex configuration. Dex only
+// connects to a single host, so the first URI is used. Users are matched to groupOfNames groups by DN and to
+// posixGroup groups by uid
+func DexConnectorConfig(conn *Connection, dex *Dex) (string, error) {
+	if len(conn.URIs) == 0 {
+		return "", fmt.Errorf("no URI to connect to")
+	}
+	uri, err := url.Parse(conn.URIs[0])
+	if err != nil {
+		return "", err
+	}
+	if uri.Scheme != "ldap" && uri.Scheme != "ldaps" {
+		return "", fmt.Errorf("unsupported scheme %q in URI %s", uri.Scheme, conn.URIs[0])
+	}
+
+	connector := dexConnector{
+		Type: "ldap",
+		ID:   dex.ID,
+		Name: dex.Name,
+		Config: dexConnectorConfig{
+			Host:           uri.Host,
+			InsecureNoSSL:  uri.Scheme == "ldap",
+			RootCAData:     conn.CACert,
+			BindDN:         conn.BindDN,
+			BindPW:         conn.BindPassword,
+			UsernamePrompt: dex.UsernamePrompt,
+			UserSearch: dexUserSearch{
+				BaseDN:                conn.PeopleBase,
+				Filter:                "(objectClass=inetOrgPerson)",
+				Username:              "uid",
+				IDAttr:                "uid",
+				EmailAttr:             "mail",
+				NameAttr:              "cn",
+				PreferredUsernameAttr: "uid",
+			},
+			GroupSearch: dexGroupSearch{
+				BaseDN: conn.GroupsBase,
+				Filter: "(|(objectClass=groupOfNames)(objectClass=posixGroup))",
+				UserMatchers: []dexUserMatcher{
+					{UserAttr: "DN", GroupAttr: "member"},
+					{UserAttr: "uid", GroupAttr: "memberUid"},
+				},
+				NameAttr: "cn",
+			},
+		},
+	}
+
+	out, err := yaml.Marshal(connector)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
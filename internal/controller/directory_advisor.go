@@ -0,0 +1,221 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+// initialAdvisorWindow is how far back the logs are read when the index advisor is first enabled
+const initialAdvisorWindow = 10 * time.Minute
+
+// reconcileIndexAdvisor reads the logs of each instance written since the last check for searches slapd
+// reports as unindexed or which took longer than the slow threshold, and suggests the missing indexes
+func (r *DirectoryReconciler) reconcileIndexAdvisor(ctx context.Context, directory *v1alpha1.Directory) error {
+	spec := directory.Spec.IndexAdvisor
+	if spec == nil {
+		directory.Status.IndexAdvice = nil
+		return nil
+	}
+	if r.Clientset == nil {
+		return errors.New("index advisor requires a clientset to read pod logs")
+	}
+
+	if directory.Status.IndexAdvice == nil {
+		directory.Status.IndexAdvice = &v1alpha1.IndexAdviceStatus{}
+	}
+	status := directory.Status.IndexAdvice
+
+	pods, err := r.directoryPods(ctx, directory)
+	if err != nil {
+		return err
+	}
+
+	suffixes := []string{}
+	for _, db := range directory.Spec.SlapdConfig.Databases {
+		suffixes = append(suffixes, db.Suffix)
+	}
+
+	positions := map[string]time.Time{}
+	for _, position := range status.Positions {
+		positions[position.Pod] = position.Time.Time
+	}
+	now := metav1.Now()
+
+	slow := time.Second
+	if spec.SlowThreshold != nil {
+		slow = spec.SlowThreshold.Duration
+	}
+
+	// The API only filters logs to the second, so lines are read with their timestamps and those up to the last
+	// line read before are skipped
+	findings := []slapd.IndexFinding{}
+	read := []v1alpha1.LogPosition{}
+	for i := range pods {
+		after, known := positions[pods[i].Name]
+		if !known {
+			after = now.Add(-initialAdvisorWindow)
+		}
+		logs, err := r.Clientset.CoreV1().Pods(pods[i].Namespace).GetLogs(pods[i].Name, &corev1.PodLogOptions{
+			Container:  "slapd",
+			SinceTime:  &metav1.Time{Time: after},
+			Timestamps: true,
+		}).Stream(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read logs of %s: %w", pods[i].Name, err)
+		}
+		lines, last, err := slapd.LogsAfter(logs, after)
+		logs.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read logs of %s: %w", pods[i].Name, err)
+		}
+		found, err := slapd.AnalyzeLog(lines, suffixes, slow)
+		if err != nil {
+			return fmt.Errorf("failed to read logs of %s: %w", pods[i].Name, err)
+		}
+		findings = append(findings, found...)
+		read = append(read, v1alpha1.LogPosition{Pod: pods[i].Name, Time: metav1.NewMicroTime(last)})
+	}
+
+	suggestions := map[string]*v1alpha1.IndexSuggestion{}
+	for i := range status.Suggestions {
+		suggestion := status.Suggestions[i]
+		suggestions[suggestionKey(suggestion.Database, suggestion.Attribute, string(suggestion.Type))] = &suggestion
+	}
+
+	for _, finding := range findings {
+		db := databaseBySuffix(directory, finding.Suffix)
+		if db == nil {
+			continue
+		}
+		attribute := metricAttribute(spec, db, finding.Attribute)
+		unindexedSearches.WithLabelValues(directory.Namespace, directory.Name, db.Name, attribute).Add(float64(finding.Unindexed))
+		slowSearches.WithLabelValues(directory.Namespace, directory.Name, db.Name, attribute).Add(float64(finding.Slow))
+
+		key := suggestionKey(db.Name, finding.Attribute, finding.Type)
+		if suggestions[key] == nil {
+			suggestions[key] = &v1alpha1.IndexSuggestion{
+				Database:  db.Name,
+				Attribute: finding.Attribute,
+				Type:      v1alpha1.IndexType(finding.Type),
+			}
+		}
+		suggestions[key].Unindexed += finding.Unindexed
+		suggestions[key].Slow += finding.Slow
+		suggestions[key].LastSeen = now
+	}
+
+	result := []v1alpha1.IndexSuggestion{}
+	for _, suggestion := range suggestions {
+		if !databaseIndexes(directory.Database(suggestion.Database), suggestion.Attribute, suggestion.Type) {
+			result = append(result, *suggestion)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Unindexed+result[i].Slow != result[j].Unindexed+result[j].Slow {
+			return result[i].Unindexed+result[i].Slow > result[j].Unindexed+result[j].Slow
+		}
+		return suggestionKey(result[i].Database, result[i].Attribute, string(result[i].Type)) <
+			suggestionKey(result[j].Database, result[j].Attribute, string(result[j].Type))
+	})
+	r.reportSuggestions(directory, result, int(spec.MaxSuggestions))
+
+	status.Suggestions = result
+	status.Positions = read
+	status.LastChecked = &now
+	return nil
+}
+
+// reportSuggestions emits an IndexSuggested event for each of the first limit suggestions which hasn't been
+// reported before. Suggestions beyond the limit are reported once they become frequent enough
+func (r *DirectoryReconciler) reportSuggestions(directory *v1alpha1.Directory, suggestions []v1alpha1.IndexSuggestion, limit int) {
+	for i := range suggestions {
+		if limit > 0 && i >= limit {
+			return
+		}
+		if suggestions[i].Reported {
+			continue
+		}
+		r.Recorder.Eventf(directory, corev1.EventTypeNormal, "IndexSuggested", "Searches of database %s filter on %s without a %s index",
+			suggestions[i].Database, suggestions[i].Attribute, suggestions[i].Type)
+		suggestions[i].Reported = true
+	}
+}
+
+// metricAttribute returns the attribute label of searches filtering on attribute. Only attributes configured
+// in the index advisor spec or indexed by the database are labelled by name, bounding the number of series
+// clients can create by searching on arbitrary attributes
+func metricAttribute(spec *v1alpha1.IndexAdvisorSpec, db *v1alpha1.DatabaseSpec, attribute string) string {
+	for _, configured := range spec.Attributes {
+		if strings.EqualFold(configured, attribute) {
+			return strings.ToLower(configured)
+		}
+	}
+	for _, index := range db.Indexes {
+		for _, indexed := range index.Attributes {
+			if strings.EqualFold(indexed, attribute) {
+				return strings.ToLower(indexed)
+			}
+		}
+	}
+	return "other"
+}
+
+func suggestionKey(database, attribute, kind string) string {
+	return strings.ToLower(fmt.Sprintf("%s/%s/%s", database, attribute, kind))
+}
+
+// databaseBySuffix returns the database of the directory with the given suffix
+func databaseBySuffix(directory *v1alpha1.Directory, suffix string) *v1alpha1.DatabaseSpec {
+	for i := range directory.Spec.SlapdConfig.Databases {
+		if strings.EqualFold(directory.Spec.SlapdConfig.Databases[i].Suffix, suffix) {
+			return &directory.Spec.SlapdConfig.Databases[i]
+		}
+	}
+	return nil
+}
+
+// databaseIndexes returns true if db is configured with an index of the given type of attribute
+func databaseIndexes(db *v1alpha1.DatabaseSpec, attribute string, kind v1alpha1.IndexType) bool {
+	if db == nil {
+		return false
+	}
+	for _, index := range db.Indexes {
+		for _, indexed := range index.Attributes {
+			if !strings.EqualFold(indexed, attribute) {
+				continue
+			}
+			for _, t := range index.Types {
+				if t == kind || (kind == "sub" && strings.HasPrefix(string(t), "sub")) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
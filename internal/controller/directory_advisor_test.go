@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/client-go/tools/record"
+
+	openldapv1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+var _ = Describe("Directory Index Advisor", func() {
+	It("only labels metrics with configured and indexed attributes", func() {
+		spec := &openldapv1alpha1.IndexAdvisorSpec{Attributes: []string{"mail"}}
+		db := &openldapv1alpha1.DatabaseSpec{
+			Indexes: []openldapv1alpha1.IndexSpec{
+				{Attributes: []string{"uid"}, Types: []openldapv1alpha1.IndexType{"eq"}},
+			},
+		}
+
+		Expect(metricAttribute(spec, db, "Mail")).To(Equal("mail"))
+		Expect(metricAttribute(spec, db, "UID")).To(Equal("uid"))
+		Expect(metricAttribute(spec, db, "description")).To(Equal("other"))
+	})
+
+	It("reports suggestions beyond the limit once they become frequent enough", func() {
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &DirectoryReconciler{Recorder: recorder}
+		directory := &openldapv1alpha1.Directory{}
+		suggestions := []openldapv1alpha1.IndexSuggestion{
+			{Database: "example", Attribute: "mail", Type: "eq", Unindexed: 10},
+			{Database: "example", Attribute: "cn", Type: "sub", Unindexed: 5},
+		}
+
+		reconciler.reportSuggestions(directory, suggestions, 1)
+		Expect(recorder.Events).To(HaveLen(1))
+		Expect(<-recorder.Events).To(ContainSubstring("filter on mail"))
+		Expect(suggestions[1].Reported).To(BeFalse())
+
+		By("not reporting a suggestion twice")
+		suggestions[0], suggestions[1] = suggestions[1], suggestions[0]
+		reconciler.reportSuggestions(directory, suggestions, 2)
+		Expect(recorder.Events).To(HaveLen(1))
+		Expect(<-recorder.Events).To(ContainSubstring("filter on cn"))
+	})
+})
@@ -0,0 +1,260 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+	"github.com/paddyoneill/openldap-operator/internal/utils"
+)
+
+const (
+	// presetSeedName is the name under which the DIT preset is recorded in the applied seeds
+	presetSeedName = "preset"
+)
+
+// reconcileBootstrap loads the DIT preset and seed sources which haven't yet been applied, or whose entries
+// were lost with the data of instances without persistent storage
+func (r *DirectoryReconciler) reconcileBootstrap(ctx context.Context, directory *v1alpha1.Directory) error {
+	if directory.Spec.Bootstrap == nil || directory.Spec.Bootstrap.Seed == nil {
+		return nil
+	}
+	seed := directory.Spec.Bootstrap.Seed
+
+	db := directory.Database(seed.Database)
+	if db == nil {
+		return fmt.Errorf("seed database %q not found", seed.Database)
+	}
+
+	// Content replicated from an external provider can't be written to until the directory is cut over
+	if external := directory.ExternalProvider(); external != nil && directory.Database(external.Database) == db {
+		return nil
+	}
+
+	var conn *slapd.Client
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+	connect := func() error {
+		if conn != nil {
+			return nil
+		}
+		var err error
+		conn, err = connectDirectory(ctx, r, directory, db.RootDN())
+		return err
+	}
+	load := func(name, revision string, entries []*slapd.Entry) error {
+		if err := connect(); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if _, err := conn.Add(entry); err != nil {
+				return fmt.Errorf("failed to load seed %s: %w", name, err)
+			}
+		}
+		recordAppliedSeed(directory, name, revision)
+		log.FromContext(ctx).Info("loaded seed into directory", "seed", name, "revision", revision)
+		return nil
+	}
+	// Instances without persistent storage lose their data when they restart, so a seed recorded as applied is
+	// only taken as loaded while its last entry, which LDIF lists after its parents, is still present
+	loaded := func(name, revision string, entries []*slapd.Entry) (bool, error) {
+		if !directory.SeedApplied(name, revision) {
+			return false, nil
+		}
+		if directory.Spec.Storage != nil || len(entries) == 0 {
+			return true, nil
+		}
+		if err := connect(); err != nil {
+			return false, err
+		}
+		entry, err := conn.Get(entries[len(entries)-1].DN)
+		if err != nil {
+			return false, err
+		}
+		return entry != nil, nil
+	}
+
+	if seed.Preset != "" && seed.Preset != v1alpha1.DITPresetNone {
+		entries, err := slapd.PresetEntries(seed.Preset, db.Suffix)
+		if err != nil {
+			return err
+		}
+		done, err := loaded(presetSeedName, string(seed.Preset), entries)
+		if err != nil {
+			return err
+		}
+		if !done {
+			if err := load(presetSeedName, string(seed.Preset), entries); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, source := range seed.Sources {
+		data, err := r.seedContent(ctx, directory, source)
+		if err != nil {
+			return err
+		}
+
+		revision := slapd.SeedRevision(data)
+		if directory.SeedApplied(source.Name, revision) && directory.Spec.Storage != nil {
+			continue
+		}
+
+		if source.Template {
+			if data, err = r.renderSeed(ctx, directory, source.Name, data, db); err != nil {
+				return err
+			}
+		}
+
+		entries, err := slapd.ParseLDIF(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse seed %s: %w", source.Name, err)
+		}
+		done, err := loaded(source.Name, revision, entries)
+		if err != nil {
+			return err
+		}
+		if done {
+			continue
+		}
+		if err := load(source.Name, revision, entries); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// seedContent reads the LDIF of a seed source from its ConfigMap or Secret
+func (r *DirectoryReconciler) seedContent(ctx context.Context, directory *v1alpha1.Directory, source v1alpha1.SeedSource) ([]byte, error) {
+	switch {
+	case source.ConfigMapKeyRef != nil:
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, types.NamespacedName{Name: source.ConfigMapKeyRef.Name, Namespace: directory.Namespace}, configMap); err != nil {
+			return nil, err
+		}
+		if data, found := configMap.Data[source.ConfigMapKeyRef.Key]; found {
+			return []byte(data), nil
+		}
+		if data, found := configMap.BinaryData[source.ConfigMapKeyRef.Key]; found {
+			return data, nil
+		}
+		return nil, fmt.Errorf("key %s not found in configmap %s", source.ConfigMapKeyRef.Key, source.ConfigMapKeyRef.Name)
+	case source.SecretKeyRef != nil:
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: source.SecretKeyRef.Name, Namespace: directory.Namespace}, secret); err != nil {
+			return nil, err
+		}
+		if data, found := secret.Data[source.SecretKeyRef.Key]; found {
+			return data, nil
+		}
+		return nil, fmt.Errorf("key %s not found in secret %s", source.SecretKeyRef.Key, source.SecretKeyRef.Name)
+	}
+
+	return nil, fmt.Errorf("seed %s has neither a configMapKeyRef nor a secretKeyRef", source.Name)
+}
+
+// renderSeed renders a templated seed. Passwords generated by the template are persisted in the seed
+// password secret before the rendered LDIF is returned
+func (r *DirectoryReconciler) renderSeed(
+	ctx context.Context, directory *v1alpha1.Directory, name string, data []byte, db *v1alpha1.DatabaseSpec,
+) ([]byte, error) {
+	secret, err := r.seedPasswordSecret(ctx, directory)
+	if err != nil {
+		return nil, err
+	}
+	patch := client.MergeFrom(secret.DeepCopy())
+
+	generated := false
+	password := func(key string) (string, error) {
+		if _, found := secret.Data[key]; !found {
+			value, err := utils.GenerateRandonPassword(24)
+			if err != nil {
+				return "", err
+			}
+			secret.Data[key] = value
+			generated = true
+		}
+		return utils.HashPassword(secret.Data[key])
+	}
+
+	rendered, err := slapd.RenderSeed(name, data, db, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render seed %s: %w", name, err)
+	}
+
+	if generated {
+		if err := r.Patch(ctx, secret, patch); err != nil {
+			return nil, err
+		}
+	}
+
+	return rendered, nil
+}
+
+func (r *DirectoryReconciler) seedPasswordSecret(ctx context.Context, directory *v1alpha1.Directory) (*corev1.Secret, error) {
+	desired, err := r.Builder.SeedPasswordSecret(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if err := r.Create(ctx, desired); err != nil {
+			return nil, err
+		}
+		return desired, nil
+	}
+
+	if existing.Data == nil {
+		existing.Data = map[string][]byte{}
+	}
+	return existing, nil
+}
+
+// recordAppliedSeed records that a revision of a seed has been loaded, replacing any earlier revision
+func recordAppliedSeed(directory *v1alpha1.Directory, name, revision string) {
+	applied := v1alpha1.AppliedSeed{
+		Name:      name,
+		Revision:  revision,
+		AppliedAt: metav1.Now(),
+	}
+	for i := range directory.Status.AppliedSeeds {
+		if directory.Status.AppliedSeeds[i].Name == name {
+			directory.Status.AppliedSeeds[i] = applied
+			return
+		}
+	}
+	directory.Status.AppliedSeeds = append(directory.Status.AppliedSeeds, applied)
+}
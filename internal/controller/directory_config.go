@@ -0,0 +1,382 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+// adminPassword returns the password of the config and database root DNs of a directory
+func adminPassword(ctx context.Context, c client.Reader, directory *v1alpha1.Directory) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: directory.SecretName(), Namespace: directory.Namespace}, secret); err != nil {
+		return nil, err
+	}
+
+	password, found := secret.Data["password"]
+	if !found {
+		return nil, fmt.Errorf("secret %s does not contain a password", directory.SecretName())
+	}
+	return password, nil
+}
+
+// connectDirectory opens a connection to an instance of the directory accepting writes, bound as bindDN using
+// the admin password
+func connectDirectory(ctx context.Context, c client.Reader, directory *v1alpha1.Directory, bindDN string) (*slapd.Client, error) {
+	password, err := adminPassword(ctx, c, directory)
+	if err != nil {
+		return nil, err
+	}
+
+	return slapd.Connect(slapd.WriteServiceURI(directory), bindDN, string(password))
+}
+
+// reconcileConfig applies the slapd config of the directory to each of its instances. Each instance holds
+// its own copy of cn=config, so the config is applied to the pods directly rather than through the service
+func (r *DirectoryReconciler) reconcileConfig(ctx context.Context, directory *v1alpha1.Directory) error {
+	if len(directory.Spec.SlapdConfig.Databases) == 0 && directory.Spec.TLS == nil && !directory.Replicated() &&
+		len(directory.ReplicationPeers()) == 0 && directory.Spec.SlapdConfig.Tuning == nil &&
+		directory.Spec.Logging == nil {
+		return nil
+	}
+
+	return r.configureInstances(ctx, directory, func(pod *corev1.Pod, err error) error {
+		return fmt.Errorf("failed to configure %s: %w", pod.Name, err)
+	})
+}
+
+// configureInstances applies the slapd config of the directory to each of its running instances, starting with
+// the provider of a provider/consumer topology. onError is called for each instance which fails to be
+// configured and stops the remaining instances from being configured by returning an error
+func (r *DirectoryReconciler) configureInstances(ctx context.Context, directory *v1alpha1.Directory,
+	onError func(pod *corev1.Pod, err error) error) error {
+	password, err := adminPassword(ctx, r, directory)
+	if err != nil {
+		return err
+	}
+
+	bindings, err := r.directoryBindings(ctx, directory)
+	if err != nil {
+		return err
+	}
+
+	watched, err := r.watchedDatabases(ctx, directory)
+	if err != nil {
+		return err
+	}
+
+	pods, err := r.directoryPods(ctx, directory)
+	if err != nil {
+		return err
+	}
+
+	external, err := r.externalProvider(ctx, directory)
+	if err != nil {
+		return err
+	}
+
+	peers, err := r.replicationPeers(ctx, directory)
+	if err != nil {
+		return err
+	}
+
+	order := []int{}
+	for i := range pods {
+		if podOrdinal(&pods[i]) == int(directory.ProviderOrdinal()) {
+			order = append([]int{i}, order...)
+		} else {
+			order = append(order, i)
+		}
+	}
+
+	for _, i := range order {
+		replica := directoryReplica(directory, pods, i, password, external, peers)
+		if err := r.configureInstance(directory, &pods[i], replica, password, bindings, watched); err != nil {
+			if err := onError(&pods[i], err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// configureInstance applies the slapd config of the directory to the instance running in pod. Watched databases
+// get the syncprov overlay so their changes can be followed over syncrepl
+func (r *DirectoryReconciler) configureInstance(directory *v1alpha1.Directory, pod *corev1.Pod, replica *slapd.Replica,
+	password []byte, bindings []v1alpha1.LdapBinding, watched map[string]bool) error {
+	conn, err := slapd.Connect(slapd.PodURI(pod.Status.PodIP), slapd.ConfigRootDN, string(password))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.EnsureSchemas(directory.Spec.SlapdConfig.Schemas.Custom()); err != nil {
+		return err
+	}
+
+	if directory.Spec.TLS != nil {
+		if err := conn.Ensure(slapd.TLSEntry()); err != nil {
+			return err
+		}
+	}
+
+	if err := conn.Ensure(slapd.LoggingEntry(directory)); err != nil {
+		return err
+	}
+
+	tuning := slapd.DirectoryTuning(directory)
+	if err := conn.Ensure(slapd.TuningEntry(tuning)); err != nil {
+		return err
+	}
+	if err := conn.Ensure(slapd.FrontendTuningEntry(tuning)); err != nil {
+		return err
+	}
+
+	if replica != nil {
+		if err := conn.Ensure(slapd.ServerIDEntry(replica)); err != nil {
+			return err
+		}
+	}
+
+	if directoryIndexed(directory) || directory.ExternalProvider() != nil {
+		if err := conn.EnsureMonitorDatabase(); err != nil {
+			return err
+		}
+	}
+
+	modules := []string{}
+	for i := range directory.Spec.SlapdConfig.Databases {
+		modules = append(modules, slapd.OverlayModules(&directory.Spec.SlapdConfig.Databases[i], replica)...)
+	}
+	if len(watched) > 0 {
+		modules = append(modules, "syncprov")
+	}
+	if err := conn.EnsureModules(modules...); err != nil {
+		return err
+	}
+
+	for i := range directory.Spec.SlapdConfig.Databases {
+		db := &directory.Spec.SlapdConfig.Databases[i]
+
+		access := []string{}
+		for j := range bindings {
+			// Bindings with an invalid scope are reported by the binding controller and granted nothing
+			if directory.Database(bindings[j].Spec.Database) == db && slapd.ValidateBindingScope(&bindings[j], db) == nil {
+				access = append(access, slapd.BindingAccess(&bindings[j], db))
+			}
+		}
+		access = append(access, db.Access...)
+
+		if replica.Delta() && replica.Provider {
+			logDN, err := conn.EnsureAccessLogDatabase(db, replica.AccessLog)
+			if err != nil {
+				return err
+			}
+			if err := conn.EnsureOverlays(logDN, slapd.AccessLogOverlayEntries(logDN)); err != nil {
+				return err
+			}
+		}
+
+		dn, err := conn.EnsureDatabase(db, password, access, replica)
+		if err != nil {
+			return err
+		}
+
+		if err := conn.Ensure(slapd.DatabaseTuningEntry(dn, slapd.DatabaseTuning(directory, db))); err != nil {
+			return err
+		}
+
+		overlays := slapd.OverlayEntries(dn, db, replica)
+		if watched[db.Name] {
+			overlays = slapd.WithSyncProv(dn, overlays)
+		}
+		if err := conn.EnsureOverlays(dn, overlays); err != nil {
+			return err
+		}
+
+		if err := conn.EnsureChainDatabase(dn, db, replica); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// directoryReplica returns the replication role of the instance running in pods[i], or nil if the directory
+// runs a single instance and replicates with neither an external provider nor peers. Providers replicate with
+// the remote peers in addition to the other providers in the cluster
+func directoryReplica(directory *v1alpha1.Directory, pods []corev1.Pod, i int, password []byte, external *slapd.External,
+	peers []slapd.Peer) *slapd.Replica {
+	if !directory.Replicated() && external == nil && len(peers) == 0 {
+		return nil
+	}
+
+	replica := &slapd.Replica{
+		ServerID:    int(directory.ServerID(podOrdinal(&pods[i]))),
+		Provider:    directory.PodRole(podOrdinal(&pods[i])) == v1alpha1.RoleProvider,
+		Mode:        directory.ReplicationMode(),
+		Credentials: string(password),
+		External:    external,
+	}
+	if directory.Spec.Replication != nil {
+		replica.AccessLog = directory.Spec.Replication.AccessLog
+	}
+
+	multiProvider := directory.ReplicationTopology() == v1alpha1.ReplicationTopologyMultiProvider
+	for j := range pods {
+		if j == i || directory.PodRole(podOrdinal(&pods[j])) != v1alpha1.RoleProvider {
+			continue
+		}
+		if multiProvider || !replica.Provider {
+			replica.Providers = append(replica.Providers, slapd.Peer{
+				ServerID: int(directory.ServerID(podOrdinal(&pods[j]))),
+				URI:      slapd.InstanceURI(directory, podOrdinal(&pods[j])),
+			})
+		}
+	}
+
+	if replica.Provider {
+		replica.Providers = append(replica.Providers, peers...)
+	}
+
+	if !replica.Provider {
+		replica.UpdateRef = slapd.WriteServiceURI(directory)
+	}
+
+	return replica
+}
+
+// reconcilePodRoles labels each pod of the directory with its replication role, which the read and write
+// services select on
+func (r *DirectoryReconciler) reconcilePodRoles(ctx context.Context, directory *v1alpha1.Directory) error {
+	list := &corev1.PodList{}
+	if err := r.List(ctx, list, client.InNamespace(directory.Namespace), client.MatchingLabels{
+		"app.kubernetes.io/instance":  directory.Name,
+		"app.kubernetes.io/component": "directory",
+	}); err != nil {
+		return err
+	}
+
+	for i := range list.Items {
+		pod := &list.Items[i]
+		role := directory.PodRole(podOrdinal(pod))
+		if pod.Labels[v1alpha1.RoleLabel] == role {
+			continue
+		}
+
+		patch := client.MergeFrom(pod.DeepCopy())
+		if pod.Labels == nil {
+			pod.Labels = map[string]string{}
+		}
+		pod.Labels[v1alpha1.RoleLabel] = role
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// directoryPods returns the running pods of the directory statefulset which are part of the target topology,
+// sorted by ordinal. Departing pods are excluded so that the remaining members stop replicating from them
+func (r *DirectoryReconciler) directoryPods(ctx context.Context, directory *v1alpha1.Directory) ([]corev1.Pod, error) {
+	target, _ := replicationTarget(directory)
+
+	list := &corev1.PodList{}
+	if err := r.List(ctx, list, client.InNamespace(directory.Namespace), client.MatchingLabels{
+		"app.kubernetes.io/instance":  directory.Name,
+		"app.kubernetes.io/component": "directory",
+	}); err != nil {
+		return nil, err
+	}
+
+	pods := []corev1.Pod{}
+	for _, pod := range list.Items {
+		if pod.Status.PodIP == "" || !pod.DeletionTimestamp.IsZero() || podOrdinal(&pod) >= int(target) {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no running pods found for directory %s", directory.Name)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return podOrdinal(&pods[i]) < podOrdinal(&pods[j])
+	})
+
+	return pods, nil
+}
+
+// podOrdinal returns the ordinal of a statefulset pod, parsed from its name
+func podOrdinal(pod *corev1.Pod) int {
+	ordinal, err := strconv.Atoi(pod.Name[strings.LastIndex(pod.Name, "-")+1:])
+	if err != nil {
+		return -1
+	}
+	return ordinal
+}
+
+// watchedDatabases returns the names of the databases of directory followed by watches which aren't being deleted
+func (r *DirectoryReconciler) watchedDatabases(ctx context.Context, directory *v1alpha1.Directory) (map[string]bool, error) {
+	list := &v1alpha1.DirectoryWatchList{}
+	if err := r.List(ctx, list, client.InNamespace(directory.Namespace)); err != nil {
+		return nil, err
+	}
+
+	watched := map[string]bool{}
+	for _, watch := range list.Items {
+		if watch.Spec.DirectoryRef.Name != directory.Name || !watch.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if db := directory.Database(watch.Spec.Database); db != nil {
+			watched[db.Name] = true
+		}
+	}
+	return watched, nil
+}
+
+// directoryBindings returns the bindings referencing directory which aren't being deleted, sorted by name
+func (r *DirectoryReconciler) directoryBindings(ctx context.Context, directory *v1alpha1.Directory) ([]v1alpha1.LdapBinding, error) {
+	list := &v1alpha1.LdapBindingList{}
+	if err := r.List(ctx, list, client.InNamespace(directory.Namespace)); err != nil {
+		return nil, err
+	}
+
+	bindings := []v1alpha1.LdapBinding{}
+	for _, binding := range list.Items {
+		if binding.Spec.DirectoryRef.Name == directory.Name && binding.DeletionTimestamp.IsZero() {
+			bindings = append(bindings, binding)
+		}
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].Name < bindings[j].Name
+	})
+
+	return bindings, nil
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+// reconcileConnection publishes the connection details of the directory in its status and binding secret
+func (r *DirectoryReconciler) reconcileConnection(ctx context.Context, directory *v1alpha1.Directory) error {
+	service := &corev1.Service{}
+	if err := r.Get(ctx, types.NamespacedName{Name: directory.ServiceName(), Namespace: directory.Namespace}, service); err != nil {
+		return err
+	}
+
+	connection := &v1alpha1.ConnectionStatus{
+		URI:      slapd.ServiceURI(directory),
+		WriteURI: slapd.WriteServiceURI(directory),
+		ReadURI:  slapd.ReadServiceURI(directory),
+		ConfigDN: slapd.ConfigRootDN,
+		AdminSecretRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: directory.SecretName()},
+			Key:                  "password",
+		},
+	}
+
+	if directory.Spec.TLS != nil {
+		connection.LDAPSURI = slapd.ServiceLDAPSURI(directory)
+		connection.CASecretRef = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: directory.Spec.TLS.SecretName},
+			Key:                  "ca.crt",
+		}
+	}
+
+	// Only load balancers report an address clients outside the cluster can reach. Which node addresses of a
+	// NodePort service are reachable depends on the cluster, so none are published
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		host := ingress.IP
+		if host == "" {
+			host = ingress.Hostname
+		}
+		if host == "" {
+			continue
+		}
+		connection.ExternalURIs = append(connection.ExternalURIs, fmt.Sprintf("ldap://%s:389", host))
+		if directory.Spec.TLS != nil {
+			connection.ExternalURIs = append(connection.ExternalURIs, fmt.Sprintf("ldaps://%s:636", host))
+		}
+	}
+
+	for _, db := range directory.Spec.SlapdConfig.Databases {
+		connection.Suffixes = append(connection.Suffixes, db.Suffix)
+	}
+	if db := directory.Database(""); db != nil {
+		connection.AdminDN = db.RootDN()
+	}
+
+	directory.Status.Connection = connection
+
+	return r.reconcileServiceBinding(ctx, directory)
+}
+
+// reconcileServiceBinding creates the servicebinding.io secret when enabled and removes it otherwise. The secret
+// publishes the service account of an LdapBinding owned by the directory, never the root DN
+func (r *DirectoryReconciler) reconcileServiceBinding(ctx context.Context, directory *v1alpha1.Directory) error {
+	if directory.Spec.ServiceBinding == nil || !directory.Spec.ServiceBinding.Enabled {
+		directory.Status.Binding = nil
+
+		binding := &v1alpha1.LdapBinding{}
+		if err := r.Get(ctx, types.NamespacedName{Name: directory.ServiceBindingName(), Namespace: directory.Namespace}, binding); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+		} else if metav1.IsControlledBy(binding, directory) {
+			if err := client.IgnoreNotFound(r.Delete(ctx, binding)); err != nil {
+				return err
+			}
+		}
+
+		existing := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: directory.BindingSecretName(), Namespace: directory.Namespace}, existing); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(existing, directory) {
+			return nil
+		}
+		return client.IgnoreNotFound(r.Delete(ctx, existing))
+	}
+
+	db := directory.Database(directory.Spec.ServiceBinding.Database)
+	if db == nil {
+		return fmt.Errorf("service binding database %q not found", directory.Spec.ServiceBinding.Database)
+	}
+
+	binding, err := r.reconcileServiceBindingAccount(ctx, directory)
+	if err != nil {
+		return err
+	}
+	// The secret is published once the binding has created the service account. The directory is reconciled
+	// again when the binding becomes ready
+	if !meta.IsStatusConditionTrue(binding.Status.Conditions, v1alpha1.LdapBindingReadyCondition) || binding.Status.SecretName == "" {
+		return nil
+	}
+
+	account := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: binding.Status.SecretName, Namespace: directory.Namespace}, account); err != nil {
+		return err
+	}
+
+	host := slapd.ReadServiceHost(directory)
+	uri := slapd.ReadServiceURI(directory)
+	if binding.Spec.Access == v1alpha1.BindingAccessWrite {
+		host = slapd.WriteServiceHost(directory)
+		uri = slapd.WriteServiceURI(directory)
+	}
+
+	data := map[string][]byte{
+		"type":     []byte("ldap"),
+		"provider": []byte("openldap"),
+		"host":     []byte(host),
+		"port":     []byte("389"),
+		"uri":      []byte(uri),
+		"base-dn":  account.Data["base-dn"],
+		"username": account.Data["bind-dn"],
+		"password": account.Data["password"],
+	}
+	if ca, found := account.Data["ca.crt"]; found {
+		data["ca.crt"] = ca
+	}
+
+	desired, err := r.Builder.ServiceBindingSecret(directory, data)
+	if err != nil {
+		return err
+	}
+
+	existing := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err := r.Create(ctx, desired); err != nil {
+			return err
+		}
+	} else {
+		patch := client.MergeFrom(existing.DeepCopy())
+		existing.Labels = desired.Labels
+		existing.Data = desired.Data
+		if err := r.Patch(ctx, existing, patch); err != nil {
+			return err
+		}
+	}
+
+	directory.Status.Binding = &corev1.LocalObjectReference{Name: desired.Name}
+	return nil
+}
+
+// reconcileServiceBindingAccount creates or updates the LdapBinding managing the service account of the
+// servicebinding.io secret
+func (r *DirectoryReconciler) reconcileServiceBindingAccount(ctx context.Context, directory *v1alpha1.Directory) (*v1alpha1.LdapBinding, error) {
+	desired, err := r.Builder.ServiceBindingLdapBinding(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := &v1alpha1.LdapBinding{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if err := r.Create(ctx, desired); err != nil {
+			return nil, err
+		}
+		return desired, nil
+	}
+	if !metav1.IsControlledBy(existing, directory) {
+		return nil, fmt.Errorf("ldapbinding %s exists and is not managed by directory %s", existing.Name, directory.Name)
+	}
+
+	patch := client.MergeFrom(existing.DeepCopy())
+	existing.Labels = desired.Labels
+	existing.Spec = desired.Spec
+	if err := r.Patch(ctx, existing, patch); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
@@ -23,17 +23,26 @@ import (
 	"os"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
 	"github.com/paddyoneill/openldap-operator/internal/builder"
@@ -45,6 +54,8 @@ type DirectoryReconciler struct {
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
 	Builder  *builder.Builder
+	// Clientset reads pod logs, which the controller-runtime client doesn't support
+	Clientset kubernetes.Interface
 }
 
 const (
@@ -56,6 +67,13 @@ const (
 // +kubebuilder:rbac:groups=openldap.my.domaim,resources=directories/finalizers,verbs=update
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=core,resources=pods/log,verbs=get
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapbindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=directorywatches,verbs=get;list;watch
 
 // Reconcile directory resource
 func (r *DirectoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -83,7 +101,8 @@ func (r *DirectoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			logger.Error(err, "Failed to update directory status")
 			return ctrl.Result{}, err
 		}
-		return ctrl.Result{}, nil
+		// Status updates don't trigger a reconcile
+		return ctrl.Result{Requeue: true}, nil
 	}
 
 	// Add finalizer if needed
@@ -169,6 +188,42 @@ func (r *DirectoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileMembers(directory); err != nil {
+		logger.Error(err, "failed to allocate server IDs")
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.DirectoryAvailableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidServerIDs",
+			Message: err.Error(),
+		})
+
+		if err := r.Status().Update(ctx, directory); err != nil {
+			logger.Error(err, "Failed to update directory status")
+			return ctrl.Result{}, err
+		}
+
+		// Retried once the spec of the directory changes
+		return ctrl.Result{}, nil
+	}
+
+	if err := validateReplication(directory); err != nil {
+		logger.Error(err, "invalid replication settings")
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.DirectoryAvailableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidReplication",
+			Message: err.Error(),
+		})
+
+		if err := r.Status().Update(ctx, directory); err != nil {
+			logger.Error(err, "Failed to update directory status")
+			return ctrl.Result{}, err
+		}
+
+		// Retried once the spec of the directory changes
+		return ctrl.Result{}, nil
+	}
+
 	if err := r.reconcileStatefulSet(ctx, directory); err != nil {
 		logger.Error(err, "failed to reconcile directory statefulset")
 		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
@@ -186,6 +241,82 @@ func (r *DirectoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	if ordinal, stopped := rebuildStopped(directory); stopped {
+		message := fmt.Sprintf("instance %d is stopped while its indexes are rebuilt", ordinal)
+		err := r.reconcileSlapindex(ctx, directory, ordinal)
+		if err != nil {
+			logger.Error(err, "failed to run slapindex against directory instance")
+			message = fmt.Sprintf("failed to rebuild indexes of instance %d: %s", ordinal, err.Error())
+		}
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.DirectoryAvailableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Rebuilding",
+			Message: message,
+		})
+
+		if err := r.Status().Update(ctx, directory); err != nil {
+			logger.Error(err, "Failed to update directory status")
+			return ctrl.Result{}, err
+		}
+
+		// Jobs are owned by the directory, so their completion triggers the next reconcile. Once the job has
+		// finished the stopped instances are started again straight away
+		_, stopped = rebuildStopped(directory)
+		return ctrl.Result{Requeue: !stopped}, err
+	}
+
+	if err := r.reconcileFailover(ctx, directory); err != nil {
+		logger.Error(err, "failed to fail over directory provider")
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.DirectoryAvailableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Reconciling",
+			Message: fmt.Sprintf("failed to promote provider of directory %s: %s", directory.Name, err.Error()),
+		})
+
+		if err := r.Status().Update(ctx, directory); err != nil {
+			logger.Error(err, "Failed to update directory status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileConnection(ctx, directory); err != nil {
+		logger.Error(err, "failed to reconcile directory connection details")
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.DirectoryAvailableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Reconciling",
+			Message: fmt.Sprintf("failed to publish connection details for directory %s: %s", directory.Name, err.Error()),
+		})
+
+		if err := r.Status().Update(ctx, directory); err != nil {
+			logger.Error(err, "Failed to update directory status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcilePodRoles(ctx, directory); err != nil {
+		logger.Error(err, "failed to reconcile directory pod roles")
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.DirectoryAvailableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Reconciling",
+			Message: fmt.Sprintf("failed to label pods of directory %s: %s", directory.Name, err.Error()),
+		})
+
+		if err := r.Status().Update(ctx, directory); err != nil {
+			logger.Error(err, "Failed to update directory status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
 	sts := &appsv1.StatefulSet{}
 	err := r.Get(ctx, types.NamespacedName{Name: directory.StatefulSetName(), Namespace: directory.Namespace}, sts)
 	if err != nil {
@@ -208,6 +339,133 @@ func (r *DirectoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	r.cutOverExternal(directory)
+
+	if err := r.reconcileConfig(ctx, directory); err != nil {
+		logger.Error(err, "failed to reconcile slapd config")
+		reason := "Reconciling"
+		if errors.Is(err, errInvalidReplication) {
+			reason = "InvalidReplication"
+		}
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.DirectoryAvailableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: fmt.Sprintf("failed to configure slapd for directory %s: %s", directory.Name, err.Error()),
+		})
+
+		if err := r.Status().Update(ctx, directory); err != nil {
+			logger.Error(err, "Failed to update directory status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	releaseMembers(directory)
+
+	if err := r.reconcileBootstrap(ctx, directory); err != nil {
+		logger.Error(err, "failed to reconcile directory seed data")
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.DirectoryAvailableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Reconciling",
+			Message: fmt.Sprintf("failed to load seed data into directory %s: %s", directory.Name, err.Error()),
+		})
+
+		if err := r.Status().Update(ctx, directory); err != nil {
+			logger.Error(err, "Failed to update directory status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileExternalStatus(ctx, directory); err != nil {
+		logger.Error(err, "failed to check external replication progress")
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.DirectoryAvailableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Reconciling",
+			Message: fmt.Sprintf("failed to check replication from external provider for directory %s: %s", directory.Name, err.Error()),
+		})
+
+		if err := r.Status().Update(ctx, directory); err != nil {
+			logger.Error(err, "Failed to update directory status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileIndexStatus(ctx, directory); err != nil {
+		logger.Error(err, "failed to check directory indexing progress")
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.DirectoryAvailableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Reconciling",
+			Message: fmt.Sprintf("failed to check indexing progress of directory %s: %s", directory.Name, err.Error()),
+		})
+
+		if err := r.Status().Update(ctx, directory); err != nil {
+			logger.Error(err, "Failed to update directory status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileIndexAdvisor(ctx, directory); err != nil {
+		logger.Error(err, "failed to analyse directory logs")
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.DirectoryAvailableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Reconciling",
+			Message: fmt.Sprintf("failed to suggest indexes for directory %s: %s", directory.Name, err.Error()),
+		})
+
+		if err := r.Status().Update(ctx, directory); err != nil {
+			logger.Error(err, "Failed to update directory status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileReplicationHealth(ctx, directory); err != nil {
+		logger.Error(err, "failed to check directory replication health")
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.DirectoryAvailableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Reconciling",
+			Message: fmt.Sprintf("failed to check replication health of directory %s: %s", directory.Name, err.Error()),
+		})
+
+		if err := r.Status().Update(ctx, directory); err != nil {
+			logger.Error(err, "Failed to update directory status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileRebuild(ctx, directory); err != nil {
+		logger.Error(err, "failed to rebuild directory instances")
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.DirectoryAvailableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Reconciling",
+			Message: fmt.Sprintf("failed to rebuild instances of directory %s: %s", directory.Name, err.Error()),
+		})
+
+		if err := r.Status().Update(ctx, directory); err != nil {
+			logger.Error(err, "Failed to update directory status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
 	meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
 		Type:    v1alpha1.DirectoryAvailableCondition,
 		Status:  metav1.ConditionTrue,
@@ -219,6 +477,12 @@ func (r *DirectoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	if directory.Replicated() || directory.ExternalProvider() != nil || len(directory.ReplicationPeers()) > 0 ||
+		directory.Spec.IndexAdvisor != nil || (directory.Status.Rebuild != nil && len(directory.Status.Rebuild.Remaining) > 0) ||
+		meta.IsStatusConditionTrue(directory.Status.Conditions, v1alpha1.IndexingCondition) {
+		return ctrl.Result{RequeueAfter: replicationCheckInterval}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -230,6 +494,18 @@ func (r *DirectoryReconciler) reconcileDefaults(ctx context.Context, directory *
 
 	directory.Spec.Image = image
 
+	shipping := directory.JSONLogging() || len(directory.AuditedDatabases()) > 0
+	if shipping && (directory.Spec.Logging == nil || directory.Spec.Logging.ShipperImage == "") {
+		shipperImage, found := os.LookupEnv("LOG_SHIPPER_IMAGE")
+		if !found {
+			return errors.New("Unable to find log shipper image name from env var LOG_SHIPPER_IMAGE")
+		}
+		if directory.Spec.Logging == nil {
+			directory.Spec.Logging = &v1alpha1.LoggingSpec{}
+		}
+		directory.Spec.Logging.ShipperImage = shipperImage
+	}
+
 	return r.Update(ctx, directory)
 }
 
@@ -254,26 +530,41 @@ func (r *DirectoryReconciler) reconcileSecret(ctx context.Context, directory *v1
 }
 
 func (r *DirectoryReconciler) reconcileService(ctx context.Context, directory *v1alpha1.Directory) error {
-	desired, err := r.Builder.DirectoryService(directory)
-	if err != nil {
-		return err
-	}
+	for _, build := range []func(*v1alpha1.Directory) (*corev1.Service, error){
+		r.Builder.DirectoryService,
+		r.Builder.DirectoryWriteService,
+		r.Builder.DirectoryReadService,
+		r.Builder.DirectoryHeadlessService,
+	} {
+		desired, err := build(directory)
+		if err != nil {
+			return err
+		}
 
-	existing := &corev1.Service{}
-	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
-		if !apierrors.IsNotFound(err) {
+		existing := &corev1.Service{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			if err := r.Create(ctx, desired); err != nil {
+				return err
+			}
+			continue
+		}
+
+		patch := client.MergeFrom(existing.DeepCopy())
+		existing.Labels = desired.Labels
+		existing.Spec.Ports = desired.Spec.Ports
+		existing.Spec.Type = desired.Spec.Type
+		existing.Spec.Selector = desired.Spec.Selector
+		existing.Spec.PublishNotReadyAddresses = desired.Spec.PublishNotReadyAddresses
+
+		if err := r.Patch(ctx, existing, patch); err != nil {
 			return err
 		}
-		return r.Create(ctx, desired)
 	}
 
-	patch := client.MergeFrom(existing.DeepCopy())
-	existing.Labels = desired.Labels
-	existing.Spec.Ports = desired.Spec.Ports
-	existing.Spec.Type = desired.Spec.Type
-	existing.Spec.Selector = desired.Spec.Selector
-
-	return r.Patch(ctx, existing, patch)
+	return nil
 }
 
 func (r *DirectoryReconciler) reconcileStatefulSet(ctx context.Context, directory *v1alpha1.Directory) error {
@@ -281,6 +572,7 @@ func (r *DirectoryReconciler) reconcileStatefulSet(ctx context.Context, director
 	if err != nil {
 		return err
 	}
+	desired.Spec.Replicas = ptr.To(statefulSetReplicas(directory))
 
 	existing := &appsv1.StatefulSet{}
 	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
@@ -290,10 +582,15 @@ func (r *DirectoryReconciler) reconcileStatefulSet(ctx context.Context, director
 		return r.Create(ctx, desired)
 	}
 
+	// The governing service of a statefulset is immutable. Statefulsets created before the headless service
+	// existed are replaced, orphaning their pods so that the new statefulset adopts them without a restart
+	if existing.Spec.ServiceName != desired.Spec.ServiceName {
+		return r.Delete(ctx, existing, client.PropagationPolicy(metav1.DeletePropagationOrphan))
+	}
+
 	patch := client.MergeFrom(existing.DeepCopy())
 	existing.Labels = desired.Labels
 	existing.Spec.Selector = desired.Spec.Selector
-	existing.Spec.ServiceName = desired.Spec.ServiceName
 	existing.Spec.Replicas = desired.Spec.Replicas
 	existing.Spec.Template.Spec = desired.Spec.Template.Spec
 
@@ -301,12 +598,63 @@ func (r *DirectoryReconciler) reconcileStatefulSet(ctx context.Context, director
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// Status updates made by the reconciler, such as the time replication health was last checked, don't trigger
+// another reconcile. Annotations requesting operations do
 func (r *DirectoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Directory{}).
+		For(&v1alpha1.Directory{}, ctrlbuilder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}),
+		)).
 		Named("directory").
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.StatefulSet{}).
+		Owns(&batchv1.Job{}).
+		Watches(&v1alpha1.LdapBinding{}, handler.EnqueueRequestsFromMapFunc(directoryForBinding)).
+		// Watches save their progress to their status every few seconds, which doesn't concern the directory
+		Watches(&v1alpha1.DirectoryWatch{}, handler.EnqueueRequestsFromMapFunc(directoryForWatch),
+			ctrlbuilder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
+
+// directoryDependentsPredicate passes the Directory events the objects built on a directory act on: changes to
+// its spec, conditions or connection details, and its deletion. Health checks rewrite the replication and index
+// advice status of a directory every pass, which would otherwise reconcile every dependent object against LDAP
+var directoryDependentsPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		old, ok := e.ObjectOld.(*v1alpha1.Directory)
+		if !ok {
+			return true
+		}
+		updated, ok := e.ObjectNew.(*v1alpha1.Directory)
+		if !ok {
+			return true
+		}
+		return old.Generation != updated.Generation ||
+			!old.DeletionTimestamp.Equal(updated.DeletionTimestamp) ||
+			!equality.Semantic.DeepEqual(old.Status.Conditions, updated.Status.Conditions) ||
+			!equality.Semantic.DeepEqual(old.Status.Connection, updated.Status.Connection)
+	},
+}
+
+// directoryForBinding maps a binding to the directory it references, whose access controls include the binding
+func directoryForBinding(ctx context.Context, obj client.Object) []reconcile.Request {
+	binding, ok := obj.(*v1alpha1.LdapBinding)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: binding.Spec.DirectoryRef.Name, Namespace: binding.Namespace}},
+	}
+}
+
+// directoryForWatch maps a watch to the directory it references, whose watched databases need the syncprov overlay
+func directoryForWatch(ctx context.Context, obj client.Object) []reconcile.Request {
+	watch, ok := obj.(*v1alpha1.DirectoryWatch)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: watch.Spec.DirectoryRef.Name, Namespace: watch.Namespace}},
+	}
+}
@@ -18,11 +18,13 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -81,4 +83,42 @@ var _ = Describe("Directory Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When notifying the objects built on a directory", func() {
+		var old, updated *openldapv1alpha1.Directory
+
+		BeforeEach(func() {
+			old = &openldapv1alpha1.Directory{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-directory", Namespace: "default", Generation: 1},
+				Status: openldapv1alpha1.DirectoryStatus{
+					Conditions:  []metav1.Condition{{Type: openldapv1alpha1.DirectoryAvailableCondition, Status: metav1.ConditionTrue}},
+					Replication: &openldapv1alpha1.ReplicationStatus{},
+				},
+			}
+			updated = old.DeepCopy()
+		})
+
+		passes := func() bool {
+			return directoryDependentsPredicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: updated})
+		}
+
+		It("ignores health check results", func() {
+			updated.Status.Replication.LastChecked = &metav1.Time{Time: time.Now()}
+			updated.Status.IndexAdvice = &openldapv1alpha1.IndexAdviceStatus{LastChecked: &metav1.Time{Time: time.Now()}}
+			Expect(passes()).To(BeFalse())
+		})
+
+		It("passes spec, condition and connection changes", func() {
+			updated.Generation = 2
+			Expect(passes()).To(BeTrue())
+
+			updated = old.DeepCopy()
+			updated.Status.Conditions[0].Status = metav1.ConditionFalse
+			Expect(passes()).To(BeTrue())
+
+			updated = old.DeepCopy()
+			updated.Status.Connection = &openldapv1alpha1.ConnectionStatus{}
+			Expect(passes()).To(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,210 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+// externalProvider resolves the external provider the directory replicates from along with its bind password.
+// Returns nil if the directory doesn't replicate from an external provider
+func (r *DirectoryReconciler) externalProvider(ctx context.Context, directory *v1alpha1.Directory) (*slapd.External, error) {
+	spec := directory.ExternalProvider()
+	if spec == nil {
+		return nil, nil
+	}
+
+	db := directory.Database(spec.Database)
+	if db == nil {
+		return nil, fmt.Errorf("external provider database %q not found", spec.Database)
+	}
+
+	if err := validateExternal(directory); err != nil {
+		return nil, err
+	}
+	password, err := syncreplPassword(ctx, r, directory.Namespace, &spec.PasswordSecretRef)
+	if err != nil {
+		return nil, err
+	}
+
+	external := &slapd.External{
+		Suffix:      db.Suffix,
+		URI:         spec.URI,
+		SearchBase:  spec.SearchBase,
+		BindDN:      spec.BindDN,
+		Credentials: password,
+		Retry:       spec.Retry,
+		StartTLS:    spec.StartTLS,
+	}
+	if spec.CASecretRef != nil {
+		external.CACertFile = fmt.Sprintf("%s/ca.crt", slapd.ExternalCADir)
+	}
+	return external, nil
+}
+
+// errInvalidReplication wraps replication settings which can't be written into olcSyncrepl
+var errInvalidReplication = errors.New("invalid replication settings")
+
+// validateReplication checks the settings of replication peers and the external provider before they are written
+// into olcSyncrepl, so invalid settings are reported on the directory rather than failing the cn=config write
+func validateReplication(directory *v1alpha1.Directory) error {
+	if err := validatePeers(directory); err != nil {
+		return err
+	}
+	return validateExternal(directory)
+}
+
+// validateExternal checks the settings of the external provider written into olcSyncrepl
+func validateExternal(directory *v1alpha1.Directory) error {
+	spec := directory.ExternalProvider()
+	if spec == nil {
+		return nil
+	}
+	if err := slapd.ValidateProvider(spec.URI, spec.BindDN, spec.SearchBase); err != nil {
+		return fmt.Errorf("%w: external provider: %w", errInvalidReplication, err)
+	}
+	if err := slapd.ValidateDirectiveValue("retry", spec.Retry); err != nil {
+		return fmt.Errorf("%w: external provider: %w", errInvalidReplication, err)
+	}
+	return nil
+}
+
+// syncreplPassword reads the password a provider is bound to with. A trailing line break, as left by
+// kubectl create secret --from-file, is dropped
+func syncreplPassword(ctx context.Context, c client.Reader, namespace string, selector *corev1.SecretKeySelector) (string, error) {
+	value, err := secretValue(ctx, c, namespace, selector)
+	if err != nil {
+		return "", err
+	}
+	password := strings.TrimRight(string(value), "\r\n")
+	if err := slapd.ValidateDirectiveValue(fmt.Sprintf("key %s of secret %s", selector.Key, selector.Name), password); err != nil {
+		return "", fmt.Errorf("%w: %w", errInvalidReplication, err)
+	}
+	return password, nil
+}
+
+// cutOverExternal stops replication from the external provider once the cut-over annotation is set. The
+// olcSyncrepl and olcUpdateRef of the database are removed by the following config reconcile, after which
+// the directory accepts writes
+func (r *DirectoryReconciler) cutOverExternal(directory *v1alpha1.Directory) {
+	external := directory.ExternalProvider()
+	if external == nil || directory.Annotations[v1alpha1.CutOverAnnotation] != "true" {
+		return
+	}
+
+	if directory.Status.External == nil {
+		directory.Status.External = &v1alpha1.ExternalReplicationStatus{}
+	}
+	directory.Status.External.Phase = v1alpha1.ExternalReplicationPhaseCutOver
+	directory.Status.External.CutOverAt = &metav1.Time{Time: time.Now()}
+
+	r.Recorder.Eventf(directory, corev1.EventTypeNormal, "CutOver",
+		"Stopped replicating from %s, the directory now accepts writes", external.URI)
+}
+
+// reconcileExternalStatus reports the progress of replication from the external provider. The initial load is
+// complete once the database has a contextCSN, which syncrepl only sets at the end of the refresh phase
+func (r *DirectoryReconciler) reconcileExternalStatus(ctx context.Context, directory *v1alpha1.Directory) error {
+	if directory.Spec.Replication == nil || directory.Spec.Replication.External == nil {
+		directory.Status.External = nil
+		return nil
+	}
+
+	spec := directory.ExternalProvider()
+	if spec == nil {
+		return nil
+	}
+
+	db := directory.Database(spec.Database)
+	if db == nil {
+		return fmt.Errorf("external provider database %q not found", spec.Database)
+	}
+
+	conn, err := connectDirectory(ctx, r, directory, db.RootDN())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	csns, err := conn.ContextCSNs(db.Suffix)
+	if err != nil {
+		return err
+	}
+
+	if directory.Status.External == nil {
+		directory.Status.External = &v1alpha1.ExternalReplicationStatus{}
+	}
+	status := directory.Status.External
+
+	if len(csns) == 0 {
+		status.Phase = v1alpha1.ExternalReplicationPhaseInitialLoad
+		status.ContextCSN = nil
+	} else {
+		if status.Phase != v1alpha1.ExternalReplicationPhaseReplicating {
+			r.Recorder.Eventf(directory, corev1.EventTypeNormal, "InitialLoadComplete",
+				"Completed initial load from %s", spec.URI)
+		}
+		status.Phase = v1alpha1.ExternalReplicationPhaseReplicating
+		status.ContextCSN = []string{}
+		for _, csn := range csns {
+			status.ContextCSN = append(status.ContextCSN, csn.String())
+		}
+	}
+
+	// The entry count is read from the monitor database, which only the config root DN can read, rather than
+	// searching the whole database on every check
+	monitor, err := connectDirectory(ctx, r, directory, slapd.ConfigRootDN)
+	if err != nil {
+		return err
+	}
+	defer monitor.Close()
+
+	entries, found, err := monitor.DatabaseEntries(db.Suffix)
+	if err != nil {
+		return err
+	}
+	if found {
+		status.Entries = entries
+	}
+
+	return nil
+}
+
+// secretValue returns the value of a key of a secret in namespace
+func secretValue(ctx context.Context, c client.Reader, namespace string, selector *corev1.SecretKeySelector) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: selector.Name, Namespace: namespace}, secret); err != nil {
+		return nil, err
+	}
+	value, found := secret.Data[selector.Key]
+	if !found {
+		return nil, fmt.Errorf("secret %s does not contain %s", selector.Name, selector.Key)
+	}
+	return value, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+// failoverRequest returns the ordinal a new promotion requests along with its trigger, recording the request as
+// acted upon. Returns false if neither the promote annotation nor spec.replication.provider changed
+func failoverRequest(directory *v1alpha1.Directory) (int32, string, bool) {
+	status := directory.Status.Replication
+	if status == nil {
+		return 0, "", false
+	}
+	annotation := directory.Annotations[v1alpha1.PromoteAnnotation]
+	var spec *int32
+	if directory.Spec.Replication != nil {
+		spec = directory.Spec.Replication.Provider
+	}
+
+	switch {
+	case annotation != "" && annotation != status.Promotion:
+		status.Promotion = annotation
+		ordinal, err := strconv.ParseInt(annotation, 10, 32)
+		if err != nil {
+			return -1, "annotation", true
+		}
+		return int32(ordinal), "annotation", true
+	case spec != nil && (status.ObservedProvider == nil || *spec != *status.ObservedProvider):
+		status.ObservedProvider = ptr.To(*spec)
+		return *spec, "spec", true
+	}
+	return 0, "", false
+}
+
+// scaleDownPromotion returns the instance to promote when a forced scale-down of a provider/consumer directory
+// would remove its provider. The first instance survives every scale-down, so it takes over
+func scaleDownPromotion(directory *v1alpha1.Directory) (int32, bool) {
+	target, _ := replicationTarget(directory)
+	return 0, removesProvider(directory, target)
+}
+
+// reconcileFailover promotes a consumer of a provider/consumer directory to provider when requested through the
+// promote annotation or spec.replication.provider. The promoted instance is reconfigured first, followed by
+// the other instances, which are repointed at it. The previous provider is usually down, so instances which
+// can't be reached are left for later reconciles. Once relabelled, the write service selects the promoted
+// instance. Writes the previous provider accepted but hadn't replicated are lost. A forced scale-down removing
+// the provider promotes a surviving instance the same way, before the departing instances are released
+func (r *DirectoryReconciler) reconcileFailover(ctx context.Context, directory *v1alpha1.Directory) error {
+	logger := log.FromContext(ctx)
+	if directory.Status.Replication == nil {
+		directory.Status.Replication = &v1alpha1.ReplicationStatus{}
+	}
+	status := directory.Status.Replication
+
+	// Requests made before the provider was first recorded choose the initial provider rather than failing over
+	if status.Provider == nil {
+		if directory.Spec.Replication != nil && directory.Spec.Replication.Provider != nil {
+			status.Provider = ptr.To(*directory.Spec.Replication.Provider)
+			status.ObservedProvider = ptr.To(*directory.Spec.Replication.Provider)
+		} else {
+			status.Provider = ptr.To(int32(0))
+		}
+		status.Promotion = directory.Annotations[v1alpha1.PromoteAnnotation]
+		return nil
+	}
+
+	previous := *status
+	ordinal, trigger, requested := failoverRequest(directory)
+	if !requested {
+		ordinal, requested = scaleDownPromotion(directory)
+		trigger = "scale-down"
+	}
+	if !requested || ordinal == directory.ProviderOrdinal() {
+		return nil
+	}
+
+	if directory.ReplicationTopology() != v1alpha1.ReplicationTopologyProviderConsumer {
+		r.Recorder.Eventf(directory, corev1.EventTypeWarning, "PromotionFailed",
+			"Every instance of a multi-provider directory accepts writes, ignoring promotion of instance %d", ordinal)
+		return nil
+	}
+	if target, _ := replicationTarget(directory); ordinal < 0 || ordinal >= target {
+		value := strconv.Itoa(int(ordinal))
+		if trigger == "annotation" {
+			value = directory.Annotations[v1alpha1.PromoteAnnotation]
+		}
+		r.Recorder.Eventf(directory, corev1.EventTypeWarning, "PromotionFailed",
+			"Requested provider %q is not an instance of the directory", value)
+		return nil
+	}
+
+	status.Provider = ptr.To(ordinal)
+	if err := r.configureInstances(ctx, directory, func(pod *corev1.Pod, err error) error {
+		if podOrdinal(pod) == int(ordinal) {
+			return err
+		}
+		logger.Error(err, "failed to reconfigure instance after failover, retrying once it is running", "pod", pod.Name)
+		return nil
+	}); err != nil {
+		// Leave the request pending so that the promotion is retried
+		*status = previous
+		return err
+	}
+
+	status.LastFailover = &v1alpha1.FailoverStatus{
+		From:    *previous.Provider,
+		To:      ordinal,
+		Trigger: trigger,
+		Time:    metav1.Time{Time: time.Now()},
+	}
+	r.Recorder.Eventf(directory, corev1.EventTypeNormal, "Failover",
+		"Promoted instance %d to provider, replacing instance %d", ordinal, *previous.Provider)
+
+	return nil
+}
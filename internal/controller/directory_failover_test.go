@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	openldapv1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+var _ = Describe("Directory Failover", func() {
+	var reconciler *DirectoryReconciler
+	var recorder *record.FakeRecorder
+	var directory *openldapv1alpha1.Directory
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &DirectoryReconciler{Recorder: recorder}
+		directory = &openldapv1alpha1.Directory{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-directory",
+				Namespace: "default",
+			},
+			Spec: openldapv1alpha1.DirectorySpec{
+				Replicas: 3,
+				Replication: &openldapv1alpha1.ReplicationSpec{
+					Topology: openldapv1alpha1.ReplicationTopologyProviderConsumer,
+				},
+			},
+		}
+		reconciler.reconcileMembers(directory)
+		Expect(reconciler.reconcileFailover(context.Background(), directory)).To(Succeed())
+	})
+
+	It("records the provider before members are allocated", func() {
+		directory.Status.Replication = nil
+		_, _, requested := failoverRequest(directory)
+		Expect(requested).To(BeFalse())
+		Expect(reconciler.reconcileFailover(context.Background(), directory)).To(Succeed())
+		Expect(directory.Status.Replication.Provider).To(Equal(ptr.To(int32(0))))
+	})
+
+	It("records the first instance as provider", func() {
+		Expect(directory.Status.Replication.Provider).To(Equal(ptr.To(int32(0))))
+		Expect(directory.PodRole(0)).To(Equal(openldapv1alpha1.RoleProvider))
+		Expect(directory.PodRole(1)).To(Equal(openldapv1alpha1.RoleConsumer))
+	})
+
+	It("makes the recorded provider the only provider", func() {
+		directory.Status.Replication.Provider = ptr.To(int32(2))
+		Expect(directory.PodRole(0)).To(Equal(openldapv1alpha1.RoleConsumer))
+		Expect(directory.PodRole(2)).To(Equal(openldapv1alpha1.RoleProvider))
+	})
+
+	It("requests a promotion when the annotation changes", func() {
+		directory.Annotations = map[string]string{openldapv1alpha1.PromoteAnnotation: "1"}
+		ordinal, trigger, requested := failoverRequest(directory)
+		Expect(requested).To(BeTrue())
+		Expect(ordinal).To(Equal(int32(1)))
+		Expect(trigger).To(Equal("annotation"))
+
+		_, _, requested = failoverRequest(directory)
+		Expect(requested).To(BeFalse())
+	})
+
+	It("requests a promotion when spec.replication.provider changes", func() {
+		directory.Spec.Replication.Provider = ptr.To(int32(2))
+		ordinal, trigger, requested := failoverRequest(directory)
+		Expect(requested).To(BeTrue())
+		Expect(ordinal).To(Equal(int32(2)))
+		Expect(trigger).To(Equal("spec"))
+
+		_, _, requested = failoverRequest(directory)
+		Expect(requested).To(BeFalse())
+	})
+
+	It("ignores promotions of instances which aren't members", func() {
+		directory.Annotations = map[string]string{openldapv1alpha1.PromoteAnnotation: "5"}
+		Expect(reconciler.reconcileFailover(context.Background(), directory)).To(Succeed())
+		Expect(directory.ProviderOrdinal()).To(Equal(int32(0)))
+		Expect(recorder.Events).To(Receive(ContainSubstring("PromotionFailed")))
+	})
+
+	It("ignores promotions in multi-provider directories", func() {
+		directory.Spec.Replication.Topology = openldapv1alpha1.ReplicationTopologyMultiProvider
+		directory.Annotations = map[string]string{openldapv1alpha1.PromoteAnnotation: "1"}
+		Expect(reconciler.reconcileFailover(context.Background(), directory)).To(Succeed())
+		Expect(directory.ProviderOrdinal()).To(Equal(int32(0)))
+		Expect(recorder.Events).To(Receive(ContainSubstring("PromotionFailed")))
+	})
+
+	It("blocks scale-downs removing the provider", func() {
+		directory.Status.Replication.Provider = ptr.To(int32(2))
+		directory.Spec.Replicas = 2
+		reconciler.reconcileMembers(directory)
+
+		target, blocked := replicationTarget(directory)
+		Expect(blocked).To(BeTrue())
+		Expect(target).To(Equal(int32(3)))
+		condition := meta.FindStatusCondition(directory.Status.Conditions, openldapv1alpha1.ScaleDownBlockedCondition)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal("ProviderRemoved"))
+	})
+
+	It("promotes a surviving instance before a forced scale-down removes the provider", func() {
+		directory.Status.Replication.Provider = ptr.To(int32(2))
+		directory.Spec.Replicas = 2
+		directory.Annotations = map[string]string{openldapv1alpha1.ForceScaleDownAnnotation: "true"}
+		Expect(reconciler.reconcileMembers(directory)).To(Succeed())
+
+		ordinal, requested := scaleDownPromotion(directory)
+		Expect(requested).To(BeTrue())
+		Expect(ordinal).To(Equal(int32(0)))
+		Expect(statefulSetReplicas(directory)).To(Equal(int32(3)))
+
+		By("leaving the provider alone when it survives")
+		directory.Status.Replication.Provider = ptr.To(int32(1))
+		_, requested = scaleDownPromotion(directory)
+		Expect(requested).To(BeFalse())
+	})
+})
@@ -0,0 +1,323 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+// directoryIndexed returns true if any database of the directory has configured indexes
+func directoryIndexed(directory *v1alpha1.Directory) bool {
+	for _, db := range directory.Spec.SlapdConfig.Databases {
+		if len(db.Indexes) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileIndexStatus reports which instances are still building indexes added to each database. slapd builds
+// indexes added through cn=config online, listing a task per database in the monitor database until done
+func (r *DirectoryReconciler) reconcileIndexStatus(ctx context.Context, directory *v1alpha1.Directory) error {
+	if !directoryIndexed(directory) {
+		directory.Status.Indexes = nil
+		meta.RemoveStatusCondition(&directory.Status.Conditions, v1alpha1.IndexingCondition)
+		return nil
+	}
+
+	password, err := adminPassword(ctx, r, directory)
+	if err != nil {
+		return err
+	}
+
+	pods, err := r.directoryPods(ctx, directory)
+	if err != nil {
+		return err
+	}
+
+	indexing := map[string][]string{}
+	applied := map[string][]string{}
+	for i := range pods {
+		suffixes, err := podIndexing(&pods[i], password, directory.Spec.SlapdConfig.Databases, applied)
+		if err != nil {
+			return fmt.Errorf("failed to read indexing tasks of %s: %w", pods[i].Name, err)
+		}
+		for suffix := range suffixes {
+			indexing[suffix] = append(indexing[suffix], pods[i].Name)
+		}
+	}
+
+	previous := map[string]v1alpha1.DatabaseIndexStatus{}
+	for _, status := range directory.Status.Indexes {
+		previous[status.Database] = status
+	}
+
+	statuses := []v1alpha1.DatabaseIndexStatus{}
+	busy := []string{}
+	for i := range directory.Spec.SlapdConfig.Databases {
+		db := &directory.Spec.SlapdConfig.Databases[i]
+		status := v1alpha1.DatabaseIndexStatus{
+			Database:    db.Name,
+			Indexes:     applied[db.Name],
+			Indexing:    indexing[strings.ToLower(db.Suffix)],
+			CompletedAt: previous[db.Name].CompletedAt,
+		}
+		if len(status.Indexing) > 0 {
+			busy = append(busy, db.Name)
+		} else if len(previous[db.Name].Indexing) > 0 || status.CompletedAt == nil {
+			status.CompletedAt = &metav1.Time{Time: time.Now()}
+			if len(previous[db.Name].Indexing) > 0 {
+				r.Recorder.Eventf(directory, corev1.EventTypeNormal, "IndexingComplete",
+					"Completed building indexes of database %s", db.Name)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	directory.Status.Indexes = statuses
+
+	if len(busy) > 0 {
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.IndexingCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Indexing",
+			Message: fmt.Sprintf("building indexes of %s", strings.Join(busy, ", ")),
+		})
+	} else {
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.IndexingCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Indexed",
+			Message: "all indexes are built",
+		})
+	}
+
+	return nil
+}
+
+// podIndexing returns the suffixes of the databases the instance running in pod is indexing. The olcDbIndex
+// values of each database are recorded in applied unless already read from another instance
+func podIndexing(pod *corev1.Pod, password []byte, dbs []v1alpha1.DatabaseSpec, applied map[string][]string) (map[string]bool, error) {
+	conn, err := slapd.Connect(slapd.PodURI(pod.Status.PodIP), slapd.ConfigRootDN, string(password))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	for _, db := range dbs {
+		if _, found := applied[db.Name]; found {
+			continue
+		}
+		indexes, err := conn.DatabaseIndexes(db.Suffix)
+		if err != nil {
+			return nil, err
+		}
+		applied[db.Name] = indexes
+	}
+
+	return conn.IndexingSuffixes()
+}
+
+// reconcileRebuild rebuilds every index of the instances of a directory one at a time when the rebuild
+// annotation changes. Instances with persistent storage are stopped in turn while a slapindex job runs against
+// their volume, see reconcileSlapindex. Otherwise instances keep their databases on emptyDir volumes which a
+// job can't reach, so instead each instance of a replicated directory is recreated and reloaded from the
+// others. The next instance is only deleted once the previous one is back in sync. The provider of a
+// provider/consumer directory isn't reloaded, as its consumers have nothing to reload it from; fail over first
+// to rebuild it
+func (r *DirectoryReconciler) reconcileRebuild(ctx context.Context, directory *v1alpha1.Directory) error {
+	request := directory.Annotations[v1alpha1.RebuildAnnotation]
+	if request != "" && (directory.Status.Rebuild == nil || request != directory.Status.Rebuild.Request) {
+		directory.Status.Rebuild = &v1alpha1.RebuildStatus{
+			Request:   request,
+			Remaining: rebuildOrdinals(directory),
+			StartedAt: &metav1.Time{Time: time.Now()},
+		}
+		if directory.Spec.Storage == nil && !directory.Replicated() {
+			directory.Status.Rebuild.Remaining = nil
+			r.Recorder.Eventf(directory, corev1.EventTypeWarning, "RebuildFailed",
+				"A directory with a single instance and no persistent storage has nothing to rebuild from")
+			return nil
+		}
+		r.Recorder.Eventf(directory, corev1.EventTypeNormal, "RebuildStarted",
+			"Rebuilding instances %v one at a time", directory.Status.Rebuild.Remaining)
+	}
+
+	rebuild := directory.Status.Rebuild
+	if rebuild == nil || len(rebuild.Remaining) == 0 {
+		return nil
+	}
+
+	// Instances are only stopped while the others are healthy, leaving the directory to serve from them
+	if directory.Spec.Storage != nil {
+		if rebuild.StoppedAt == nil && (!directory.Replicated() ||
+			meta.IsStatusConditionTrue(directory.Status.Conditions, v1alpha1.ReplicationHealthyCondition)) {
+			rebuild.StoppedAt = &metav1.Time{Time: time.Now()}
+		}
+		return nil
+	}
+
+	name := fmt.Sprintf("%s-%d", directory.StatefulSetName(), rebuild.Remaining[0])
+	pod := &corev1.Pod{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: directory.Namespace}, pod); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if rebuild.DeletedAt == nil {
+		if !meta.IsStatusConditionTrue(directory.Status.Conditions, v1alpha1.ReplicationHealthyCondition) {
+			return nil
+		}
+		if err := r.Delete(ctx, pod); err != nil {
+			return err
+		}
+		rebuild.DeletedAt = &metav1.Time{Time: time.Now()}
+		return nil
+	}
+
+	if pod.CreationTimestamp.Before(rebuild.DeletedAt) || !replicaInSync(directory, name) {
+		return nil
+	}
+
+	rebuild.Remaining = rebuild.Remaining[1:]
+	rebuild.DeletedAt = nil
+	if len(rebuild.Remaining) == 0 {
+		rebuild.CompletedAt = &metav1.Time{Time: time.Now()}
+		r.Recorder.Eventf(directory, corev1.EventTypeNormal, "RebuildComplete", "Rebuilt every instance")
+	}
+	return nil
+}
+
+// rebuildStopped returns the ordinal of the instance stopped to run slapindex against its volume, if any
+func rebuildStopped(directory *v1alpha1.Directory) (int32, bool) {
+	rebuild := directory.Status.Rebuild
+	if directory.Spec.Storage == nil || rebuild == nil || rebuild.StoppedAt == nil || len(rebuild.Remaining) == 0 {
+		return 0, false
+	}
+	return rebuild.Remaining[0], true
+}
+
+// reconcileSlapindex runs slapindex against the volume of the instance with the given ordinal once
+// reconcileRebuild has stopped it. Statefulsets only stop instances from the highest ordinal down, so the
+// instances above it, already rebuilt, are stopped alongside it. The job is deleted once it finishes, after
+// which the statefulset starts the stopped instances again
+func (r *DirectoryReconciler) reconcileSlapindex(ctx context.Context, directory *v1alpha1.Directory, ordinal int32) error {
+	rebuild := directory.Status.Rebuild
+
+	// The job waits for the instance to shut down, as slapd and slapindex can't open the databases together
+	pod := &corev1.Pod{}
+	name := fmt.Sprintf("%s-%d", directory.StatefulSetName(), ordinal)
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: directory.Namespace}, pod); err == nil {
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	desired, err := r.Builder.SlapindexJob(directory, ordinal)
+	if err != nil {
+		return err
+	}
+
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), job); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return r.Create(ctx, desired)
+	}
+
+	// Jobs left over from an earlier request are replaced
+	if job.CreationTimestamp.Before(rebuild.StoppedAt) {
+		return client.IgnoreNotFound(r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)))
+	}
+
+	var finished *batchv1.JobCondition
+	for i := range job.Status.Conditions {
+		condition := &job.Status.Conditions[i]
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) &&
+			condition.Status == corev1.ConditionTrue {
+			finished = condition
+		}
+	}
+	if finished == nil {
+		return nil
+	}
+
+	if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	rebuild.StoppedAt = nil
+	if finished.Type == batchv1.JobFailed {
+		rebuild.Remaining = nil
+		r.Recorder.Eventf(directory, corev1.EventTypeWarning, "RebuildFailed",
+			"slapindex failed on instance %d: %s", ordinal, finished.Message)
+		return nil
+	}
+
+	rebuild.Remaining = rebuild.Remaining[1:]
+	if len(rebuild.Remaining) == 0 {
+		rebuild.CompletedAt = &metav1.Time{Time: time.Now()}
+		r.Recorder.Eventf(directory, corev1.EventTypeNormal, "RebuildComplete", "Rebuilt every instance")
+	}
+	return nil
+}
+
+// rebuildOrdinals returns the ordinals of the instances a rebuild covers, in descending order
+func rebuildOrdinals(directory *v1alpha1.Directory) []int32 {
+	ordinals := []int32{}
+	for _, member := range directory.Members() {
+		if directory.Spec.Storage == nil && directory.PodRole(int(member.Ordinal)) == v1alpha1.RoleProvider &&
+			directory.ReplicationTopology() == v1alpha1.ReplicationTopologyProviderConsumer {
+			continue
+		}
+		ordinals = append(ordinals, member.Ordinal)
+	}
+	sort.Slice(ordinals, func(i, j int) bool {
+		return ordinals[i] > ordinals[j]
+	})
+	return ordinals
+}
+
+// replicaInSync returns true if the last replication check found the instance running in the pod in sync
+func replicaInSync(directory *v1alpha1.Directory, pod string) bool {
+	if directory.Status.Replication == nil {
+		return false
+	}
+	for _, replica := range directory.Status.Replication.Replicas {
+		if replica.Pod == pod {
+			return replica.InSync && replica.Message == ""
+		}
+	}
+	return false
+}
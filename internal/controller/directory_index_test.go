@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	openldapv1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+var _ = Describe("Directory Rebuild", func() {
+	var reconciler *DirectoryReconciler
+	var recorder *record.FakeRecorder
+	var directory *openldapv1alpha1.Directory
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &DirectoryReconciler{Recorder: recorder}
+		directory = &openldapv1alpha1.Directory{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-directory",
+				Namespace: "default",
+			},
+			Spec: openldapv1alpha1.DirectorySpec{
+				Replicas: 3,
+			},
+		}
+		reconciler.reconcileMembers(directory)
+	})
+
+	It("rebuilds every instance of a multi-provider directory", func() {
+		Expect(rebuildOrdinals(directory)).To(Equal([]int32{2, 1, 0}))
+	})
+
+	It("doesn't rebuild the provider of a provider/consumer directory", func() {
+		directory.Spec.Replication = &openldapv1alpha1.ReplicationSpec{
+			Topology: openldapv1alpha1.ReplicationTopologyProviderConsumer,
+		}
+		Expect(rebuildOrdinals(directory)).To(Equal([]int32{2, 1}))
+	})
+
+	It("refuses to rebuild a single instance", func() {
+		directory.Spec.Replicas = 1
+		directory.Status.Replication.Members = directory.Status.Replication.Members[:1]
+		directory.Annotations = map[string]string{openldapv1alpha1.RebuildAnnotation: "1"}
+
+		Expect(reconciler.reconcileRebuild(context.Background(), directory)).To(Succeed())
+		Expect(directory.Status.Rebuild.Request).To(Equal("1"))
+		Expect(directory.Status.Rebuild.Remaining).To(BeEmpty())
+		Expect(recorder.Events).To(Receive(ContainSubstring("RebuildFailed")))
+	})
+
+	Context("with persistent storage", func() {
+		BeforeEach(func() {
+			directory.Spec.Storage = &openldapv1alpha1.StorageSpec{Size: resource.MustParse("1Gi")}
+		})
+
+		It("rebuilds the provider of a provider/consumer directory", func() {
+			directory.Spec.Replication = &openldapv1alpha1.ReplicationSpec{
+				Topology: openldapv1alpha1.ReplicationTopologyProviderConsumer,
+			}
+			Expect(rebuildOrdinals(directory)).To(Equal([]int32{2, 1, 0}))
+		})
+
+		It("stops a single instance to run slapindex against its volume", func() {
+			directory.Spec.Replicas = 1
+			directory.Status.Replication.Members = directory.Status.Replication.Members[:1]
+			directory.Annotations = map[string]string{openldapv1alpha1.RebuildAnnotation: "1"}
+
+			Expect(reconciler.reconcileRebuild(context.Background(), directory)).To(Succeed())
+			Expect(directory.Status.Rebuild.Remaining).To(Equal([]int32{0}))
+			Expect(directory.Status.Rebuild.StoppedAt).ToNot(BeNil())
+			Expect(recorder.Events).To(Receive(ContainSubstring("RebuildStarted")))
+
+			ordinal, stopped := rebuildStopped(directory)
+			Expect(stopped).To(BeTrue())
+			Expect(ordinal).To(Equal(int32(0)))
+			Expect(statefulSetReplicas(directory)).To(Equal(int32(0)))
+		})
+
+		It("only stops an instance of a replicated directory while replication is healthy", func() {
+			directory.Annotations = map[string]string{openldapv1alpha1.RebuildAnnotation: "1"}
+
+			Expect(reconciler.reconcileRebuild(context.Background(), directory)).To(Succeed())
+			Expect(directory.Status.Rebuild.StoppedAt).To(BeNil())
+			Expect(statefulSetReplicas(directory)).To(Equal(int32(3)))
+
+			meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+				Type:   openldapv1alpha1.ReplicationHealthyCondition,
+				Status: metav1.ConditionTrue,
+				Reason: "InSync",
+			})
+			Expect(reconciler.reconcileRebuild(context.Background(), directory)).To(Succeed())
+			Expect(directory.Status.Rebuild.StoppedAt).ToNot(BeNil())
+			Expect(statefulSetReplicas(directory)).To(Equal(int32(2)))
+		})
+	})
+})
@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+// peerServerIDs returns the server IDs held by the replication peers of the directory
+func peerServerIDs(directory *v1alpha1.Directory) map[int32]bool {
+	ids := map[int32]bool{}
+	for _, peer := range directory.ReplicationPeers() {
+		ids[peer.ServerID] = true
+	}
+	return ids
+}
+
+// validateServerIDs checks that the server IDs of replication peers lie outside the range allocated to the
+// instances of the directory. The peers of another region allocate their own instances from a different range,
+// so a peer within the range would collide with a local instance sooner or later
+func validateServerIDs(directory *v1alpha1.Directory) error {
+	ids := directory.ServerIDRange()
+	if ids.Start > ids.End {
+		return fmt.Errorf("server ID range %d-%d is empty", ids.Start, ids.End)
+	}
+	for _, peer := range directory.ReplicationPeers() {
+		if ids.Contains(peer.ServerID) {
+			return fmt.Errorf("server ID %d of peer %s lies within the server ID range %d-%d of the directory. "+
+				"Configure a range for the directory disjoint from the ranges of its peers", peer.ServerID, peer.Name, ids.Start, ids.End)
+		}
+	}
+	return nil
+}
+
+// validatePeers checks that the server ID of each replication peer is unique across the peers and members
+// of the directory, that the replicated database exists and that the connection settings of the peer can be
+// written into olcSyncrepl
+func validatePeers(directory *v1alpha1.Directory) error {
+	if err := validateServerIDs(directory); err != nil {
+		return err
+	}
+
+	held := map[int32]string{}
+	for _, member := range directory.Members() {
+		held[member.ServerID] = fmt.Sprintf("instance %d", member.Ordinal)
+	}
+	for _, peer := range directory.ReplicationPeers() {
+		if holder, found := held[peer.ServerID]; found {
+			return fmt.Errorf("server ID %d of peer %s is already held by %s", peer.ServerID, peer.Name, holder)
+		}
+		held[peer.ServerID] = fmt.Sprintf("peer %s", peer.Name)
+
+		if directory.Database(peer.Database) == nil {
+			return fmt.Errorf("database %q of peer %s not found", peer.Database, peer.Name)
+		}
+		if err := slapd.ValidateProvider(peer.URI, peer.BindDN, ""); err != nil {
+			return fmt.Errorf("%w: peer %s: %w", errInvalidReplication, peer.Name, err)
+		}
+	}
+	return nil
+}
+
+// replicationPeers resolves the replication peers of the directory along with their bind passwords
+func (r *DirectoryReconciler) replicationPeers(ctx context.Context, directory *v1alpha1.Directory) ([]slapd.Peer, error) {
+	if err := validatePeers(directory); err != nil {
+		return nil, err
+	}
+
+	peers := []slapd.Peer{}
+	for _, spec := range directory.ReplicationPeers() {
+		password, err := syncreplPassword(ctx, r, directory.Namespace, &spec.PasswordSecretRef)
+		if err != nil {
+			return nil, err
+		}
+
+		peer := slapd.Peer{
+			ServerID:    int(spec.ServerID),
+			URI:         spec.URI,
+			Suffix:      directory.Database(spec.Database).Suffix,
+			BindDN:      spec.BindDN,
+			Credentials: password,
+			StartTLS:    spec.StartTLS,
+		}
+		if spec.CASecretRef != nil {
+			peer.CACertFile = slapd.PeerCACertFile(spec.Name)
+		}
+		peers = append(peers, peer)
+	}
+	return peers, nil
+}
+
+// peerStatuses checks that the operator can bind to each replication peer and reads the context CSNs of the
+// replicated database. Peers are reached from the operator rather than the instances, so a reachable peer
+// may still be unreachable from the instances if network policies differ
+func (r *DirectoryReconciler) peerStatuses(ctx context.Context, directory *v1alpha1.Directory) []v1alpha1.PeerStatus {
+	statuses := []v1alpha1.PeerStatus{}
+	for _, spec := range directory.ReplicationPeers() {
+		status := v1alpha1.PeerStatus{Name: spec.Name}
+		if err := r.probePeer(ctx, directory, &spec, &status); err != nil {
+			status.Message = err.Error()
+		}
+
+		reachable := 0.0
+		if status.Reachable {
+			reachable = 1
+		}
+		replicationPeerReachable.WithLabelValues(directory.Namespace, directory.Name, spec.Name).Set(reachable)
+
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// probePeer binds to a replication peer and records its context CSNs in status
+func (r *DirectoryReconciler) probePeer(ctx context.Context, directory *v1alpha1.Directory, spec *v1alpha1.ReplicationPeerSpec,
+	status *v1alpha1.PeerStatus) error {
+	password, err := syncreplPassword(ctx, r, directory.Namespace, &spec.PasswordSecretRef)
+	if err != nil {
+		return err
+	}
+
+	var caCert []byte
+	if spec.CASecretRef != nil {
+		if caCert, err = secretValue(ctx, r, directory.Namespace, spec.CASecretRef); err != nil {
+			return err
+		}
+	}
+
+	conn, err := slapd.ConnectTLS(spec.URI, spec.BindDN, password, caCert, spec.StartTLS)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	status.Reachable = true
+
+	db := directory.Database(spec.Database)
+	if db == nil {
+		return fmt.Errorf("database %q not found", spec.Database)
+	}
+	csns, err := conn.ContextCSNs(db.Suffix)
+	if err != nil {
+		return fmt.Errorf("failed to read contextCSN of %s: %w", db.Name, err)
+	}
+	for _, csn := range csns {
+		status.ContextCSN = append(status.ContextCSN, csn.String())
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+const (
+	// replicationCheckInterval is how often the sync state of replicated directories is checked
+	replicationCheckInterval = 30 * time.Second
+)
+
+// replicationTarget returns the number of instances replication is configured for and whether a scale-down
+// is being held back. Unless forced, a scale-down may not remove the provider of a provider/consumer topology
+// or a multi-provider member which the last check found out of sync, as it may hold changes the others lack
+func replicationTarget(directory *v1alpha1.Directory) (int32, bool) {
+	desired := directory.Spec.Replicas
+	if desired < 1 {
+		desired = 1
+	}
+
+	current := int32(len(directory.Members()))
+	if desired >= current || directory.Annotations[v1alpha1.ForceScaleDownAnnotation] == "true" {
+		return desired, false
+	}
+	if removesProvider(directory, desired) || len(removedUnsynced(directory, desired)) > 0 {
+		return current, true
+	}
+	return desired, false
+}
+
+// removesProvider returns true if scaling a provider/consumer directory down to replicas would remove its provider
+func removesProvider(directory *v1alpha1.Directory, replicas int32) bool {
+	return directory.ReplicationTopology() == v1alpha1.ReplicationTopologyProviderConsumer &&
+		directory.ProviderOrdinal() >= replicas
+}
+
+// removedUnsynced returns the pods of multi-provider members which scaling down to replicas would remove while
+// the last check found them out of sync
+func removedUnsynced(directory *v1alpha1.Directory, replicas int32) []string {
+	if directory.ReplicationTopology() != v1alpha1.ReplicationTopologyMultiProvider || directory.Status.Replication == nil {
+		return nil
+	}
+
+	departing := map[string]bool{}
+	for _, member := range directory.Members() {
+		if member.Ordinal >= replicas {
+			departing[fmt.Sprintf("%s-%d", directory.StatefulSetName(), member.Ordinal)] = true
+		}
+	}
+
+	pods := []string{}
+	for _, replica := range directory.Status.Replication.Replicas {
+		if departing[replica.Pod] && !replica.InSync {
+			pods = append(pods, replica.Pod)
+		}
+	}
+	return pods
+}
+
+// statefulSetReplicas returns the number of replicas of the directory statefulset. Departing members keep
+// running until they have been removed from the replication config of the remaining members. Instances from
+// the one being rebuilt by slapindex up are stopped
+func statefulSetReplicas(directory *v1alpha1.Directory) int32 {
+	if ordinal, stopped := rebuildStopped(directory); stopped {
+		return ordinal
+	}
+
+	replicas, _ := replicationTarget(directory)
+	for _, member := range directory.Members() {
+		if member.Ordinal+1 > replicas {
+			replicas = member.Ordinal + 1
+		}
+	}
+	return replicas
+}
+
+// reconcileMembers allocates server IDs to new members and reports scale-downs which are held back
+func (r *DirectoryReconciler) reconcileMembers(directory *v1alpha1.Directory) error {
+	if err := validateServerIDs(directory); err != nil {
+		return err
+	}
+
+	target, blocked := replicationTarget(directory)
+	switch {
+	case blocked && removesProvider(directory, directory.Spec.Replicas):
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:   v1alpha1.ScaleDownBlockedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: "ProviderRemoved",
+			Message: fmt.Sprintf("scaling down to %d replicas would remove the provider, instance %d. Promote a remaining "+
+				"instance first or set the %s annotation", directory.Spec.Replicas, directory.ProviderOrdinal(), v1alpha1.ForceScaleDownAnnotation),
+		})
+	case blocked:
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:   v1alpha1.ScaleDownBlockedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: "ReplicasOutOfSync",
+			Message: fmt.Sprintf("scaling down to %d replicas would remove %s, which are out of sync. Wait for them to "+
+				"catch up or set the %s annotation", directory.Spec.Replicas,
+				strings.Join(removedUnsynced(directory, directory.Spec.Replicas), ", "), v1alpha1.ForceScaleDownAnnotation),
+		})
+	default:
+		meta.RemoveStatusCondition(&directory.Status.Conditions, v1alpha1.ScaleDownBlockedCondition)
+	}
+
+	if directory.Status.Replication == nil {
+		directory.Status.Replication = &v1alpha1.ReplicationStatus{}
+	}
+	for ordinal := 0; ordinal < int(target); ordinal++ {
+		if directory.ServerID(ordinal) != 0 {
+			continue
+		}
+		id := allocateServerID(directory.Status.Replication, directory.ServerIDRange(), peerServerIDs(directory))
+		if id == 0 {
+			ids := directory.ServerIDRange()
+			return fmt.Errorf("no free server ID left in the range %d-%d for instance %d", ids.Start, ids.End, ordinal)
+		}
+		directory.Status.Replication.Members = append(directory.Status.Replication.Members, v1alpha1.ReplicationMember{
+			Ordinal:  int32(ordinal),
+			ServerID: id,
+		})
+	}
+	return nil
+}
+
+// releaseMembers removes members which are no longer part of the target topology. Called once the remaining
+// members have stopped replicating from them, after which the statefulset is scaled down
+func releaseMembers(directory *v1alpha1.Directory) {
+	if directory.Status.Replication == nil {
+		return
+	}
+
+	target, _ := replicationTarget(directory)
+	members := []v1alpha1.ReplicationMember{}
+	for _, member := range directory.Status.Replication.Members {
+		if member.Ordinal < target {
+			members = append(members, member)
+		}
+	}
+	directory.Status.Replication.Members = members
+}
+
+// allocateServerID returns the next server ID of ids which is neither held by a member nor reserved, wrapping
+// around at the end of the range. Returns 0 if every server ID of the range is taken
+func allocateServerID(status *v1alpha1.ReplicationStatus, ids v1alpha1.ServerIDRange, reserved map[int32]bool) int32 {
+	inUse := map[int32]bool{}
+	for id := range reserved {
+		inUse[id] = true
+	}
+	for _, member := range status.Members {
+		inUse[member.ServerID] = true
+	}
+
+	id := status.LastServerID
+	for range ids.End - ids.Start + 1 {
+		id++
+		if !ids.Contains(id) {
+			id = ids.Start
+		}
+		if !inUse[id] {
+			status.LastServerID = id
+			return id
+		}
+	}
+	return 0
+}
+
+// reconcileReplicationHealth compares the context CSNs of each database across the instances of the directory,
+// recording the sync state of each instance in the status and the replication lag as metrics
+func (r *DirectoryReconciler) reconcileReplicationHealth(ctx context.Context, directory *v1alpha1.Directory) error {
+	labels := prometheus.Labels{"namespace": directory.Namespace, "directory": directory.Name}
+	replicationLagSeconds.DeletePartialMatch(labels)
+	replicationInSync.DeletePartialMatch(labels)
+
+	replicationPeerReachable.DeletePartialMatch(labels)
+
+	if !directory.Replicated() && len(directory.ReplicationPeers()) == 0 {
+		if directory.Status.Replication != nil {
+			directory.Status.Replication.Replicas = nil
+			directory.Status.Replication.Peers = nil
+			directory.Status.Replication.LastChecked = nil
+		}
+		meta.RemoveStatusCondition(&directory.Status.Conditions, v1alpha1.ReplicationHealthyCondition)
+		return nil
+	}
+
+	password, err := adminPassword(ctx, r, directory)
+	if err != nil {
+		return err
+	}
+
+	pods, err := r.directoryPods(ctx, directory)
+	if err != nil {
+		return err
+	}
+
+	replicas := make([]v1alpha1.ReplicaStatus, len(pods))
+	csns := map[string]map[string][]slapd.CSN{}
+	for i := range pods {
+		replicas[i] = v1alpha1.ReplicaStatus{
+			Pod:    pods[i].Name,
+			Role:   directory.PodRole(podOrdinal(&pods[i])),
+			InSync: true,
+		}
+		for j := range directory.Spec.SlapdConfig.Databases {
+			db := &directory.Spec.SlapdConfig.Databases[j]
+			values, err := podContextCSNs(&pods[i], db, password)
+			if err != nil {
+				replicas[i].InSync = false
+				replicas[i].Message = fmt.Sprintf("failed to read contextCSN of %s: %s", db.Name, err.Error())
+				break
+			}
+			if csns[db.Name] == nil {
+				csns[db.Name] = map[string][]slapd.CSN{}
+			}
+			csns[db.Name][pods[i].Name] = values
+		}
+	}
+
+	for _, db := range directory.Spec.SlapdConfig.Databases {
+		lags := slapd.CompareCSNs(csns[db.Name])
+		for i := range replicas {
+			lag, found := lags[replicas[i].Pod]
+			if !found {
+				continue
+			}
+
+			values := []string{}
+			for _, csn := range csns[db.Name][replicas[i].Pod] {
+				values = append(values, csn.String())
+			}
+			replicas[i].Databases = append(replicas[i].Databases, v1alpha1.DatabaseSyncStatus{
+				Name:       db.Name,
+				ContextCSN: values,
+				LagSeconds: int64(lag.Lag.Seconds()),
+			})
+			if int64(lag.Lag.Seconds()) > replicas[i].LagSeconds {
+				replicas[i].LagSeconds = int64(lag.Lag.Seconds())
+			}
+			if !lag.InSync {
+				replicas[i].InSync = false
+			}
+			replicationLagSeconds.WithLabelValues(directory.Namespace, directory.Name, replicas[i].Pod, db.Name).Set(lag.Lag.Seconds())
+		}
+	}
+
+	unhealthy := []string{}
+	for _, replica := range replicas {
+		inSync := 0.0
+		if replica.InSync {
+			inSync = 1
+		}
+		replicationInSync.WithLabelValues(directory.Namespace, directory.Name, replica.Pod).Set(inSync)
+
+		if replica.Message != "" || time.Duration(replica.LagSeconds)*time.Second > directory.MaxReplicationLag() {
+			unhealthy = append(unhealthy, replica.Pod)
+		}
+	}
+
+	peers := r.peerStatuses(ctx, directory)
+	for _, peer := range peers {
+		if !peer.Reachable {
+			unhealthy = append(unhealthy, fmt.Sprintf("peer %s", peer.Name))
+		}
+	}
+
+	directory.Status.Replication.Replicas = replicas
+	directory.Status.Replication.Peers = peers
+	directory.Status.Replication.LastChecked = &metav1.Time{Time: time.Now()}
+
+	if len(unhealthy) > 0 {
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:   v1alpha1.ReplicationHealthyCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "ReplicationLagging",
+			Message: fmt.Sprintf("replicas unreachable or behind by more than %s: %s",
+				directory.MaxReplicationLag(), strings.Join(unhealthy, ", ")),
+		})
+	} else {
+		meta.SetStatusCondition(&directory.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.ReplicationHealthyCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  "InSync",
+			Message: fmt.Sprintf("all replicas are within %s of the newest changes", directory.MaxReplicationLag()),
+		})
+	}
+
+	return nil
+}
+
+// podContextCSNs reads the context CSNs of a database from the instance running in pod
+func podContextCSNs(pod *corev1.Pod, db *v1alpha1.DatabaseSpec, password []byte) ([]slapd.CSN, error) {
+	conn, err := slapd.Connect(slapd.PodURI(pod.Status.PodIP), db.RootDN(), string(password))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return conn.ContextCSNs(db.Suffix)
+}
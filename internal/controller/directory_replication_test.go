@@ -0,0 +1,228 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	openldapv1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+var _ = Describe("Directory Replication Members", func() {
+	var reconciler *DirectoryReconciler
+	var directory *openldapv1alpha1.Directory
+
+	BeforeEach(func() {
+		reconciler = &DirectoryReconciler{}
+		directory = &openldapv1alpha1.Directory{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-directory",
+				Namespace: "default",
+			},
+			Spec: openldapv1alpha1.DirectorySpec{
+				Replicas: 3,
+			},
+		}
+		Expect(reconciler.reconcileMembers(directory)).To(Succeed())
+	})
+
+	It("allocates a server ID to each member", func() {
+		Expect(directory.Members()).To(Equal([]openldapv1alpha1.ReplicationMember{
+			{Ordinal: 0, ServerID: 1},
+			{Ordinal: 1, ServerID: 2},
+			{Ordinal: 2, ServerID: 3},
+		}))
+	})
+
+	It("doesn't reuse the server IDs of departed members", func() {
+		directory.Spec.Replicas = 2
+		releaseMembers(directory)
+		directory.Spec.Replicas = 3
+		Expect(reconciler.reconcileMembers(directory)).To(Succeed())
+		Expect(directory.ServerID(2)).To(Equal(int32(4)))
+	})
+
+	It("keeps departing members running until they are released", func() {
+		directory.Spec.Replicas = 2
+		Expect(reconciler.reconcileMembers(directory)).To(Succeed())
+		Expect(statefulSetReplicas(directory)).To(Equal(int32(3)))
+
+		releaseMembers(directory)
+		Expect(statefulSetReplicas(directory)).To(Equal(int32(2)))
+	})
+
+	It("allows scaling down to a single member", func() {
+		directory.Spec.Replicas = 1
+		Expect(reconciler.reconcileMembers(directory)).To(Succeed())
+
+		target, blocked := replicationTarget(directory)
+		Expect(blocked).To(BeFalse())
+		Expect(target).To(Equal(int32(1)))
+		Expect(meta.FindStatusCondition(directory.Status.Conditions, openldapv1alpha1.ScaleDownBlockedCondition)).To(BeNil())
+	})
+
+	It("blocks removing members which are out of sync", func() {
+		directory.Status.Replication.Replicas = []openldapv1alpha1.ReplicaStatus{
+			{Pod: "test-directory-slapd-0", InSync: true},
+			{Pod: "test-directory-slapd-1", InSync: true},
+			{Pod: "test-directory-slapd-2", InSync: false},
+		}
+		directory.Spec.Replicas = 1
+		Expect(reconciler.reconcileMembers(directory)).To(Succeed())
+
+		target, blocked := replicationTarget(directory)
+		Expect(blocked).To(BeTrue())
+		Expect(target).To(Equal(int32(3)))
+		condition := meta.FindStatusCondition(directory.Status.Conditions, openldapv1alpha1.ScaleDownBlockedCondition)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal("ReplicasOutOfSync"))
+		Expect(condition.Message).To(ContainSubstring("test-directory-slapd-2"))
+
+		By("allowing the scale-down once the member is in sync")
+		directory.Status.Replication.Replicas[2].InSync = true
+		Expect(reconciler.reconcileMembers(directory)).To(Succeed())
+		Expect(meta.FindStatusCondition(directory.Status.Conditions, openldapv1alpha1.ScaleDownBlockedCondition)).To(BeNil())
+	})
+
+	It("allows forced scale-downs", func() {
+		directory.Status.Replication.Replicas = []openldapv1alpha1.ReplicaStatus{
+			{Pod: "test-directory-slapd-2", InSync: false},
+		}
+		directory.Spec.Replicas = 1
+		directory.Annotations = map[string]string{openldapv1alpha1.ForceScaleDownAnnotation: "true"}
+		Expect(reconciler.reconcileMembers(directory)).To(Succeed())
+
+		target, blocked := replicationTarget(directory)
+		Expect(blocked).To(BeFalse())
+		Expect(target).To(Equal(int32(1)))
+		Expect(meta.FindStatusCondition(directory.Status.Conditions, openldapv1alpha1.ScaleDownBlockedCondition)).To(BeNil())
+	})
+
+	Context("with replication peers", func() {
+		BeforeEach(func() {
+			directory.Spec.SlapdConfig = &openldapv1alpha1.SlapdConfigSpec{
+				Databases: []openldapv1alpha1.DatabaseSpec{
+					{Name: "example", Suffix: "dc=example,dc=com"},
+				},
+			}
+			directory.Spec.Replication = &openldapv1alpha1.ReplicationSpec{
+				Peers: []openldapv1alpha1.ReplicationPeerSpec{
+					{Name: "eu-west", URI: "ldaps://ldap.eu-west.example.com", ServerID: 105},
+				},
+				ServerIDs: &openldapv1alpha1.ServerIDRange{Start: 1, End: 100},
+			}
+		})
+
+		It("allocates server IDs from the configured range", func() {
+			directory.Status.Replication = nil
+			directory.Spec.Replication.ServerIDs = &openldapv1alpha1.ServerIDRange{Start: 201, End: 203}
+			Expect(reconciler.reconcileMembers(directory)).To(Succeed())
+			Expect(directory.Members()).To(Equal([]openldapv1alpha1.ReplicationMember{
+				{Ordinal: 0, ServerID: 201},
+				{Ordinal: 1, ServerID: 202},
+				{Ordinal: 2, ServerID: 203},
+			}))
+			Expect(validatePeers(directory)).To(Succeed())
+
+			By("failing once the range is exhausted")
+			directory.Spec.Replicas = 4
+			Expect(reconciler.reconcileMembers(directory)).To(MatchError(ContainSubstring("no free server ID left in the range 201-203")))
+		})
+
+		It("rejects peers within the server ID range of the directory", func() {
+			directory.Spec.Replication.Peers[0].ServerID = 50
+			Expect(validatePeers(directory)).To(MatchError(ContainSubstring("lies within the server ID range 1-100")))
+			Expect(reconciler.reconcileMembers(directory)).NotTo(Succeed())
+
+			By("requiring a range to be configured alongside peers")
+			directory.Spec.Replication.ServerIDs = nil
+			directory.Spec.Replication.Peers[0].ServerID = 5
+			Expect(validatePeers(directory)).To(MatchError(ContainSubstring("lies within the server ID range 1-999")))
+		})
+
+		It("rejects peers replicating an unknown database", func() {
+			directory.Spec.Replication.Peers[0].Database = "other"
+			Expect(validatePeers(directory)).To(MatchError(ContainSubstring(`database "other" of peer eu-west not found`)))
+		})
+
+		It("rejects peers whose settings would add syncrepl keywords", func() {
+			directory.Spec.Replication.Peers[0].BindDN = `cn=replicator,dc=example,dc=com" bindmethod="sasl`
+			err := validatePeers(directory)
+			Expect(err).To(MatchError(errInvalidReplication))
+			Expect(err).To(MatchError(ContainSubstring("peer eu-west: bind DN contains quotes")))
+		})
+	})
+
+	Context("with an external provider", func() {
+		BeforeEach(func() {
+			directory.Spec.SlapdConfig = &openldapv1alpha1.SlapdConfigSpec{
+				Databases: []openldapv1alpha1.DatabaseSpec{
+					{Name: "example", Suffix: "dc=example,dc=com"},
+				},
+			}
+			directory.Spec.Replication = &openldapv1alpha1.ReplicationSpec{
+				External: &openldapv1alpha1.ExternalProviderSpec{
+					URI:    "ldaps://ldap.example.com",
+					BindDN: "cn=replicator,dc=example,dc=com",
+					PasswordSecretRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "external-provider"},
+						Key:                  "password",
+					},
+				},
+			}
+		})
+
+		It("rejects settings which would add syncrepl keywords", func() {
+			Expect(validateReplication(directory)).To(Succeed())
+
+			directory.Spec.Replication.External.SearchBase = "dc=example,dc=com\" retry=\"1 +"
+			Expect(validateReplication(directory)).To(MatchError(errInvalidReplication))
+
+			directory.Spec.Replication.External.SearchBase = ""
+			directory.Spec.Replication.External.URI = "ldaps://ldap.example.com starttls=no"
+			Expect(validateReplication(directory)).To(MatchError(ContainSubstring("invalid provider URI")))
+		})
+
+		It("drops the trailing line break of the password", func() {
+			ctx := context.Background()
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "external-provider", Namespace: "default"},
+				Data:       map[string][]byte{"password": []byte("s3cret\n")},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			}()
+
+			selector := &directory.Spec.Replication.External.PasswordSecretRef
+			Expect(syncreplPassword(ctx, k8sClient, "default", selector)).To(Equal("s3cret"))
+
+			secret.Data["password"] = []byte("s3\"cret")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			_, err := syncreplPassword(ctx, k8sClient, "default", selector)
+			Expect(err).To(MatchError(errInvalidReplication))
+			Expect(err.Error()).NotTo(ContainSubstring("s3"))
+		})
+	})
+})
@@ -0,0 +1,537 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/events"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+const (
+	// watchRestartDelay is how long a failed watch waits before it's restarted from its cookie
+	watchRestartDelay = 30 * time.Second
+	// watchProgressInterval is how often the cookie of a watch is saved to its status while changes are delivered
+	watchProgressInterval = 5 * time.Second
+)
+
+// DirectoryWatchReconciler reconciles a DirectoryWatch object. Each watch holds a syncrepl connection to its
+// directory for as long as it exists, delivering changes to its sink
+type DirectoryWatchReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	mutex   sync.Mutex
+	running map[types.NamespacedName]*runningWatch
+}
+
+// runningWatch is the syncrepl connection of a watch
+type runningWatch struct {
+	generation int64
+	// credentials the watch binds with, restarting the watch when its binding rotates its password
+	credentials watchCredentials
+	cancel      context.CancelFunc
+	done        chan struct{}
+	// err and stopped are set before done is closed
+	err     error
+	stopped time.Time
+}
+
+func (running *runningWatch) finished() bool {
+	select {
+	case <-running.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// watchCredentials is the service account of the binding a watch binds as
+type watchCredentials struct {
+	bindDN   string
+	password string
+}
+
+// watchProgress is what a watch has delivered since it was started
+type watchProgress struct {
+	cookie        string
+	delivered     int64
+	lastDelivered *metav1.Time
+	saved         time.Time
+}
+
+// changeData is the payload of the CloudEvent delivered for a change
+type changeData struct {
+	Directory         string              `json:"directory"`
+	Database          string              `json:"database"`
+	Change            slapd.ChangeType    `json:"change"`
+	DN                string              `json:"dn"`
+	EntryUUID         string              `json:"entryUUID"`
+	ChangedAttributes []string            `json:"changedAttributes,omitempty"`
+	Attributes        map[string][]string `json:"attributes,omitempty"`
+}
+
+// changeEventTypes maps changes to the type of their CloudEvent
+var changeEventTypes = map[slapd.ChangeType]string{
+	slapd.ChangeAdd:    "domain.my.openldap.entry.added",
+	slapd.ChangeModify: "domain.my.openldap.entry.modified",
+	slapd.ChangeDelete: "domain.my.openldap.entry.deleted",
+}
+
+// watchChangeTypes maps changes to the types selected in the spec of a watch
+var watchChangeTypes = map[slapd.ChangeType]v1alpha1.WatchChangeType{
+	slapd.ChangeAdd:    v1alpha1.WatchChangeAdd,
+	slapd.ChangeModify: v1alpha1.WatchChangeModify,
+	slapd.ChangeDelete: v1alpha1.WatchChangeDelete,
+}
+
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=directorywatches,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=directorywatches/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=directorywatches/finalizers,verbs=update
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=directories,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapbindings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile directorywatch resource
+func (r *DirectoryWatchReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	watch := &v1alpha1.DirectoryWatch{}
+	if err := r.Get(ctx, req.NamespacedName, watch); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("directorywatch not found, stopping its watch since it must have been deleted")
+			r.stopWatch(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "failed to retrieve directorywatch")
+		return ctrl.Result{}, err
+	}
+
+	// Set watch condition to Unknown if no condition is already defined
+	if len(watch.Status.Conditions) == 0 {
+		meta.SetStatusCondition(&watch.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.DirectoryWatchReadyCondition,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "Reconciling",
+			Message: "Starting reconciler for new watch",
+		})
+		if err := r.Status().Update(ctx, watch); err != nil {
+			logger.Error(err, "Failed to update directorywatch status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !watch.DeletionTimestamp.IsZero() {
+		r.stopWatch(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	directory := &v1alpha1.Directory{}
+	if err := r.Get(ctx, types.NamespacedName{Name: watch.Spec.DirectoryRef.Name, Namespace: watch.Namespace}, directory); err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "failed to retrieve directory")
+			return ctrl.Result{}, err
+		}
+		r.stopWatch(req.NamespacedName)
+		return r.setNotReady(ctx, watch, "DirectoryNotFound", fmt.Sprintf("directory %s not found", watch.Spec.DirectoryRef.Name))
+	}
+
+	binding := &v1alpha1.LdapBinding{}
+	if err := r.Get(ctx, types.NamespacedName{Name: watch.Spec.BindingRef.Name, Namespace: watch.Namespace}, binding); err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "failed to retrieve ldapbinding")
+			return ctrl.Result{}, err
+		}
+		r.stopWatch(req.NamespacedName)
+		return r.setNotReady(ctx, watch, "BindingNotFound", fmt.Sprintf("ldapbinding %s not found", watch.Spec.BindingRef.Name))
+	}
+	if binding.Spec.DirectoryRef.Name != directory.Name {
+		r.stopWatch(req.NamespacedName)
+		return r.setNotReady(ctx, watch, "BindingMismatch", fmt.Sprintf("ldapbinding %s belongs to directory %s",
+			binding.Name, binding.Spec.DirectoryRef.Name))
+	}
+
+	database := watch.Spec.Database
+	if database == "" {
+		database = binding.Spec.Database
+	}
+	db := directory.Database(database)
+	if db == nil {
+		r.stopWatch(req.NamespacedName)
+		return r.setNotReady(ctx, watch, "DatabaseNotFound", fmt.Sprintf("database %q not found in directory %s", database, directory.Name))
+	}
+	if bindingDB := directory.Database(binding.Spec.Database); bindingDB == nil || bindingDB.Name != db.Name {
+		r.stopWatch(req.NamespacedName)
+		return r.setNotReady(ctx, watch, "BindingMismatch", fmt.Sprintf("ldapbinding %s doesn't grant access to database %s",
+			binding.Name, db.Name))
+	}
+
+	if !meta.IsStatusConditionTrue(directory.Status.Conditions, v1alpha1.DirectoryAvailableCondition) {
+		r.stopWatch(req.NamespacedName)
+		return r.setNotReady(ctx, watch, "DirectoryNotAvailable", fmt.Sprintf("directory %s is not available", directory.Name))
+	}
+
+	if !meta.IsStatusConditionTrue(binding.Status.Conditions, v1alpha1.LdapBindingReadyCondition) {
+		r.stopWatch(req.NamespacedName)
+		return r.setNotReady(ctx, watch, "BindingNotReady", fmt.Sprintf("ldapbinding %s is not ready", binding.Name))
+	}
+
+	credentials, err := r.bindingCredentials(ctx, binding)
+	if err != nil {
+		logger.Error(err, "failed to read credentials of ldapbinding")
+		return ctrl.Result{}, err
+	}
+
+	r.mutex.Lock()
+	running := r.running[req.NamespacedName]
+	r.mutex.Unlock()
+
+	current := running != nil && running.generation == watch.Generation && running.credentials == credentials
+	if current && !running.finished() {
+		return r.setReady(ctx, watch)
+	}
+
+	// The watch stopped on its own, having failed to deliver a change or lost its connection
+	if current {
+		if wait := watchRestartDelay - time.Since(running.stopped); wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+	}
+
+	sink, err := r.watchSink(ctx, watch)
+	if err != nil {
+		logger.Error(err, "failed to create sink of directorywatch")
+		r.stopWatch(req.NamespacedName)
+		if _, err := r.setNotReady(ctx, watch, "InvalidSink", fmt.Sprintf("failed to create sink: %s", err.Error())); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, err
+	}
+
+	r.startWatch(watch, directory, db, credentials, sink)
+
+	return r.setReady(ctx, watch)
+}
+
+// setReady records that the watch is delivering changes, only updating the status if the condition changed
+// since the watch itself saves its progress to the status
+func (r *DirectoryWatchReconciler) setReady(ctx context.Context, watch *v1alpha1.DirectoryWatch) (ctrl.Result, error) {
+	changed := meta.SetStatusCondition(&watch.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.DirectoryWatchReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Watching",
+		Message: fmt.Sprintf("Delivering changes to %s", watch.Spec.Sink.URL),
+	})
+	if !changed {
+		return ctrl.Result{}, nil
+	}
+	if err := r.Status().Update(ctx, watch); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update directorywatch status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// setNotReady records why changes aren't being delivered. The watch is reconciled again when its directory
+// changes
+func (r *DirectoryWatchReconciler) setNotReady(ctx context.Context, watch *v1alpha1.DirectoryWatch, reason, message string) (ctrl.Result, error) {
+	meta.SetStatusCondition(&watch.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.DirectoryWatchReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	if err := r.Status().Update(ctx, watch); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update directorywatch status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// bindingCredentials reads the bind DN and password of the service account of binding from its secret
+func (r *DirectoryWatchReconciler) bindingCredentials(ctx context.Context, binding *v1alpha1.LdapBinding) (watchCredentials, error) {
+	secretName := binding.Status.SecretName
+	if secretName == "" {
+		secretName = binding.SecretName()
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: binding.Namespace}, secret); err != nil {
+		return watchCredentials{}, err
+	}
+	return watchCredentials{bindDN: string(secret.Data["bind-dn"]), password: string(secret.Data["password"])}, nil
+}
+
+// watchSink returns the sink of the watch, reading its CA certificate and headers from their secrets
+func (r *DirectoryWatchReconciler) watchSink(ctx context.Context, watch *v1alpha1.DirectoryWatch) (*events.Sink, error) {
+	var caCert []byte
+	if watch.Spec.Sink.CASecretRef != nil {
+		value, err := secretValue(ctx, r, watch.Namespace, watch.Spec.Sink.CASecretRef)
+		if err != nil {
+			return nil, err
+		}
+		caCert = value
+	}
+
+	headers := map[string]string{}
+	if watch.Spec.Sink.HeadersSecretRef != nil {
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: watch.Spec.Sink.HeadersSecretRef.Name, Namespace: watch.Namespace}, secret); err != nil {
+			return nil, err
+		}
+		for name, value := range secret.Data {
+			headers[name] = string(value)
+		}
+	}
+
+	attempts, backoff := watch.RetryPolicy()
+	return events.NewSink(watch.Spec.Sink.URL, caCert, headers, attempts, backoff)
+}
+
+// startWatch replaces the running watch with one following the current spec, resuming from the cookie in the
+// status of the watch
+func (r *DirectoryWatchReconciler) startWatch(watch *v1alpha1.DirectoryWatch, directory *v1alpha1.Directory,
+	db *v1alpha1.DatabaseSpec, credentials watchCredentials, sink *events.Sink) {
+	key := client.ObjectKeyFromObject(watch)
+	r.stopWatch(key)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	running := &runningWatch{generation: watch.Generation, credentials: credentials, cancel: cancel, done: make(chan struct{})}
+
+	r.mutex.Lock()
+	if r.running == nil {
+		r.running = map[types.NamespacedName]*runningWatch{}
+	}
+	r.running[key] = running
+	r.mutex.Unlock()
+
+	watch = watch.DeepCopy()
+	directory = directory.DeepCopy()
+	db = db.DeepCopy()
+	go func() {
+		err := r.runWatch(ctx, watch, directory, db, credentials, sink)
+		if ctx.Err() != nil {
+			err = nil
+		}
+
+		running.err = err
+		running.stopped = time.Now()
+		close(running.done)
+	}()
+}
+
+// stopWatch stops the running watch with the given key, if any, and waits for it to finish
+func (r *DirectoryWatchReconciler) stopWatch(key types.NamespacedName) {
+	r.mutex.Lock()
+	running := r.running[key]
+	delete(r.running, key)
+	r.mutex.Unlock()
+
+	if running != nil {
+		running.cancel()
+		<-running.done
+	}
+}
+
+// runWatch delivers the changes to the watched entries until ctx is cancelled or a change can't be delivered.
+// The cookie only advances past a change once it has been delivered, so undelivered changes are delivered again
+// when the watch is restarted. The watch binds as the service account of its binding, so the access controls
+// of the directory decide which entries and attributes it sees
+func (r *DirectoryWatchReconciler) runWatch(ctx context.Context, watch *v1alpha1.DirectoryWatch,
+	directory *v1alpha1.Directory, db *v1alpha1.DatabaseSpec, credentials watchCredentials, sink *events.Sink) error {
+	logger := log.FromContext(ctx).WithValues("directorywatch", client.ObjectKeyFromObject(watch))
+
+	progress := &watchProgress{
+		cookie:        watch.Status.Cookie,
+		delivered:     watch.Status.Delivered,
+		lastDelivered: watch.Status.LastDelivered,
+		saved:         time.Now(),
+	}
+
+	err := func() error {
+		conn, err := slapd.Connect(slapd.WriteServiceURI(directory), credentials.bindDN, credentials.password)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		request := slapd.SyncRequest{
+			Base:       watch.Base(db),
+			Filter:     watch.Filter(),
+			Attributes: watch.Spec.Attributes,
+			Cookie:     progress.cookie,
+		}
+		return conn.Sync(ctx, request, func(change *slapd.Change) error {
+			if change.Type != "" && watch.Delivers(watchChangeTypes[change.Type]) {
+				if err := sink.Send(ctx, changeEvent(watch, directory, db, change)); err != nil {
+					return err
+				}
+				progress.delivered++
+				progress.lastDelivered = &metav1.Time{Time: time.Now()}
+			}
+			if change.Cookie != "" {
+				progress.cookie = change.Cookie
+			}
+
+			if time.Since(progress.saved) >= watchProgressInterval {
+				if err := r.saveProgress(ctx, watch, progress, nil); err != nil {
+					logger.Error(err, "failed to save progress of directorywatch")
+				}
+			}
+			return nil
+		})
+	}()
+
+	if ctx.Err() != nil {
+		err = nil
+	}
+	if err != nil {
+		logger.Error(err, "directorywatch stopped")
+		r.Recorder.Eventf(watch, corev1.EventTypeWarning, "WatchFailed", "Watch of directory %s stopped: %s", directory.Name, err.Error())
+	}
+
+	// The watch may be stopping because ctx was cancelled, so its progress is saved without it
+	if saveErr := r.saveProgress(context.Background(), watch, progress, err); saveErr != nil && !apierrors.IsNotFound(saveErr) {
+		logger.Error(saveErr, "failed to save progress of directorywatch")
+	}
+	return err
+}
+
+// saveProgress saves the cookie and delivery counts of a watch to its status, marking the watch not ready if it
+// stopped with watchErr
+func (r *DirectoryWatchReconciler) saveProgress(ctx context.Context, watch *v1alpha1.DirectoryWatch,
+	progress *watchProgress, watchErr error) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &v1alpha1.DirectoryWatch{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(watch), latest); err != nil {
+			return err
+		}
+		latest.Status.Cookie = progress.cookie
+		latest.Status.Delivered = progress.delivered
+		latest.Status.LastDelivered = progress.lastDelivered
+		if watchErr != nil {
+			meta.SetStatusCondition(&latest.Status.Conditions, metav1.Condition{
+				Type:    v1alpha1.DirectoryWatchReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  "WatchFailed",
+				Message: fmt.Sprintf("watch stopped, restarting from the last delivered change: %s", watchErr.Error()),
+			})
+		}
+		return r.Status().Update(ctx, latest)
+	})
+	if err == nil {
+		progress.saved = time.Now()
+	}
+	return err
+}
+
+// changeEvent returns the CloudEvent delivered for a change to an entry of a watched directory
+func changeEvent(watch *v1alpha1.DirectoryWatch, directory *v1alpha1.Directory, db *v1alpha1.DatabaseSpec,
+	change *slapd.Change) events.Event {
+	csn := change.CSN
+	if csn == "" {
+		csn = slapd.CookieCSN(change.Cookie)
+	}
+
+	event := events.Event{
+		ID:      fmt.Sprintf("%s/%s", change.EntryUUID, csn),
+		Source:  fmt.Sprintf("/apis/%s/namespaces/%s/directories/%s", v1alpha1.GroupVersion.String(), directory.Namespace, directory.Name),
+		Type:    changeEventTypes[change.Type],
+		Subject: change.DN,
+		Time:    time.Now(),
+		Data: changeData{
+			Directory:         directory.Name,
+			Database:          db.Name,
+			Change:            change.Type,
+			DN:                change.DN,
+			EntryUUID:         change.EntryUUID,
+			ChangedAttributes: change.Changed,
+			Attributes:        slapd.PublicAttributes(change.Attributes),
+		},
+	}
+	if parsed, err := slapd.ParseCSN(csn); err == nil {
+		event.Time = parsed.Time
+	}
+	return event
+}
+
+// watchesForDirectory maps a directory to the watches referencing it
+func (r *DirectoryWatchReconciler) watchesForDirectory(ctx context.Context, obj client.Object) []reconcile.Request {
+	watches := &v1alpha1.DirectoryWatchList{}
+	if err := r.List(ctx, watches, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list directorywatches")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, watch := range watches.Items {
+		if watch.Spec.DirectoryRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&watch)})
+		}
+	}
+	return requests
+}
+
+// watchesForBinding maps a binding to the watches binding as it, so watches restart when its password rotates
+func (r *DirectoryWatchReconciler) watchesForBinding(ctx context.Context, obj client.Object) []reconcile.Request {
+	watches := &v1alpha1.DirectoryWatchList{}
+	if err := r.List(ctx, watches, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list directorywatches")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, watch := range watches.Items {
+		if watch.Spec.BindingRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&watch)})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DirectoryWatchReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.DirectoryWatch{}).
+		Named("directorywatch").
+		Watches(&v1alpha1.Directory{}, handler.EnqueueRequestsFromMapFunc(r.watchesForDirectory),
+			ctrlbuilder.WithPredicates(directoryDependentsPredicate)).
+		Watches(&v1alpha1.LdapBinding{}, handler.EnqueueRequestsFromMapFunc(r.watchesForBinding)).
+		Complete(r)
+}
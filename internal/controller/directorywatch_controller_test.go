@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	openldapv1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("DirectoryWatch Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		directorywatch := &openldapv1alpha1.DirectoryWatch{}
+
+		BeforeEach(func() {
+			By("creating the custom resource for the Kind DirectoryWatch")
+			err := k8sClient.Get(ctx, typeNamespacedName, directorywatch)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &openldapv1alpha1.DirectoryWatch{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: openldapv1alpha1.DirectoryWatchSpec{
+						DirectoryRef: corev1.LocalObjectReference{Name: "missing-directory"},
+						BindingRef:   corev1.LocalObjectReference{Name: "watch-binding"},
+						Sink:         openldapv1alpha1.WebhookSinkSpec{URL: "http://event-receiver.default.svc"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &openldapv1alpha1.DirectoryWatch{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance DirectoryWatch")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+
+		It("reports a missing directory", func() {
+			controllerReconciler := &DirectoryWatchReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, directorywatch)).To(Succeed())
+			condition := meta.FindStatusCondition(directorywatch.Status.Conditions, openldapv1alpha1.DirectoryWatchReadyCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("DirectoryNotFound"))
+		})
+	})
+
+	Context("When building change events", func() {
+		var directory *openldapv1alpha1.Directory
+		var db *openldapv1alpha1.DatabaseSpec
+		var watch *openldapv1alpha1.DirectoryWatch
+
+		BeforeEach(func() {
+			directory = &openldapv1alpha1.Directory{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo-directory", Namespace: "bar"},
+			}
+			db = &openldapv1alpha1.DatabaseSpec{Name: "example", Suffix: "dc=example,dc=com"}
+			watch = &openldapv1alpha1.DirectoryWatch{}
+		})
+
+		It("describes a modification", func() {
+			event := changeEvent(watch, directory, db, &slapd.Change{
+				Type:       slapd.ChangeModify,
+				DN:         "uid=jdoe,ou=people,dc=example,dc=com",
+				EntryUUID:  "5e5b3b7a-2f0e-4d5c-8d3f-1c2b3a4d5e6f",
+				CSN:        "20250101120000.000000Z#000000#001#000000",
+				Attributes: map[string][]string{"mail": {"jdoe@example.com"}},
+				Changed:    []string{"mail"},
+			})
+
+			Expect(event.ID).To(Equal("5e5b3b7a-2f0e-4d5c-8d3f-1c2b3a4d5e6f/20250101120000.000000Z#000000#001#000000"))
+			Expect(event.Source).To(Equal("/apis/openldap.my.domain/v1alpha1/namespaces/bar/directories/foo-directory"))
+			Expect(event.Type).To(Equal("domain.my.openldap.entry.modified"))
+			Expect(event.Subject).To(Equal("uid=jdoe,ou=people,dc=example,dc=com"))
+			Expect(event.Time).To(Equal(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)))
+			Expect(event.Data).To(Equal(changeData{
+				Directory:         "foo-directory",
+				Database:          "example",
+				Change:            slapd.ChangeModify,
+				DN:                "uid=jdoe,ou=people,dc=example,dc=com",
+				EntryUUID:         "5e5b3b7a-2f0e-4d5c-8d3f-1c2b3a4d5e6f",
+				ChangedAttributes: []string{"mail"},
+				Attributes:        map[string][]string{"mail": {"jdoe@example.com"}},
+			}))
+		})
+
+		It("leaves password attributes out of events", func() {
+			event := changeEvent(watch, directory, db, &slapd.Change{
+				Type:      slapd.ChangeModify,
+				DN:        "uid=jdoe,ou=people,dc=example,dc=com",
+				EntryUUID: "5e5b3b7a-2f0e-4d5c-8d3f-1c2b3a4d5e6f",
+				Attributes: map[string][]string{
+					"mail":                {"jdoe@example.com"},
+					"userPassword":        {"{SSHA}abcdef"},
+					"authPassword;binary": {"secret"},
+				},
+				Changed: []string{"userPassword"},
+			})
+
+			data := event.Data.(changeData)
+			Expect(data.Attributes).To(Equal(map[string][]string{"mail": {"jdoe@example.com"}}))
+			Expect(data.ChangedAttributes).To(Equal([]string{"userPassword"}))
+		})
+
+		It("identifies deletions by the CSN of their cookie", func() {
+			event := changeEvent(watch, directory, db, &slapd.Change{
+				Type:      slapd.ChangeDelete,
+				DN:        "uid=jdoe,ou=people,dc=example,dc=com",
+				EntryUUID: "5e5b3b7a-2f0e-4d5c-8d3f-1c2b3a4d5e6f",
+				Cookie:    "rid=000,csn=20250101120000.000000Z#000000#001#000000;20250102120000.000000Z#000000#002#000000",
+			})
+
+			Expect(event.ID).To(Equal("5e5b3b7a-2f0e-4d5c-8d3f-1c2b3a4d5e6f/20250102120000.000000Z#000000#002#000000"))
+			Expect(event.Type).To(Equal("domain.my.openldap.entry.deleted"))
+		})
+	})
+})
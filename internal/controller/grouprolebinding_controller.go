@@ -0,0 +1,390 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/builder"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+// GroupRoleBindingReconciler reconciles a GroupRoleBinding object
+type GroupRoleBindingReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Builder  *builder.Builder
+	// BindableRoles are the roles GroupRoleBindings may grant. Anyone able to create a GroupRoleBinding can grant
+	// these roles to any group of the directory, so the operator only binds roles an administrator has allowed
+	BindableRoles []v1alpha1.GroupRoleRef
+}
+
+// errRoleBindingConflict is returned when a RoleBinding with the name of a GroupRoleBinding isn't managed by it
+var errRoleBindingConflict = errors.New("rolebinding is not managed by the grouprolebinding")
+
+// ParseBindableRoles parses a comma-separated list of Kind/name roles, e.g. ClusterRole/view,Role/app-reader
+func ParseBindableRoles(value string) ([]v1alpha1.GroupRoleRef, error) {
+	roles := []v1alpha1.GroupRoleRef{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kind, name, found := strings.Cut(entry, "/")
+		if !found || name == "" || (kind != string(v1alpha1.RoleKindRole) && kind != string(v1alpha1.RoleKindClusterRole)) {
+			return nil, fmt.Errorf("invalid role %q, expected Role/<name> or ClusterRole/<name>", entry)
+		}
+		roles = append(roles, v1alpha1.GroupRoleRef{Kind: v1alpha1.RoleKind(kind), Name: name})
+	}
+	return roles, nil
+}
+
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=grouprolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=grouprolebindings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=grouprolebindings/finalizers,verbs=update
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=directories,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapusers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapgroups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;clusterroles,verbs=bind
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile grouprolebinding resource. The manager may bind any role, so the bindable roles are the only check on
+// which roles GroupRoleBindings can grant. The RoleBinding is owned by the grouprolebinding and garbage collected
+// with it, so no finalizer is needed. Membership is resolved again every resync interval to pick up changes made
+// to the directory outside the operator
+func (r *GroupRoleBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	binding := &v1alpha1.GroupRoleBinding{}
+	if err := r.Get(ctx, req.NamespacedName, binding); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("grouprolebinding not found, ignoring since it must have been deleted")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "failed to retrieve grouprolebinding")
+		return ctrl.Result{}, err
+	}
+
+	// Set binding condition to Unknown if no condition is already defined
+	if len(binding.Status.Conditions) == 0 {
+		meta.SetStatusCondition(&binding.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.GroupRoleBindingReadyCondition,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "Reconciling",
+			Message: "Starting reconciler for new group role binding",
+		})
+		if err := r.Status().Update(ctx, binding); err != nil {
+			logger.Error(err, "Failed to update grouprolebinding status")
+			return ctrl.Result{}, err
+		}
+		// Status updates don't trigger a reconcile
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if !binding.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	directory := &v1alpha1.Directory{}
+	if err := r.Get(ctx, types.NamespacedName{Name: binding.Spec.DirectoryRef.Name, Namespace: binding.Namespace}, directory); err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "failed to retrieve directory")
+			return ctrl.Result{}, err
+		}
+		return r.setNotReady(ctx, binding, "DirectoryNotFound", fmt.Sprintf("directory %s not found", binding.Spec.DirectoryRef.Name))
+	}
+
+	db := directory.Database(binding.Spec.Database)
+	if db == nil {
+		return r.setNotReady(ctx, binding, "DatabaseNotFound", fmt.Sprintf("database %q not found in directory %s",
+			binding.Spec.Database, directory.Name))
+	}
+
+	if err := validateGroupDN(binding.Spec.GroupDN, db); err != nil {
+		return r.setNotReady(ctx, binding, "InvalidGroupDN", err.Error())
+	}
+
+	// Members lose access as soon as the role is removed from the bindable roles
+	if !r.bindable(binding) {
+		if err := r.deleteRoleBinding(ctx, binding); err != nil {
+			logger.Error(err, "failed to remove rolebinding of grouprolebinding")
+			return ctrl.Result{}, err
+		}
+		binding.Status.Members = nil
+		binding.Status.RoleBindingName = ""
+		return r.setNotReady(ctx, binding, "RoleNotBindable", fmt.Sprintf("%s %s is not one of the roles the operator may bind",
+			binding.RoleKind(), binding.Spec.RoleRef.Name))
+	}
+
+	if !meta.IsStatusConditionTrue(directory.Status.Conditions, v1alpha1.DirectoryAvailableCondition) {
+		return r.setNotReady(ctx, binding, "DirectoryNotAvailable", fmt.Sprintf("directory %s is not available", directory.Name))
+	}
+
+	if err := r.reconcileRoleBinding(ctx, binding, directory, db); err != nil {
+		reason := "Reconciling"
+		message := fmt.Sprintf("failed to resolve members of group %s: %s", binding.Spec.GroupDN, err.Error())
+		switch {
+		case errors.Is(err, slapd.ErrGroupNotFound):
+			reason = "GroupNotFound"
+			message = fmt.Sprintf("group %s not found, no users are bound to the role", binding.Spec.GroupDN)
+		case errors.Is(err, errRoleBindingConflict):
+			reason = "Conflict"
+			message = fmt.Sprintf("rolebinding %s exists and is not managed by grouprolebinding %s", binding.Name, binding.Name)
+		}
+		meta.SetStatusCondition(&binding.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.GroupRoleBindingReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: message,
+		})
+		if err := r.Status().Update(ctx, binding); err != nil {
+			logger.Error(err, "Failed to update grouprolebinding status")
+			return ctrl.Result{}, err
+		}
+
+		// The group may be created later, outside the operator, and the conflicting RoleBinding may be removed
+		if reason == "GroupNotFound" || reason == "Conflict" {
+			return ctrl.Result{RequeueAfter: binding.ResyncInterval()}, nil
+		}
+		logger.Error(err, "failed to reconcile grouprolebinding")
+		return ctrl.Result{}, err
+	}
+
+	meta.SetStatusCondition(&binding.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.GroupRoleBindingReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Reconciling",
+		Message: fmt.Sprintf("Bound %d members of the group", len(binding.Status.Members)),
+	})
+	if err := r.Status().Update(ctx, binding); err != nil {
+		logger.Error(err, "Failed to update grouprolebinding status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: binding.ResyncInterval()}, nil
+}
+
+// setNotReady records why the group role binding can't be reconciled yet. The binding is reconciled again when
+// its directory changes
+func (r *GroupRoleBindingReconciler) setNotReady(ctx context.Context, binding *v1alpha1.GroupRoleBinding, reason, message string) (ctrl.Result, error) {
+	meta.SetStatusCondition(&binding.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.GroupRoleBindingReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	if err := r.Status().Update(ctx, binding); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update grouprolebinding status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// validateGroupDN checks the group DN is held by the database
+func validateGroupDN(dn string, db *v1alpha1.DatabaseSpec) error {
+	group, err := ldap.ParseDN(dn)
+	if err != nil {
+		return fmt.Errorf("invalid group DN %q: %w", dn, err)
+	}
+	suffix, err := ldap.ParseDN(db.Suffix)
+	if err != nil {
+		return err
+	}
+	if !suffix.AncestorOfFold(group) {
+		return fmt.Errorf("group %s is not below the suffix %s of database %s", dn, db.Suffix, db.Name)
+	}
+	return nil
+}
+
+// bindable returns whether the role of a group role binding is one of the roles the operator may bind
+func (r *GroupRoleBindingReconciler) bindable(binding *v1alpha1.GroupRoleBinding) bool {
+	return slices.Contains(r.BindableRoles, v1alpha1.GroupRoleRef{Kind: binding.RoleKind(), Name: binding.Spec.RoleRef.Name})
+}
+
+// deleteRoleBinding removes the RoleBinding of a group role binding, leaving RoleBindings it doesn't manage alone
+func (r *GroupRoleBindingReconciler) deleteRoleBinding(ctx context.Context, binding *v1alpha1.GroupRoleBinding) error {
+	existing := &rbacv1.RoleBinding{}
+	if err := r.Get(ctx, types.NamespacedName{Name: binding.Name, Namespace: binding.Namespace}, existing); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(existing, binding) {
+		return nil
+	}
+	return client.IgnoreNotFound(r.Delete(ctx, existing))
+}
+
+// reconcileRoleBinding resolves the members of the group and writes them to the RoleBinding. When the group no
+// longer exists the RoleBinding is emptied so its former members lose access. A RoleBinding of the same name which
+// isn't managed by the group role binding is left alone
+func (r *GroupRoleBindingReconciler) reconcileRoleBinding(
+	ctx context.Context, binding *v1alpha1.GroupRoleBinding, directory *v1alpha1.Directory, db *v1alpha1.DatabaseSpec,
+) error {
+	conn, err := connectDirectory(ctx, r, directory, db.RootDN())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	resolver := &slapd.MemberResolver{
+		Directory:         conn,
+		Base:              db.Suffix,
+		UsernameAttribute: binding.UsernameAttribute(),
+		MaxDepth:          binding.MaxNestingDepth(),
+	}
+	members, resolveErr := resolver.Members(binding.Spec.GroupDN)
+	if resolveErr != nil {
+		if !errors.Is(resolveErr, slapd.ErrGroupNotFound) {
+			return resolveErr
+		}
+		members = []string{}
+	}
+
+	desired, err := r.Builder.GroupRoleBinding(binding, members)
+	if err != nil {
+		return err
+	}
+
+	existing := &rbacv1.RoleBinding{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err := r.Create(ctx, desired); err != nil {
+			return err
+		}
+	} else if !metav1.IsControlledBy(existing, binding) {
+		return errRoleBindingConflict
+	} else if existing.RoleRef != desired.RoleRef {
+		// The role of a RoleBinding can't be changed, so it is replaced
+		if err := r.Delete(ctx, existing); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, desired); err != nil {
+			return err
+		}
+	} else {
+		patch := client.MergeFrom(existing.DeepCopy())
+		existing.Labels = desired.Labels
+		existing.Subjects = desired.Subjects
+		if err := r.Patch(ctx, existing, patch); err != nil {
+			return err
+		}
+	}
+
+	r.recordMembers(binding, members)
+	now := metav1.Now()
+	binding.Status.Members = members
+	binding.Status.RoleBindingName = desired.Name
+	binding.Status.LastSyncTime = &now
+	return resolveErr
+}
+
+// recordMembers emits an event listing the users added to and removed from the role
+func (r *GroupRoleBindingReconciler) recordMembers(binding *v1alpha1.GroupRoleBinding, members []string) {
+	added := []string{}
+	for _, member := range members {
+		if !slices.Contains(binding.Status.Members, member) {
+			added = append(added, member)
+		}
+	}
+	removed := []string{}
+	for _, member := range binding.Status.Members {
+		if !slices.Contains(members, member) {
+			removed = append(removed, member)
+		}
+	}
+	if len(added) > 0 {
+		r.Recorder.Eventf(binding, corev1.EventTypeNormal, "MembersAdded", "Bound %s to %s %s",
+			strings.Join(added, ", "), binding.RoleKind(), binding.Spec.RoleRef.Name)
+	}
+	if len(removed) > 0 {
+		r.Recorder.Eventf(binding, corev1.EventTypeNormal, "MembersRemoved", "Unbound %s from %s %s",
+			strings.Join(removed, ", "), binding.RoleKind(), binding.Spec.RoleRef.Name)
+	}
+}
+
+// bindingsForDirectory maps a directory to the group role bindings referencing it
+func (r *GroupRoleBindingReconciler) bindingsForDirectory(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.bindingsMatching(ctx, obj.GetNamespace(), obj.GetName())
+}
+
+// bindingsForMember maps a user or group managed by the operator to the group role bindings of its directory,
+// so membership changes take effect without waiting for the resync interval
+func (r *GroupRoleBindingReconciler) bindingsForMember(ctx context.Context, obj client.Object) []reconcile.Request {
+	switch member := obj.(type) {
+	case *v1alpha1.LdapUser:
+		return r.bindingsMatching(ctx, member.Namespace, member.Spec.DirectoryRef.Name)
+	case *v1alpha1.LdapGroup:
+		return r.bindingsMatching(ctx, member.Namespace, member.Spec.DirectoryRef.Name)
+	}
+	return nil
+}
+
+func (r *GroupRoleBindingReconciler) bindingsMatching(ctx context.Context, namespace, directory string) []reconcile.Request {
+	bindings := &v1alpha1.GroupRoleBindingList{}
+	if err := r.List(ctx, bindings, client.InNamespace(namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list grouprolebindings")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, binding := range bindings.Items {
+		if binding.Spec.DirectoryRef.Name == directory {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&binding)})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates, which record the sync time on every
+// pass, don't trigger another reconcile
+func (r *GroupRoleBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.GroupRoleBinding{}, ctrlbuilder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("grouprolebinding").
+		Owns(&rbacv1.RoleBinding{}).
+		Watches(&v1alpha1.Directory{}, handler.EnqueueRequestsFromMapFunc(r.bindingsForDirectory),
+			ctrlbuilder.WithPredicates(directoryDependentsPredicate)).
+		Watches(&v1alpha1.LdapUser{}, handler.EnqueueRequestsFromMapFunc(r.bindingsForMember)).
+		Watches(&v1alpha1.LdapGroup{}, handler.EnqueueRequestsFromMapFunc(r.bindingsForMember)).
+		Complete(r)
+}
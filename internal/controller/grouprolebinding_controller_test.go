@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	openldapv1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+var _ = Describe("GroupRoleBinding Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default", // TODO(user):Modify as needed
+		}
+		grouprolebinding := &openldapv1alpha1.GroupRoleBinding{}
+
+		BeforeEach(func() {
+			By("creating the custom resource for the Kind GroupRoleBinding")
+			err := k8sClient.Get(ctx, typeNamespacedName, grouprolebinding)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &openldapv1alpha1.GroupRoleBinding{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: openldapv1alpha1.GroupRoleBindingSpec{
+						DirectoryRef: corev1.LocalObjectReference{Name: "test-directory"},
+						GroupDN:      "cn=developers,ou=groups,dc=example,dc=com",
+						RoleRef:      openldapv1alpha1.GroupRoleRef{Name: "edit"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			// TODO(user): Cleanup logic after each test, like removing the resource instance.
+			resource := &openldapv1alpha1.GroupRoleBinding{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance GroupRoleBinding")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+		It("should successfully reconcile the resource", func() {
+			By("Reconciling the created resource")
+			controllerReconciler := &GroupRoleBindingReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
+			// Example: If you expect a certain status condition after reconciliation, verify it here.
+		})
+	})
+
+	Context("When checking the bound role", func() {
+		ctx := context.Background()
+
+		var reconciler *GroupRoleBindingReconciler
+		var binding *openldapv1alpha1.GroupRoleBinding
+
+		BeforeEach(func() {
+			roles, err := ParseBindableRoles("ClusterRole/view, Role/app-reader")
+			Expect(err).NotTo(HaveOccurred())
+			reconciler = &GroupRoleBindingReconciler{
+				Client:        k8sClient,
+				Scheme:        k8sClient.Scheme(),
+				BindableRoles: roles,
+			}
+			binding = &openldapv1alpha1.GroupRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "developers", Namespace: "default"},
+				Spec: openldapv1alpha1.GroupRoleBindingSpec{
+					DirectoryRef: corev1.LocalObjectReference{Name: "test-directory"},
+					GroupDN:      "cn=developers,ou=groups,dc=example,dc=com",
+					RoleRef:      openldapv1alpha1.GroupRoleRef{Name: "view"},
+				},
+			}
+		})
+
+		It("only binds the configured roles", func() {
+			Expect(reconciler.bindable(binding)).To(BeTrue())
+			binding.Spec.RoleRef = openldapv1alpha1.GroupRoleRef{Kind: openldapv1alpha1.RoleKindRole, Name: "app-reader"}
+			Expect(reconciler.bindable(binding)).To(BeTrue())
+			binding.Spec.RoleRef = openldapv1alpha1.GroupRoleRef{Name: "cluster-admin"}
+			Expect(reconciler.bindable(binding)).To(BeFalse())
+			binding.Spec.RoleRef = openldapv1alpha1.GroupRoleRef{Kind: openldapv1alpha1.RoleKindRole, Name: "view"}
+			Expect(reconciler.bindable(binding)).To(BeFalse())
+		})
+
+		It("rejects malformed roles", func() {
+			_, err := ParseBindableRoles("view")
+			Expect(err).To(MatchError(ContainSubstring(`invalid role "view"`)))
+			_, err = ParseBindableRoles("Group/view")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("leaves RoleBindings it doesn't manage alone", func() {
+			roleBinding := &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "developers", Namespace: "default"},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "admin"},
+			}
+			Expect(k8sClient.Create(ctx, roleBinding)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, roleBinding)).To(Succeed())
+			}()
+
+			Expect(reconciler.deleteRoleBinding(ctx, binding)).To(Succeed())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(roleBinding), &rbacv1.RoleBinding{})).To(Succeed())
+		})
+	})
+})
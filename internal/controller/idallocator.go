@@ -0,0 +1,240 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/builder"
+)
+
+// idKind selects the range of a directory POSIX ids are allocated from
+type idKind string
+
+const (
+	uidKind idKind = "uid"
+	gidKind idKind = "gid"
+)
+
+// idAllocator hands out POSIX ids from the ranges of a directory. The next free id of each range is recorded in a
+// ConfigMap owned by the directory and only ever moves forward, so released ids are never handed out again.
+// Concurrent allocations conflict on the resourceVersion of the ConfigMap and are retried
+type idAllocator struct {
+	client.Client
+	Builder *builder.Builder
+}
+
+// Allocate returns the next free id of a range
+func (allocator *idAllocator) Allocate(ctx context.Context, directory *v1alpha1.Directory, kind idKind) (int64, error) {
+	var allocated int64
+	err := allocator.update(ctx, directory, kind, func(next int64, idRange v1alpha1.IDRange) (int64, error) {
+		if next > idRange.End {
+			return 0, fmt.Errorf("%s range %d-%d of directory %s is exhausted", kind, idRange.Start, idRange.End, directory.Name)
+		}
+		allocated = next
+		return next + 1, nil
+	})
+	return allocated, err
+}
+
+// Reserve moves the next free id of a range past an id set explicitly on a user or group, so the id isn't
+// allocated to another one later. Ids outside the range are ignored. Ids below the next free id may have been
+// handed out already, so Resolve checks explicit ids against the recorded ones first
+func (allocator *idAllocator) Reserve(ctx context.Context, directory *v1alpha1.Directory, kind idKind, id int64) error {
+	return allocator.update(ctx, directory, kind, func(next int64, idRange v1alpha1.IDRange) (int64, error) {
+		if idRange.Contains(id) && id >= next {
+			return id + 1, nil
+		}
+		return next, nil
+	})
+}
+
+// Resolve returns the id to record for a user or group. An id set explicitly is reserved the first time it is seen,
+// and rejected if it is already the uid of another user or the gid of another group. Otherwise the recorded id is
+// kept and an id is only allocated if none has been recorded before
+func (allocator *idAllocator) Resolve(
+	ctx context.Context, directory *v1alpha1.Directory, kind idKind, obj client.Object, explicit, recorded *int64,
+) (*int64, error) {
+	if explicit != nil {
+		if !ptr.Equal(explicit, recorded) {
+			claims, err := allocator.recordedIDs(ctx, directory, kind)
+			if err != nil {
+				return nil, err
+			}
+			owner, identifies := idOwner(obj, kind)
+			if claim, found := claims[*explicit]; found && identifies && claim.identifies && claim.owner != owner {
+				return nil, fmt.Errorf("%sNumber %d is already used by %s", kind, *explicit, claim.owner)
+			}
+			if err := allocator.Reserve(ctx, directory, kind, *explicit); err != nil {
+				return nil, err
+			}
+		}
+		return ptr.To(*explicit), nil
+	}
+	if recorded != nil {
+		return recorded, nil
+	}
+
+	id, err := allocator.Allocate(ctx, directory, kind)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// update applies advance to the next free id of a range, creating the ConfigMap if it doesn't exist
+func (allocator *idAllocator) update(
+	ctx context.Context, directory *v1alpha1.Directory, kind idKind, advance func(int64, v1alpha1.IDRange) (int64, error),
+) error {
+	idRange := directory.IDRanges().UIDs
+	if kind == gidKind {
+		idRange = directory.IDRanges().GIDs
+	}
+	key := fmt.Sprintf("next-%s", kind)
+
+	retriable := func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}
+	return retry.OnError(retry.DefaultBackoff, retriable, func() error {
+		configMap := &corev1.ConfigMap{}
+		err := allocator.Get(ctx, types.NamespacedName{Name: directory.IDAllocationConfigMapName(), Namespace: directory.Namespace}, configMap)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		create := err != nil
+		if create {
+			configMap, err = allocator.Builder.IDAllocationConfigMap(directory)
+			if err != nil {
+				return err
+			}
+		}
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+
+		// The range may have been moved above ids already handed out. A range without a next free id yet starts
+		// past the ids already recorded, in case the ConfigMap was deleted
+		next := idRange.Start
+		if value, found := configMap.Data[key]; found {
+			recorded, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s in ConfigMap %s: %w", key, configMap.Name, err)
+			}
+			next = max(next, recorded)
+		} else {
+			claims, err := allocator.recordedIDs(ctx, directory, kind)
+			if err != nil {
+				return err
+			}
+			for id := range claims {
+				if idRange.Contains(id) {
+					next = max(next, id+1)
+				}
+			}
+		}
+
+		advanced, err := advance(next, idRange)
+		if err != nil {
+			return err
+		}
+		if !create && configMap.Data[key] == strconv.FormatInt(advanced, 10) {
+			return nil
+		}
+		configMap.Data[key] = strconv.FormatInt(advanced, 10)
+
+		if create {
+			return allocator.Create(ctx, configMap)
+		}
+		return allocator.Update(ctx, configMap)
+	})
+}
+
+// idClaim is an id recorded in the status of a user or group
+type idClaim struct {
+	// owner names the user or group, e.g. "ldapuser jdoe"
+	owner string
+	// identifies is true for the uid of a user and the gid of a group, which no other user or group may share.
+	// Users may share the gid of their primary group
+	identifies bool
+}
+
+// recordedIDs returns the ids of a kind recorded by the users and groups of a directory. Where an id is recorded
+// more than once, an identifying claim is returned
+func (allocator *idAllocator) recordedIDs(ctx context.Context, directory *v1alpha1.Directory, kind idKind) (map[int64]idClaim, error) {
+	claims := map[int64]idClaim{}
+	record := func(obj client.Object, id *int64) {
+		if id == nil {
+			return
+		}
+		owner, identifies := idOwner(obj, kind)
+		if claim, found := claims[*id]; !found || (identifies && !claim.identifies) {
+			claims[*id] = idClaim{owner: owner, identifies: identifies}
+		}
+	}
+
+	users := &v1alpha1.LdapUserList{}
+	if err := allocator.List(ctx, users, client.InNamespace(directory.Namespace)); err != nil {
+		return nil, err
+	}
+	for i := range users.Items {
+		user := &users.Items[i]
+		if user.Spec.DirectoryRef.Name != directory.Name {
+			continue
+		}
+		if kind == uidKind {
+			record(user, user.Status.UIDNumber)
+		} else {
+			record(user, user.Status.GIDNumber)
+		}
+	}
+	if kind == uidKind {
+		return claims, nil
+	}
+
+	groups := &v1alpha1.LdapGroupList{}
+	if err := allocator.List(ctx, groups, client.InNamespace(directory.Namespace)); err != nil {
+		return nil, err
+	}
+	for i := range groups.Items {
+		group := &groups.Items[i]
+		if group.Spec.DirectoryRef.Name == directory.Name {
+			record(group, group.Status.GIDNumber)
+		}
+	}
+	return claims, nil
+}
+
+// idOwner names a user or group for an idClaim, and returns whether an id of the kind identifies it
+func idOwner(obj client.Object, kind idKind) (string, bool) {
+	switch obj.(type) {
+	case *v1alpha1.LdapUser:
+		return fmt.Sprintf("ldapuser %s", obj.GetName()), kind == uidKind
+	case *v1alpha1.LdapGroup:
+		return fmt.Sprintf("ldapgroup %s", obj.GetName()), kind == gidKind
+	}
+	return obj.GetName(), false
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	openldapv1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/builder"
+)
+
+var _ = Describe("ID allocator", func() {
+	ctx := context.Background()
+	var directory *openldapv1alpha1.Directory
+	var allocator *idAllocator
+
+	BeforeEach(func() {
+		directory = &openldapv1alpha1.Directory{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "allocator-directory",
+				Namespace: "default",
+			},
+			Spec: openldapv1alpha1.DirectorySpec{
+				IDAllocation: &openldapv1alpha1.IDAllocationSpec{
+					UIDs: openldapv1alpha1.IDRange{Start: 20000, End: 20009},
+					GIDs: openldapv1alpha1.IDRange{Start: 30000, End: 30001},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, directory)).To(Succeed())
+		allocator = &idAllocator{Client: k8sClient, Builder: builder.NewBuilder(k8sClient.Scheme())}
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(ctx, directory)).To(Succeed())
+		configMap := &corev1.ConfigMap{}
+		key := types.NamespacedName{Name: directory.IDAllocationConfigMapName(), Namespace: directory.Namespace}
+		if err := k8sClient.Get(ctx, key, configMap); err == nil {
+			Expect(k8sClient.Delete(ctx, configMap)).To(Succeed())
+		}
+	})
+
+	It("allocates ids from the start of each range", func() {
+		Expect(allocator.Allocate(ctx, directory, uidKind)).To(Equal(int64(20000)))
+		Expect(allocator.Allocate(ctx, directory, uidKind)).To(Equal(int64(20001)))
+		Expect(allocator.Allocate(ctx, directory, gidKind)).To(Equal(int64(30000)))
+	})
+
+	It("doesn't hand out the same id to concurrent allocations", func() {
+		var mutex sync.Mutex
+		var wg sync.WaitGroup
+		allocated := map[int64]bool{}
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				id, err := allocator.Allocate(ctx, directory, uidKind)
+				Expect(err).NotTo(HaveOccurred())
+				mutex.Lock()
+				defer mutex.Unlock()
+				Expect(allocated).NotTo(HaveKey(id))
+				allocated[id] = true
+			}()
+		}
+		wg.Wait()
+		Expect(allocated).To(HaveLen(5))
+	})
+
+	It("skips ids set explicitly", func() {
+		Expect(allocator.Reserve(ctx, directory, uidKind, 20004)).To(Succeed())
+		Expect(allocator.Reserve(ctx, directory, uidKind, 1000)).To(Succeed())
+		Expect(allocator.Allocate(ctx, directory, uidKind)).To(Equal(int64(20005)))
+	})
+
+	It("keeps recorded ids", func() {
+		Expect(allocator.Resolve(ctx, directory, uidKind, &openldapv1alpha1.LdapUser{}, nil, ptr.To(int64(20007)))).To(Equal(ptr.To(int64(20007))))
+		Expect(allocator.Allocate(ctx, directory, uidKind)).To(Equal(int64(20000)))
+	})
+
+	It("fails once a range is exhausted", func() {
+		Expect(allocator.Allocate(ctx, directory, gidKind)).To(Equal(int64(30000)))
+		Expect(allocator.Allocate(ctx, directory, gidKind)).To(Equal(int64(30001)))
+		_, err := allocator.Allocate(ctx, directory, gidKind)
+		Expect(err).To(MatchError(ContainSubstring("exhausted")))
+	})
+
+	Context("with ids recorded by users and groups", func() {
+		var user *openldapv1alpha1.LdapUser
+		var group *openldapv1alpha1.LdapGroup
+
+		BeforeEach(func() {
+			directoryRef := corev1.LocalObjectReference{Name: directory.Name}
+			user = &openldapv1alpha1.LdapUser{
+				ObjectMeta: metav1.ObjectMeta{Name: "allocator-user", Namespace: "default"},
+				Spec:       openldapv1alpha1.LdapUserSpec{DirectoryRef: directoryRef, CN: "Jane Doe", SN: "Doe"},
+			}
+			Expect(k8sClient.Create(ctx, user)).To(Succeed())
+			user.Status.UIDNumber = ptr.To(int64(20003))
+			user.Status.GIDNumber = ptr.To(int64(30000))
+			Expect(k8sClient.Status().Update(ctx, user)).To(Succeed())
+
+			group = &openldapv1alpha1.LdapGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "allocator-group", Namespace: "default"},
+				Spec:       openldapv1alpha1.LdapGroupSpec{DirectoryRef: directoryRef},
+			}
+			Expect(k8sClient.Create(ctx, group)).To(Succeed())
+			group.Status.GIDNumber = ptr.To(int64(30000))
+			Expect(k8sClient.Status().Update(ctx, group)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, user)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, group)).To(Succeed())
+		})
+
+		It("starts a new ConfigMap past the recorded ids", func() {
+			Expect(allocator.Allocate(ctx, directory, uidKind)).To(Equal(int64(20004)))
+			Expect(allocator.Allocate(ctx, directory, gidKind)).To(Equal(int64(30001)))
+		})
+
+		It("rejects explicit ids used by another user or group", func() {
+			other := &openldapv1alpha1.LdapUser{ObjectMeta: metav1.ObjectMeta{Name: "other-user", Namespace: "default"}}
+			_, err := allocator.Resolve(ctx, directory, uidKind, other, ptr.To(int64(20003)), nil)
+			Expect(err).To(MatchError(ContainSubstring("uidNumber 20003 is already used by ldapuser allocator-user")))
+
+			otherGroup := &openldapv1alpha1.LdapGroup{ObjectMeta: metav1.ObjectMeta{Name: "other-group", Namespace: "default"}}
+			_, err = allocator.Resolve(ctx, directory, gidKind, otherGroup, ptr.To(int64(30000)), nil)
+			Expect(err).To(MatchError(ContainSubstring("gidNumber 30000 is already used by ldapgroup allocator-group")))
+
+			By("letting users share the gid of their primary group")
+			Expect(allocator.Resolve(ctx, directory, gidKind, other, ptr.To(int64(30000)), nil)).To(Equal(ptr.To(int64(30000))))
+
+			By("accepting the id recorded by the same user")
+			Expect(allocator.Resolve(ctx, directory, uidKind, user, ptr.To(int64(20003)), nil)).To(Equal(ptr.To(int64(20003))))
+		})
+	})
+})
@@ -0,0 +1,338 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/builder"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+	"github.com/paddyoneill/openldap-operator/internal/utils"
+)
+
+// LdapBindingReconciler reconciles a LdapBinding object
+type LdapBindingReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Builder  *builder.Builder
+}
+
+const (
+	ldapBindingFinalizer = "openldap.my.domain/ldapBindingFinalizer"
+)
+
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapbindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapbindings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapbindings/finalizers,verbs=update
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=directories,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get
+
+// Reconcile ldapbinding resource
+func (r *LdapBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	binding := &v1alpha1.LdapBinding{}
+	if err := r.Get(ctx, req.NamespacedName, binding); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("ldapbinding not found, ignoring since it must have been deleted")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "failed to retrieve ldapbinding")
+		return ctrl.Result{}, err
+	}
+
+	// Set binding condition to Unknown if no condition is already defined
+	if len(binding.Status.Conditions) == 0 {
+		meta.SetStatusCondition(&binding.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.LdapBindingReadyCondition,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "Reconciling",
+			Message: "Starting reconciler for new binding",
+		})
+		if err := r.Status().Update(ctx, binding); err != nil {
+			logger.Error(err, "Failed to update ldapbinding status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	directory := &v1alpha1.Directory{}
+	directoryErr := r.Get(ctx, types.NamespacedName{Name: binding.Spec.DirectoryRef.Name, Namespace: binding.Namespace}, directory)
+	if directoryErr != nil && !apierrors.IsNotFound(directoryErr) {
+		logger.Error(directoryErr, "failed to retrieve directory")
+		return ctrl.Result{}, directoryErr
+	}
+
+	// Binding marked for deletion
+	if !binding.ObjectMeta.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(binding, ldapBindingFinalizer) {
+			if directoryErr == nil && directory.ObjectMeta.DeletionTimestamp.IsZero() {
+				logger.Info("removing service account of ldapbinding")
+				if err := r.deleteServiceAccount(ctx, binding, directory); err != nil {
+					logger.Error(err, "failed to remove service account of ldapbinding")
+					return ctrl.Result{}, err
+				}
+			}
+			controllerutil.RemoveFinalizer(binding, ldapBindingFinalizer)
+			if err := r.Update(ctx, binding); err != nil {
+				logger.Error(err, "failed to remove finalizer from ldapbinding")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Add finalizer if needed
+	if !controllerutil.ContainsFinalizer(binding, ldapBindingFinalizer) {
+		controllerutil.AddFinalizer(binding, ldapBindingFinalizer)
+		if err := r.Update(ctx, binding); err != nil {
+			logger.Error(err, "failed to update ldapbinding with finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if directoryErr != nil {
+		return r.setNotReady(ctx, binding, "DirectoryNotFound", fmt.Sprintf("directory %s not found", binding.Spec.DirectoryRef.Name))
+	}
+
+	db := directory.Database(binding.Spec.Database)
+	if db == nil {
+		return r.setNotReady(ctx, binding, "DatabaseNotFound", fmt.Sprintf("database %q not found in directory %s", binding.Spec.Database, directory.Name))
+	}
+
+	if err := slapd.ValidateBindingScope(binding, db); err != nil {
+		return r.setNotReady(ctx, binding, "InvalidScope", err.Error())
+	}
+
+	if !meta.IsStatusConditionTrue(directory.Status.Conditions, v1alpha1.DirectoryAvailableCondition) {
+		return r.setNotReady(ctx, binding, "DirectoryNotAvailable", fmt.Sprintf("directory %s is not available", directory.Name))
+	}
+
+	if err := r.reconcileServiceAccount(ctx, binding, directory, db); err != nil {
+		logger.Error(err, "failed to reconcile ldapbinding service account")
+		meta.SetStatusCondition(&binding.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.LdapBindingReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Reconciling",
+			Message: fmt.Sprintf("failed to reconcile service account for binding %s: %s", binding.Name, err.Error()),
+		})
+
+		if err := r.Status().Update(ctx, binding); err != nil {
+			logger.Error(err, "Failed to update ldapbinding status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	meta.SetStatusCondition(&binding.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.LdapBindingReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Reconciling",
+		Message: "Successfully reconciled service account",
+	})
+	if err := r.Status().Update(ctx, binding); err != nil {
+		logger.Error(err, "Failed to update ldapbinding status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// setNotReady records why the binding can't be reconciled yet. The binding is reconciled again when its
+// directory changes
+func (r *LdapBindingReconciler) setNotReady(ctx context.Context, binding *v1alpha1.LdapBinding, reason, message string) (ctrl.Result, error) {
+	meta.SetStatusCondition(&binding.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.LdapBindingReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	if err := r.Status().Update(ctx, binding); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update ldapbinding status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileServiceAccount creates or updates the service account entry and the connection secret,
+// generating a new password when none exists or a rotation has been requested
+func (r *LdapBindingReconciler) reconcileServiceAccount(
+	ctx context.Context, binding *v1alpha1.LdapBinding, directory *v1alpha1.Directory, db *v1alpha1.DatabaseSpec,
+) error {
+	existing := &corev1.Secret{}
+	password := []byte{}
+	if err := r.Get(ctx, types.NamespacedName{Name: binding.SecretName(), Namespace: binding.Namespace}, existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+	} else {
+		password = existing.Data["password"]
+	}
+
+	rotation := binding.Annotations[v1alpha1.RotatePasswordAnnotation]
+	rotate := rotation != "" && rotation != binding.Status.PasswordRotation
+	if len(password) == 0 || rotate {
+		generated, err := utils.GenerateRandonPassword(24)
+		if err != nil {
+			return err
+		}
+		password = generated
+	}
+
+	conn, err := connectDirectory(ctx, r, directory, db.RootDN())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	suffix, err := slapd.SuffixEntry(db.Suffix)
+	if err != nil {
+		return err
+	}
+	for _, parent := range []*slapd.Entry{suffix, slapd.ServicesEntry(db)} {
+		if _, err := conn.Add(parent); err != nil {
+			return err
+		}
+	}
+
+	hash, err := conn.PasswordHash(binding.BindDN(db), "userPassword", password)
+	if err != nil {
+		return err
+	}
+	if err := conn.Ensure(slapd.BindingEntry(binding, db, hash)); err != nil {
+		return err
+	}
+
+	uri := slapd.ReadServiceURI(directory)
+	if binding.Spec.Access == v1alpha1.BindingAccessWrite {
+		uri = slapd.WriteServiceURI(directory)
+	}
+
+	data := map[string][]byte{
+		"uri":      []byte(uri),
+		"base-dn":  []byte(binding.Scope(db)),
+		"bind-dn":  []byte(binding.BindDN(db)),
+		"password": password,
+	}
+	if directory.Spec.TLS != nil {
+		tls := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: directory.Spec.TLS.SecretName, Namespace: directory.Namespace}, tls); err != nil {
+			return err
+		}
+		if ca, found := tls.Data["ca.crt"]; found {
+			data["ca.crt"] = ca
+		}
+	}
+
+	if err := r.reconcileSecret(ctx, binding, data); err != nil {
+		return err
+	}
+
+	binding.Status.BindDN = binding.BindDN(db)
+	binding.Status.SecretName = binding.SecretName()
+	if rotate {
+		binding.Status.PasswordRotation = rotation
+		r.Recorder.Event(binding, corev1.EventTypeNormal, "PasswordRotated", "Rotated service account password")
+	}
+
+	return nil
+}
+
+func (r *LdapBindingReconciler) reconcileSecret(ctx context.Context, binding *v1alpha1.LdapBinding, data map[string][]byte) error {
+	desired, err := r.Builder.BindingSecret(binding, data)
+	if err != nil {
+		return err
+	}
+
+	existing := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return r.Create(ctx, desired)
+	}
+
+	patch := client.MergeFrom(existing.DeepCopy())
+	existing.Labels = desired.Labels
+	existing.Data = desired.Data
+
+	return r.Patch(ctx, existing, patch)
+}
+
+func (r *LdapBindingReconciler) deleteServiceAccount(ctx context.Context, binding *v1alpha1.LdapBinding, directory *v1alpha1.Directory) error {
+	db := directory.Database(binding.Spec.Database)
+	if db == nil {
+		return nil
+	}
+
+	conn, err := connectDirectory(ctx, r, directory, db.RootDN())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Delete(binding.BindDN(db))
+}
+
+// bindingsForDirectory maps a directory to the bindings referencing it
+func (r *LdapBindingReconciler) bindingsForDirectory(ctx context.Context, obj client.Object) []reconcile.Request {
+	bindings := &v1alpha1.LdapBindingList{}
+	if err := r.List(ctx, bindings, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list ldapbindings")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, binding := range bindings.Items {
+		if binding.Spec.DirectoryRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&binding)})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LdapBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.LdapBinding{}).
+		Named("ldapbinding").
+		Owns(&corev1.Secret{}).
+		Watches(&v1alpha1.Directory{}, handler.EnqueueRequestsFromMapFunc(r.bindingsForDirectory),
+			ctrlbuilder.WithPredicates(directoryDependentsPredicate)).
+		Complete(r)
+}
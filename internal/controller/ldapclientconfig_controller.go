@@ -0,0 +1,410 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/builder"
+	"github.com/paddyoneill/openldap-operator/internal/clientconfig"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+// LdapClientConfigReconciler reconciles a LdapClientConfig object
+type LdapClientConfigReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Builder  *builder.Builder
+}
+
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapclientconfigs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapclientconfigs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapclientconfigs/finalizers,verbs=update
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=directories,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapbindings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile ldapclientconfig resource. The generated ConfigMap and Secret are owned by the ldapclientconfig and
+// garbage collected with it, so no finalizer is needed
+func (r *LdapClientConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	config := &v1alpha1.LdapClientConfig{}
+	if err := r.Get(ctx, req.NamespacedName, config); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("ldapclientconfig not found, ignoring since it must have been deleted")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "failed to retrieve ldapclientconfig")
+		return ctrl.Result{}, err
+	}
+
+	// Set client config condition to Unknown if no condition is already defined
+	if len(config.Status.Conditions) == 0 {
+		meta.SetStatusCondition(&config.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.LdapClientConfigReadyCondition,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "Reconciling",
+			Message: "Starting reconciler for new client config",
+		})
+		if err := r.Status().Update(ctx, config); err != nil {
+			logger.Error(err, "Failed to update ldapclientconfig status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !config.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	directory := &v1alpha1.Directory{}
+	if err := r.Get(ctx, types.NamespacedName{Name: config.Spec.DirectoryRef.Name, Namespace: config.Namespace}, directory); err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "failed to retrieve directory")
+			return ctrl.Result{}, err
+		}
+		return r.setNotReady(ctx, config, "DirectoryNotFound", fmt.Sprintf("directory %s not found", config.Spec.DirectoryRef.Name))
+	}
+
+	var binding *v1alpha1.LdapBinding
+	database := config.Spec.Database
+	if config.Spec.BindingRef != nil {
+		binding = &v1alpha1.LdapBinding{}
+		if err := r.Get(ctx, types.NamespacedName{Name: config.Spec.BindingRef.Name, Namespace: config.Namespace}, binding); err != nil {
+			if !apierrors.IsNotFound(err) {
+				logger.Error(err, "failed to retrieve ldapbinding")
+				return ctrl.Result{}, err
+			}
+			return r.setNotReady(ctx, config, "BindingNotFound", fmt.Sprintf("ldapbinding %s not found", config.Spec.BindingRef.Name))
+		}
+		if binding.Spec.DirectoryRef.Name != directory.Name {
+			return r.setNotReady(ctx, config, "BindingMismatch", fmt.Sprintf("ldapbinding %s belongs to directory %s",
+				binding.Name, binding.Spec.DirectoryRef.Name))
+		}
+		database = binding.Spec.Database
+	}
+
+	db := directory.Database(database)
+	if db == nil {
+		return r.setNotReady(ctx, config, "DatabaseNotFound", fmt.Sprintf("database %q not found in directory %s", database, directory.Name))
+	}
+
+	if !meta.IsStatusConditionTrue(directory.Status.Conditions, v1alpha1.DirectoryAvailableCondition) || directory.Status.Connection == nil {
+		return r.setNotReady(ctx, config, "DirectoryNotAvailable", fmt.Sprintf("directory %s is not available", directory.Name))
+	}
+
+	if binding != nil && !meta.IsStatusConditionTrue(binding.Status.Conditions, v1alpha1.LdapBindingReadyCondition) {
+		return r.setNotReady(ctx, config, "BindingNotReady", fmt.Sprintf("ldapbinding %s is not ready", binding.Name))
+	}
+	if binding != nil {
+		for _, base := range []string{db.PeopleDN(), db.GroupsDN()} {
+			if _, err := slapd.BindingSearchBase(binding, db, base); err != nil {
+				return r.setNotReady(ctx, config, "OutsideBindingScope", err.Error())
+			}
+		}
+	}
+
+	uris := clientURIs(directory, config.Spec.Endpoint)
+	if len(uris) == 0 {
+		return r.setNotReady(ctx, config, "EndpointNotAvailable", fmt.Sprintf("directory %s has no %s URIs",
+			directory.Name, strings.ToLower(string(config.Spec.Endpoint))))
+	}
+
+	if err := r.reconcileConfig(ctx, config, directory, db, binding, uris); err != nil {
+		logger.Error(err, "failed to reconcile ldapclientconfig")
+		meta.SetStatusCondition(&config.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.LdapClientConfigReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Reconciling",
+			Message: fmt.Sprintf("failed to generate client config %s: %s", config.Name, err.Error()),
+		})
+
+		if err := r.Status().Update(ctx, config); err != nil {
+			logger.Error(err, "Failed to update ldapclientconfig status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	meta.SetStatusCondition(&config.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.LdapClientConfigReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Reconciling",
+		Message: "Successfully generated client config",
+	})
+	if err := r.Status().Update(ctx, config); err != nil {
+		logger.Error(err, "Failed to update ldapclientconfig status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// setNotReady records why the client config can't be generated yet. The client config is reconciled again when
+// its directory or binding changes
+func (r *LdapClientConfigReconciler) setNotReady(ctx context.Context, config *v1alpha1.LdapClientConfig, reason, message string) (ctrl.Result, error) {
+	meta.SetStatusCondition(&config.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.LdapClientConfigReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	if err := r.Status().Update(ctx, config); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update ldapclientconfig status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// clientURIs returns the URIs clients reach the directory at. Clients of a directory using TLS only get LDAPS URIs
+func clientURIs(directory *v1alpha1.Directory, endpoint v1alpha1.ClientEndpoint) []string {
+	connection := directory.Status.Connection
+	scheme := "ldap://"
+	if directory.Spec.TLS != nil {
+		scheme = "ldaps://"
+	}
+
+	if endpoint == v1alpha1.ClientEndpointExternal {
+		uris := []string{}
+		for _, uri := range connection.ExternalURIs {
+			if strings.HasPrefix(uri, scheme) {
+				uris = append(uris, uri)
+			}
+		}
+		return uris
+	}
+
+	if directory.Spec.TLS != nil {
+		if connection.LDAPSURI == "" {
+			return nil
+		}
+		return []string{connection.LDAPSURI}
+	}
+	if connection.ReadURI != "" {
+		return []string{connection.ReadURI}
+	}
+	if connection.URI != "" {
+		return []string{connection.URI}
+	}
+	return nil
+}
+
+// reconcileConfig renders the configuration files and writes them to the ConfigMap and Secret of the client config
+func (r *LdapClientConfigReconciler) reconcileConfig(
+	ctx context.Context, config *v1alpha1.LdapClientConfig, directory *v1alpha1.Directory, db *v1alpha1.DatabaseSpec,
+	binding *v1alpha1.LdapBinding, uris []string,
+) error {
+	conn := &clientconfig.Connection{
+		URIs:          uris,
+		Base:          db.Suffix,
+		PeopleBase:    db.PeopleDN(),
+		GroupsBase:    db.GroupsDN(),
+		Domain:        config.Domain(),
+		SSHPublicKeys: slices.Contains(directory.Spec.SlapdConfig.Schemas, v1alpha1.SchemaOpenSSHLPK),
+	}
+	if slices.Contains(directory.Spec.SlapdConfig.Schemas, v1alpha1.SchemaSudo) {
+		conn.SudoBase = db.SudoersDN()
+	}
+
+	if binding != nil {
+		secretName := binding.Status.SecretName
+		if secretName == "" {
+			secretName = binding.SecretName()
+		}
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: binding.Namespace}, secret); err != nil {
+			return err
+		}
+		conn.Base = string(secret.Data["base-dn"])
+		conn.BindDN = string(secret.Data["bind-dn"])
+		conn.BindPassword = string(secret.Data["password"])
+
+		// Clients of a binding scoped below the suffix search within the scope. Sudo rules outside it are left out
+		var err error
+		if conn.PeopleBase, err = slapd.BindingSearchBase(binding, db, conn.PeopleBase); err != nil {
+			return err
+		}
+		if conn.GroupsBase, err = slapd.BindingSearchBase(binding, db, conn.GroupsBase); err != nil {
+			return err
+		}
+		if conn.SudoBase != "" {
+			conn.SudoBase, _ = slapd.BindingSearchBase(binding, db, conn.SudoBase)
+		}
+	}
+
+	configMapData := map[string]string{}
+	if ref := directory.Status.Connection.CASecretRef; ref != nil {
+		ca, err := secretValue(ctx, r, directory.Namespace, ref)
+		if err != nil {
+			return err
+		}
+		configMapData["ca.crt"] = string(ca)
+		conn.CACertFile = config.CACertPath()
+		conn.CACert = ca
+	}
+
+	if config.Generates(v1alpha1.ClientConfigLdapConf) {
+		configMapData[clientconfig.LdapConf] = clientconfig.LdapConfig(conn)
+	}
+	secretData := map[string][]byte{}
+	if config.Generates(v1alpha1.ClientConfigSSSD) {
+		secretData[clientconfig.SSSDConf] = []byte(clientconfig.SSSDConfig(conn))
+	}
+	if config.Generates(v1alpha1.ClientConfigNslcd) {
+		secretData[clientconfig.NslcdConf] = []byte(clientconfig.NslcdConfig(conn))
+	}
+	if config.Generates(v1alpha1.ClientConfigDex) {
+		spec := config.DexConnector()
+		connector, err := clientconfig.DexConnectorConfig(conn, &clientconfig.Dex{
+			ID:             spec.ID,
+			Name:           spec.Name,
+			UsernamePrompt: spec.UsernamePrompt,
+		})
+		if err != nil {
+			return err
+		}
+		secretData[clientconfig.DexConnector] = []byte(connector)
+	}
+
+	if err := r.reconcileConfigMap(ctx, config, configMapData); err != nil {
+		return err
+	}
+	if err := r.reconcileSecret(ctx, config, secretData); err != nil {
+		return err
+	}
+
+	config.Status.ConfigMapName = config.Name
+	config.Status.SecretName = config.Name
+	config.Status.URIs = uris
+	return nil
+}
+
+func (r *LdapClientConfigReconciler) reconcileConfigMap(ctx context.Context, config *v1alpha1.LdapClientConfig, data map[string]string) error {
+	desired, err := r.Builder.ClientConfigConfigMap(config, data)
+	if err != nil {
+		return err
+	}
+
+	existing := &corev1.ConfigMap{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return r.Create(ctx, desired)
+	}
+	if !metav1.IsControlledBy(existing, config) {
+		return fmt.Errorf("configmap %s exists and is not managed by ldapclientconfig %s", existing.Name, config.Name)
+	}
+
+	patch := client.MergeFrom(existing.DeepCopy())
+	existing.Labels = desired.Labels
+	existing.Data = desired.Data
+
+	return r.Patch(ctx, existing, patch)
+}
+
+func (r *LdapClientConfigReconciler) reconcileSecret(ctx context.Context, config *v1alpha1.LdapClientConfig, data map[string][]byte) error {
+	desired, err := r.Builder.ClientConfigSecret(config, data)
+	if err != nil {
+		return err
+	}
+
+	existing := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return r.Create(ctx, desired)
+	}
+	if !metav1.IsControlledBy(existing, config) {
+		return fmt.Errorf("secret %s exists and is not managed by ldapclientconfig %s", existing.Name, config.Name)
+	}
+
+	patch := client.MergeFrom(existing.DeepCopy())
+	existing.Labels = desired.Labels
+	existing.Data = desired.Data
+
+	return r.Patch(ctx, existing, patch)
+}
+
+// configsForDirectory maps a directory to the client configs referencing it
+func (r *LdapClientConfigReconciler) configsForDirectory(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.configsMatching(ctx, obj.GetNamespace(), func(config *v1alpha1.LdapClientConfig) bool {
+		return config.Spec.DirectoryRef.Name == obj.GetName()
+	})
+}
+
+// configsForBinding maps a binding to the client configs binding as it, so rotated passwords reach clients
+func (r *LdapClientConfigReconciler) configsForBinding(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.configsMatching(ctx, obj.GetNamespace(), func(config *v1alpha1.LdapClientConfig) bool {
+		return config.Spec.BindingRef != nil && config.Spec.BindingRef.Name == obj.GetName()
+	})
+}
+
+func (r *LdapClientConfigReconciler) configsMatching(
+	ctx context.Context, namespace string, matches func(*v1alpha1.LdapClientConfig) bool,
+) []reconcile.Request {
+	configs := &v1alpha1.LdapClientConfigList{}
+	if err := r.List(ctx, configs, client.InNamespace(namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list ldapclientconfigs")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, config := range configs.Items {
+		if matches(&config) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&config)})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LdapClientConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.LdapClientConfig{}).
+		Named("ldapclientconfig").
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
+		Watches(&v1alpha1.Directory{}, handler.EnqueueRequestsFromMapFunc(r.configsForDirectory),
+			ctrlbuilder.WithPredicates(directoryDependentsPredicate)).
+		Watches(&v1alpha1.LdapBinding{}, handler.EnqueueRequestsFromMapFunc(r.configsForBinding)).
+		Complete(r)
+}
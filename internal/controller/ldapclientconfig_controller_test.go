@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	openldapv1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/builder"
+)
+
+var _ = Describe("LdapClientConfig Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default", // TODO(user):Modify as needed
+		}
+		ldapclientconfig := &openldapv1alpha1.LdapClientConfig{}
+
+		BeforeEach(func() {
+			By("creating the custom resource for the Kind LdapClientConfig")
+			err := k8sClient.Get(ctx, typeNamespacedName, ldapclientconfig)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &openldapv1alpha1.LdapClientConfig{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: openldapv1alpha1.LdapClientConfigSpec{
+						DirectoryRef: corev1.LocalObjectReference{Name: "test-directory"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			// TODO(user): Cleanup logic after each test, like removing the resource instance.
+			resource := &openldapv1alpha1.LdapClientConfig{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance LdapClientConfig")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+		It("should successfully reconcile the resource", func() {
+			By("Reconciling the created resource")
+			controllerReconciler := &LdapClientConfigReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
+			// Example: If you expect a certain status condition after reconciliation, verify it here.
+		})
+	})
+
+	Context("When the generated objects already exist", func() {
+		ctx := context.Background()
+
+		var config *openldapv1alpha1.LdapClientConfig
+		var configMap *corev1.ConfigMap
+
+		BeforeEach(func() {
+			config = &openldapv1alpha1.LdapClientConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "existing-config", Namespace: "default"},
+				Spec: openldapv1alpha1.LdapClientConfigSpec{
+					DirectoryRef: corev1.LocalObjectReference{Name: "test-directory"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, config)).To(Succeed())
+
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "existing-config", Namespace: "default"},
+				Data:       map[string]string{"app.conf": "unrelated"},
+			}
+			Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, configMap)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, config)).To(Succeed())
+		})
+
+		It("doesn't overwrite objects it doesn't manage", func() {
+			reconciler := &LdapClientConfigReconciler{
+				Client:  k8sClient,
+				Scheme:  k8sClient.Scheme(),
+				Builder: builder.NewBuilder(k8sClient.Scheme()),
+			}
+
+			err := reconciler.reconcileConfigMap(ctx, config, map[string]string{"ldap.conf": "URI ldap://example"})
+			Expect(err).To(MatchError(ContainSubstring("configmap existing-config exists and is not managed by ldapclientconfig existing-config")))
+
+			existing := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(configMap), existing)).To(Succeed())
+			Expect(existing.Data).To(Equal(map[string]string{"app.conf": "unrelated"}))
+		})
+	})
+})
@@ -0,0 +1,366 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/builder"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+// LdapGroupReconciler reconciles a LdapGroup object
+type LdapGroupReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Builder  *builder.Builder
+}
+
+const (
+	ldapGroupFinalizer = "openldap.my.domain/ldapGroupFinalizer"
+)
+
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapgroups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapgroups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapgroups/finalizers,verbs=update
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapusers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=directories,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile ldapgroup resource
+func (r *LdapGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	group := &v1alpha1.LdapGroup{}
+	if err := r.Get(ctx, req.NamespacedName, group); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("ldapgroup not found, ignoring since it must have been deleted")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "failed to retrieve ldapgroup")
+		return ctrl.Result{}, err
+	}
+
+	// Set group condition to Unknown if no condition is already defined
+	if len(group.Status.Conditions) == 0 {
+		meta.SetStatusCondition(&group.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.LdapGroupReadyCondition,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "Reconciling",
+			Message: "Starting reconciler for new group",
+		})
+		if err := r.Status().Update(ctx, group); err != nil {
+			logger.Error(err, "Failed to update ldapgroup status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	directory := &v1alpha1.Directory{}
+	directoryErr := r.Get(ctx, types.NamespacedName{Name: group.Spec.DirectoryRef.Name, Namespace: group.Namespace}, directory)
+	if directoryErr != nil && !apierrors.IsNotFound(directoryErr) {
+		logger.Error(directoryErr, "failed to retrieve directory")
+		return ctrl.Result{}, directoryErr
+	}
+
+	// Group marked for deletion
+	if !group.ObjectMeta.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(group, ldapGroupFinalizer) {
+			if directoryErr == nil && directory.ObjectMeta.DeletionTimestamp.IsZero() {
+				logger.Info("removing entry of ldapgroup")
+				if err := r.deleteGroup(ctx, group, directory); err != nil {
+					logger.Error(err, "failed to remove entry of ldapgroup")
+					return ctrl.Result{}, err
+				}
+			}
+			controllerutil.RemoveFinalizer(group, ldapGroupFinalizer)
+			if err := r.Update(ctx, group); err != nil {
+				logger.Error(err, "failed to remove finalizer from ldapgroup")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Add finalizer if needed
+	if !controllerutil.ContainsFinalizer(group, ldapGroupFinalizer) {
+		controllerutil.AddFinalizer(group, ldapGroupFinalizer)
+		if err := r.Update(ctx, group); err != nil {
+			logger.Error(err, "failed to update ldapgroup with finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if directoryErr != nil {
+		return r.setNotReady(ctx, group, "DirectoryNotFound", fmt.Sprintf("directory %s not found", group.Spec.DirectoryRef.Name))
+	}
+
+	db := directory.Database(group.Spec.Database)
+	if db == nil {
+		return r.setNotReady(ctx, group, "DatabaseNotFound", fmt.Sprintf("database %q not found in directory %s", group.Spec.Database, directory.Name))
+	}
+
+	if !meta.IsStatusConditionTrue(directory.Status.Conditions, v1alpha1.DirectoryAvailableCondition) {
+		return r.setNotReady(ctx, group, "DirectoryNotAvailable", fmt.Sprintf("directory %s is not available", directory.Name))
+	}
+
+	members, pending, err := r.resolveMembers(ctx, group, directory, db)
+	if err != nil {
+		logger.Error(err, "failed to resolve ldapgroup members")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileGroup(ctx, group, directory, db, members); err != nil {
+		logger.Error(err, "failed to reconcile ldapgroup entry")
+		meta.SetStatusCondition(&group.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.LdapGroupReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Reconciling",
+			Message: fmt.Sprintf("failed to reconcile entry for group %s: %s", group.Name, err.Error()),
+		})
+
+		if err := r.Status().Update(ctx, group); err != nil {
+			logger.Error(err, "Failed to update ldapgroup status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// Members which aren't ready yet are added once their users are reconciled
+	if len(pending) > 0 {
+		return r.setNotReady(ctx, group, "MembersNotReady", fmt.Sprintf("users %s are not ready", strings.Join(pending, ", ")))
+	}
+
+	meta.SetStatusCondition(&group.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.LdapGroupReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Reconciling",
+		Message: "Successfully reconciled group entry",
+	})
+	if err := r.Status().Update(ctx, group); err != nil {
+		logger.Error(err, "Failed to update ldapgroup status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// setNotReady records why the group can't be fully reconciled yet. The group is reconciled again when its
+// directory or members change
+func (r *LdapGroupReconciler) setNotReady(ctx context.Context, group *v1alpha1.LdapGroup, reason, message string) (ctrl.Result, error) {
+	meta.SetStatusCondition(&group.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.LdapGroupReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	if err := r.Status().Update(ctx, group); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update ldapgroup status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// resolveMembers resolves the members of a group to their users. Users which don't exist, belong to another
+// directory or database, or haven't been reconciled yet are returned as pending
+func (r *LdapGroupReconciler) resolveMembers(
+	ctx context.Context, group *v1alpha1.LdapGroup, directory *v1alpha1.Directory, db *v1alpha1.DatabaseSpec,
+) ([]*v1alpha1.LdapUser, []string, error) {
+	members := []*v1alpha1.LdapUser{}
+	pending := []string{}
+	for _, member := range group.Spec.Members {
+		user := &v1alpha1.LdapUser{}
+		if err := r.Get(ctx, types.NamespacedName{Name: member.Name, Namespace: group.Namespace}, user); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, nil, err
+			}
+			pending = append(pending, member.Name)
+			continue
+		}
+
+		userDB := directory.Database(user.Spec.Database)
+		if user.Spec.DirectoryRef.Name != directory.Name || userDB == nil || userDB.Name != db.Name ||
+			!user.ObjectMeta.DeletionTimestamp.IsZero() ||
+			!meta.IsStatusConditionTrue(user.Status.Conditions, v1alpha1.LdapUserReadyCondition) {
+			pending = append(pending, member.Name)
+			continue
+		}
+		members = append(members, user)
+	}
+	return members, pending, nil
+}
+
+// reconcileGroup creates or updates the group entry. The previous entry is removed when the DN of the group changes,
+// and the entry is recreated when it switches between a groupOfNames and a posixGroup, as the structural object
+// class of an entry can't be modified
+func (r *LdapGroupReconciler) reconcileGroup(
+	ctx context.Context, group *v1alpha1.LdapGroup, directory *v1alpha1.Directory, db *v1alpha1.DatabaseSpec, members []*v1alpha1.LdapUser,
+) error {
+	if err := r.reconcileIDs(ctx, group, directory); err != nil {
+		return err
+	}
+
+	conn, err := connectDirectory(ctx, r, directory, db.RootDN())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	suffix, err := slapd.SuffixEntry(db.Suffix)
+	if err != nil {
+		return err
+	}
+	for _, parent := range []*slapd.Entry{suffix, slapd.GroupsEntry(db)} {
+		if _, err := conn.Add(parent); err != nil {
+			return err
+		}
+	}
+
+	dn := group.DN(db)
+	if group.Status.DN != "" && group.Status.DN != dn {
+		if err := conn.Delete(group.Status.DN); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(group, corev1.EventTypeNormal, "Renamed", "Moved group entry from %s to %s", group.Status.DN, dn)
+	}
+
+	entry := slapd.GroupEntry(group, db, members)
+	existing, err := conn.Get(dn, "objectClass")
+	if err != nil {
+		return err
+	}
+	if existing != nil && !slices.ContainsFunc(existing.GetAttributeValues("objectClass"), func(class string) bool {
+		return strings.EqualFold(class, entry.Get("objectClass")[0])
+	}) {
+		if err := conn.Delete(dn); err != nil {
+			return err
+		}
+	}
+	if err := conn.Ensure(entry); err != nil {
+		return err
+	}
+
+	group.Status.DN = dn
+	group.Status.Members = []string{}
+	for _, member := range members {
+		group.Status.Members = append(group.Status.Members, member.Status.DN)
+	}
+	return nil
+}
+
+// reconcileIDs records the gidNumber of a POSIX group, allocating one if it isn't set in the spec. The status is
+// saved before the entry is written so an allocated id isn't lost if the reconcile fails
+func (r *LdapGroupReconciler) reconcileIDs(ctx context.Context, group *v1alpha1.LdapGroup, directory *v1alpha1.Directory) error {
+	if group.Spec.Posix == nil {
+		return nil
+	}
+
+	allocator := &idAllocator{Client: r.Client, Builder: r.Builder}
+	gid, err := allocator.Resolve(ctx, directory, gidKind, group, group.Spec.Posix.GIDNumber, group.Status.GIDNumber)
+	if err != nil {
+		return err
+	}
+	if ptr.Equal(gid, group.Status.GIDNumber) {
+		return nil
+	}
+
+	group.Status.GIDNumber = gid
+	return r.Status().Update(ctx, group)
+}
+
+func (r *LdapGroupReconciler) deleteGroup(ctx context.Context, group *v1alpha1.LdapGroup, directory *v1alpha1.Directory) error {
+	db := directory.Database(group.Spec.Database)
+	if db == nil {
+		return nil
+	}
+
+	conn, err := connectDirectory(ctx, r, directory, db.RootDN())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	dn := group.Status.DN
+	if dn == "" {
+		dn = group.DN(db)
+	}
+	return conn.Delete(dn)
+}
+
+// groupsForDirectory maps a directory to the groups referencing it
+func (r *LdapGroupReconciler) groupsForDirectory(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.groupsMatching(ctx, obj.GetNamespace(), func(group *v1alpha1.LdapGroup) bool {
+		return group.Spec.DirectoryRef.Name == obj.GetName()
+	})
+}
+
+// groupsForUser maps a user to the groups it is a member of
+func (r *LdapGroupReconciler) groupsForUser(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.groupsMatching(ctx, obj.GetNamespace(), func(group *v1alpha1.LdapGroup) bool {
+		return group.HasMember(obj.GetName())
+	})
+}
+
+func (r *LdapGroupReconciler) groupsMatching(ctx context.Context, namespace string, match func(*v1alpha1.LdapGroup) bool) []reconcile.Request {
+	groups := &v1alpha1.LdapGroupList{}
+	if err := r.List(ctx, groups, client.InNamespace(namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list ldapgroups")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, group := range groups.Items {
+		if match(&group) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&group)})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LdapGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.LdapGroup{}).
+		Named("ldapgroup").
+		Watches(&v1alpha1.Directory{}, handler.EnqueueRequestsFromMapFunc(r.groupsForDirectory),
+			ctrlbuilder.WithPredicates(directoryDependentsPredicate)).
+		Watches(&v1alpha1.LdapUser{}, handler.EnqueueRequestsFromMapFunc(r.groupsForUser)).
+		Complete(r)
+}
@@ -0,0 +1,446 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/builder"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+// LdapUserReconciler reconciles a LdapUser object
+type LdapUserReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Builder  *builder.Builder
+}
+
+const (
+	ldapUserFinalizer = "openldap.my.domain/ldapUserFinalizer"
+)
+
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapusers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapusers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=ldapusers/finalizers,verbs=update
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=directories,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile ldapuser resource
+func (r *LdapUserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	user := &v1alpha1.LdapUser{}
+	if err := r.Get(ctx, req.NamespacedName, user); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("ldapuser not found, ignoring since it must have been deleted")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "failed to retrieve ldapuser")
+		return ctrl.Result{}, err
+	}
+
+	// Set user condition to Unknown if no condition is already defined
+	if len(user.Status.Conditions) == 0 {
+		meta.SetStatusCondition(&user.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.LdapUserReadyCondition,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "Reconciling",
+			Message: "Starting reconciler for new user",
+		})
+		if err := r.Status().Update(ctx, user); err != nil {
+			logger.Error(err, "Failed to update ldapuser status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	directory := &v1alpha1.Directory{}
+	directoryErr := r.Get(ctx, types.NamespacedName{Name: user.Spec.DirectoryRef.Name, Namespace: user.Namespace}, directory)
+	if directoryErr != nil && !apierrors.IsNotFound(directoryErr) {
+		logger.Error(directoryErr, "failed to retrieve directory")
+		return ctrl.Result{}, directoryErr
+	}
+
+	// User marked for deletion
+	if !user.ObjectMeta.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(user, ldapUserFinalizer) {
+			if directoryErr == nil && directory.ObjectMeta.DeletionTimestamp.IsZero() {
+				logger.Info("removing entry of ldapuser")
+				if err := r.deleteUser(ctx, user, directory); err != nil {
+					logger.Error(err, "failed to remove entry of ldapuser")
+					return ctrl.Result{}, err
+				}
+			}
+			controllerutil.RemoveFinalizer(user, ldapUserFinalizer)
+			if err := r.Update(ctx, user); err != nil {
+				logger.Error(err, "failed to remove finalizer from ldapuser")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Add finalizer if needed
+	if !controllerutil.ContainsFinalizer(user, ldapUserFinalizer) {
+		controllerutil.AddFinalizer(user, ldapUserFinalizer)
+		if err := r.Update(ctx, user); err != nil {
+			logger.Error(err, "failed to update ldapuser with finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if directoryErr != nil {
+		return r.setNotReady(ctx, user, "DirectoryNotFound", fmt.Sprintf("directory %s not found", user.Spec.DirectoryRef.Name))
+	}
+
+	db := directory.Database(user.Spec.Database)
+	if db == nil {
+		return r.setNotReady(ctx, user, "DatabaseNotFound", fmt.Sprintf("database %q not found in directory %s", user.Spec.Database, directory.Name))
+	}
+
+	if !meta.IsStatusConditionTrue(directory.Status.Conditions, v1alpha1.DirectoryAvailableCondition) {
+		return r.setNotReady(ctx, user, "DirectoryNotAvailable", fmt.Sprintf("directory %s is not available", directory.Name))
+	}
+
+	if len(user.Spec.SSHPublicKeys) > 0 && !slices.Contains(directory.Spec.SlapdConfig.Schemas, v1alpha1.SchemaOpenSSHLPK) {
+		return r.setNotReady(ctx, user, "SchemaNotLoaded", fmt.Sprintf("directory %s doesn't load the %s schema required by SSH public keys",
+			directory.Name, v1alpha1.SchemaOpenSSHLPK))
+	}
+
+	owner, err := r.dnOwner(ctx, user, directory, db)
+	if err != nil {
+		logger.Error(err, "failed to check ownership of the ldapuser entry")
+		return ctrl.Result{}, err
+	}
+	if owner != nil {
+		return r.setNotReady(ctx, user, "Conflict", fmt.Sprintf("uid %s is already used by ldapuser %s", user.UID(), owner.Name))
+	}
+
+	expiry, err := r.reconcileUser(ctx, user, directory, db)
+	if err != nil {
+		logger.Error(err, "failed to reconcile ldapuser entry")
+		meta.SetStatusCondition(&user.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.LdapUserReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Reconciling",
+			Message: fmt.Sprintf("failed to reconcile entry for user %s: %s", user.Name, err.Error()),
+		})
+
+		if err := r.Status().Update(ctx, user); err != nil {
+			logger.Error(err, "Failed to update ldapuser status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	meta.SetStatusCondition(&user.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.LdapUserReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Reconciling",
+		Message: "Successfully reconciled user entry",
+	})
+	if err := r.Status().Update(ctx, user); err != nil {
+		logger.Error(err, "Failed to update ldapuser status")
+		return ctrl.Result{}, err
+	}
+
+	// Reconcile again to remove SSH public keys once they expire
+	if expiry != nil {
+		return ctrl.Result{RequeueAfter: time.Until(*expiry)}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// setNotReady records why the user can't be reconciled yet. The user is reconciled again when its
+// directory changes
+func (r *LdapUserReconciler) setNotReady(ctx context.Context, user *v1alpha1.LdapUser, reason, message string) (ctrl.Result, error) {
+	meta.SetStatusCondition(&user.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.LdapUserReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	if err := r.Status().Update(ctx, user); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update ldapuser status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// dnOwner returns the other user owning the entry of a user, if any. An entry is owned by the user which recorded
+// it in its status, or otherwise by the oldest user claiming it, so two users with the same uid don't overwrite
+// each other's entry
+func (r *LdapUserReconciler) dnOwner(
+	ctx context.Context, user *v1alpha1.LdapUser, directory *v1alpha1.Directory, db *v1alpha1.DatabaseSpec,
+) (*v1alpha1.LdapUser, error) {
+	dn := user.DN(db)
+	if strings.EqualFold(user.Status.DN, dn) {
+		return nil, nil
+	}
+
+	users := &v1alpha1.LdapUserList{}
+	if err := r.List(ctx, users, client.InNamespace(user.Namespace)); err != nil {
+		return nil, err
+	}
+
+	var owner *v1alpha1.LdapUser
+	for i := range users.Items {
+		other := &users.Items[i]
+		if other.Name == user.Name || other.Spec.DirectoryRef.Name != directory.Name {
+			continue
+		}
+		if strings.EqualFold(other.Status.DN, dn) {
+			return other, nil
+		}
+		otherDB := directory.Database(other.Spec.Database)
+		if otherDB == nil || !strings.EqualFold(other.DN(otherDB), dn) || !claimedBefore(other, user) {
+			continue
+		}
+		if owner == nil || claimedBefore(other, owner) {
+			owner = other
+		}
+	}
+	return owner, nil
+}
+
+// claimedBefore orders users by creation, breaking ties by name
+func claimedBefore(a, b *v1alpha1.LdapUser) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// reconcileUser creates or updates the user entry. The previous entry is removed when the DN of the user changes.
+// Returns the time the next active SSH public key expires
+func (r *LdapUserReconciler) reconcileUser(
+	ctx context.Context, user *v1alpha1.LdapUser, directory *v1alpha1.Directory, db *v1alpha1.DatabaseSpec,
+) (*time.Time, error) {
+	if err := r.reconcileIDs(ctx, user, directory); err != nil {
+		return nil, err
+	}
+
+	password := []byte{}
+	if user.Spec.PasswordSecretRef != nil {
+		value, err := secretValue(ctx, r, user.Namespace, user.Spec.PasswordSecretRef)
+		if err != nil {
+			return nil, err
+		}
+		password = value
+	}
+
+	conn, err := connectDirectory(ctx, r, directory, db.RootDN())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	suffix, err := slapd.SuffixEntry(db.Suffix)
+	if err != nil {
+		return nil, err
+	}
+	for _, parent := range []*slapd.Entry{suffix, slapd.PeopleEntry(db)} {
+		if _, err := conn.Add(parent); err != nil {
+			return nil, err
+		}
+	}
+
+	dn := user.DN(db)
+	if user.Status.DN != "" && user.Status.DN != dn {
+		if err := conn.Delete(user.Status.DN); err != nil {
+			return nil, err
+		}
+		r.Recorder.Eventf(user, corev1.EventTypeNormal, "Renamed", "Moved user entry from %s to %s", user.Status.DN, dn)
+	}
+
+	hash := ""
+	if len(password) > 0 {
+		hash, err = conn.PasswordHash(dn, "userPassword", password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sshKeys, keyStatuses, expiry := slapd.SSHPublicKeys(user, time.Now())
+	if err := conn.EnsureClasses(slapd.UserEntry(user, db, hash, sshKeys)); err != nil {
+		return nil, err
+	}
+
+	user.Status.DN = dn
+	r.recordSSHPublicKeys(user, keyStatuses)
+	return expiry, nil
+}
+
+// recordSSHPublicKeys records the state of the SSH public keys of a user, remembering the fingerprints of revoked
+// keys and emitting an event for every key which stops being active
+func (r *LdapUserReconciler) recordSSHPublicKeys(user *v1alpha1.LdapUser, statuses []v1alpha1.SSHPublicKeyStatus) {
+	previous := map[string]v1alpha1.SSHPublicKeyState{}
+	for _, key := range user.Status.SSHPublicKeys {
+		previous[key.Name] = key.State
+	}
+
+	for _, key := range statuses {
+		if key.State == v1alpha1.SSHPublicKeyRevoked && !slices.Contains(user.Status.RevokedSSHPublicKeys, key.Fingerprint) {
+			user.Status.RevokedSSHPublicKeys = append(user.Status.RevokedSSHPublicKeys, key.Fingerprint)
+		}
+		if key.State == previous[key.Name] {
+			continue
+		}
+		switch key.State {
+		case v1alpha1.SSHPublicKeyInvalid:
+			r.Recorder.Eventf(user, corev1.EventTypeWarning, "InvalidSSHPublicKey", "Rejected SSH public key %s: %s", key.Name, key.Message)
+		case v1alpha1.SSHPublicKeyExpired:
+			r.Recorder.Eventf(user, corev1.EventTypeNormal, "SSHPublicKeyExpired", "Removed expired SSH public key %s", key.Name)
+		case v1alpha1.SSHPublicKeyRevoked:
+			r.Recorder.Eventf(user, corev1.EventTypeNormal, "SSHPublicKeyRevoked", "Removed revoked SSH public key %s (%s)", key.Name, key.Fingerprint)
+		}
+	}
+	user.Status.SSHPublicKeys = statuses
+}
+
+// reconcileIDs records the uidNumber and gidNumber of a POSIX user, allocating those which aren't set in the spec.
+// The status is saved before the entry is written so allocated ids aren't lost if the reconcile fails. Recorded ids
+// are kept when the user stops being a POSIX user, so it gets the same ids back if it becomes one again
+func (r *LdapUserReconciler) reconcileIDs(ctx context.Context, user *v1alpha1.LdapUser, directory *v1alpha1.Directory) error {
+	posix := user.Spec.Posix
+	if posix == nil {
+		return nil
+	}
+
+	allocator := &idAllocator{Client: r.Client, Builder: r.Builder}
+	uid, err := allocator.Resolve(ctx, directory, uidKind, user, posix.UIDNumber, user.Status.UIDNumber)
+	if err != nil {
+		return err
+	}
+	gid, err := allocator.Resolve(ctx, directory, gidKind, user, posix.GIDNumber, user.Status.GIDNumber)
+	if err != nil {
+		return err
+	}
+	if ptr.Equal(uid, user.Status.UIDNumber) && ptr.Equal(gid, user.Status.GIDNumber) {
+		return nil
+	}
+
+	user.Status.UIDNumber = uid
+	user.Status.GIDNumber = gid
+	return r.Status().Update(ctx, user)
+}
+
+func (r *LdapUserReconciler) deleteUser(ctx context.Context, user *v1alpha1.LdapUser, directory *v1alpha1.Directory) error {
+	db := directory.Database(user.Spec.Database)
+	if db == nil {
+		return nil
+	}
+
+	// A user which never recorded its entry only removes it when no other user owns it
+	dn := user.Status.DN
+	if dn == "" {
+		owner, err := r.dnOwner(ctx, user, directory, db)
+		if err != nil || owner != nil {
+			return err
+		}
+		dn = user.DN(db)
+	}
+
+	conn, err := connectDirectory(ctx, r, directory, db.RootDN())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Delete(dn)
+}
+
+// usersForDirectory maps a directory to the users referencing it
+func (r *LdapUserReconciler) usersForDirectory(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.usersMatching(ctx, obj.GetNamespace(), func(user *v1alpha1.LdapUser) bool {
+		return user.Spec.DirectoryRef.Name == obj.GetName()
+	})
+}
+
+// usersForSecret maps a secret to the users taking their password from it
+func (r *LdapUserReconciler) usersForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.usersMatching(ctx, obj.GetNamespace(), func(user *v1alpha1.LdapUser) bool {
+		return user.Spec.PasswordSecretRef != nil && user.Spec.PasswordSecretRef.Name == obj.GetName()
+	})
+}
+
+// usersSharingUID maps a user to the other users with the same uid, so users in conflict with it are reconciled
+// again once it releases the entry
+func (r *LdapUserReconciler) usersSharingUID(ctx context.Context, obj client.Object) []reconcile.Request {
+	changed, ok := obj.(*v1alpha1.LdapUser)
+	if !ok {
+		return nil
+	}
+	return r.usersMatching(ctx, obj.GetNamespace(), func(user *v1alpha1.LdapUser) bool {
+		return user.Name != changed.Name && user.Spec.DirectoryRef.Name == changed.Spec.DirectoryRef.Name &&
+			strings.EqualFold(user.UID(), changed.UID())
+	})
+}
+
+func (r *LdapUserReconciler) usersMatching(ctx context.Context, namespace string, match func(*v1alpha1.LdapUser) bool) []reconcile.Request {
+	users := &v1alpha1.LdapUserList{}
+	if err := r.List(ctx, users, client.InNamespace(namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list ldapusers")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, user := range users.Items {
+		if match(&user) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&user)})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LdapUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.LdapUser{}).
+		Named("ldapuser").
+		Watches(&v1alpha1.Directory{}, handler.EnqueueRequestsFromMapFunc(r.usersForDirectory),
+			ctrlbuilder.WithPredicates(directoryDependentsPredicate)).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.usersForSecret)).
+		Watches(&v1alpha1.LdapUser{}, handler.EnqueueRequestsFromMapFunc(r.usersSharingUID)).
+		Complete(r)
+}
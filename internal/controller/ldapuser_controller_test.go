@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	openldapv1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+var _ = Describe("LdapUser Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default", // TODO(user):Modify as needed
+		}
+		ldapuser := &openldapv1alpha1.LdapUser{}
+
+		BeforeEach(func() {
+			By("creating the custom resource for the Kind LdapUser")
+			err := k8sClient.Get(ctx, typeNamespacedName, ldapuser)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &openldapv1alpha1.LdapUser{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: openldapv1alpha1.LdapUserSpec{
+						DirectoryRef: corev1.LocalObjectReference{Name: "test-directory"},
+						CN:           "Test User",
+						SN:           "User",
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			// TODO(user): Cleanup logic after each test, like removing the resource instance.
+			resource := &openldapv1alpha1.LdapUser{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance LdapUser")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+		It("should successfully reconcile the resource", func() {
+			By("Reconciling the created resource")
+			controllerReconciler := &LdapUserReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
+			// Example: If you expect a certain status condition after reconciliation, verify it here.
+		})
+	})
+
+	Context("When two users claim the same uid", func() {
+		ctx := context.Background()
+
+		var reconciler *LdapUserReconciler
+		var directory *openldapv1alpha1.Directory
+		var first, second *openldapv1alpha1.LdapUser
+
+		newUser := func(name string) *openldapv1alpha1.LdapUser {
+			return &openldapv1alpha1.LdapUser{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: openldapv1alpha1.LdapUserSpec{
+					DirectoryRef: corev1.LocalObjectReference{Name: "test-directory"},
+					UID:          "jdoe",
+					CN:           "Jane Doe",
+					SN:           "Doe",
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			reconciler = &LdapUserReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			directory = &openldapv1alpha1.Directory{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-directory", Namespace: "default"},
+				Spec: openldapv1alpha1.DirectorySpec{
+					SlapdConfig: &openldapv1alpha1.SlapdConfigSpec{
+						Databases: []openldapv1alpha1.DatabaseSpec{{Name: "example", Suffix: "dc=example,dc=com"}},
+					},
+				},
+			}
+			first = newUser("jdoe-first")
+			second = newUser("jdoe-second")
+			Expect(k8sClient.Create(ctx, first)).To(Succeed())
+			Expect(k8sClient.Create(ctx, second)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, first)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, second)).To(Succeed())
+		})
+
+		It("gives the entry to the user which recorded it", func() {
+			db := directory.Database("")
+			second.Status.DN = second.DN(db)
+			Expect(k8sClient.Status().Update(ctx, second)).To(Succeed())
+
+			owner, err := reconciler.dnOwner(ctx, first, directory, db)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(owner).NotTo(BeNil())
+			Expect(owner.Name).To(Equal("jdoe-second"))
+
+			owner, err = reconciler.dnOwner(ctx, second, directory, db)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(owner).To(BeNil())
+		})
+
+		It("gives an unrecorded entry to the user created first", func() {
+			db := directory.Database("")
+			owner, err := reconciler.dnOwner(ctx, second, directory, db)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(owner).NotTo(BeNil())
+			Expect(owner.Name).To(Equal("jdoe-first"))
+
+			owner, err = reconciler.dnOwner(ctx, first, directory, db)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(owner).To(BeNil())
+		})
+
+		It("requeues the other users of the uid", func() {
+			Expect(reconciler.usersSharingUID(ctx, first)).To(ConsistOf(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "jdoe-second", Namespace: "default"},
+			}))
+		})
+	})
+})
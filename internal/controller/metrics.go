@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	replicationLagSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "openldap_replication_lag_seconds",
+		Help: "Time by which a database of a directory instance trails the newest changes across instances",
+	}, []string{"namespace", "directory", "pod", "database"})
+
+	replicationInSync = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "openldap_replication_in_sync",
+		Help: "Whether a directory instance holds the newest changes of every database",
+	}, []string{"namespace", "directory", "pod"})
+
+	replicationPeerReachable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "openldap_replication_peer_reachable",
+		Help: "Whether the operator could bind to a replication peer outside the cluster",
+	}, []string{"namespace", "directory", "peer"})
+
+	unindexedSearches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "openldap_unindexed_searches_total",
+		Help: "Number of times slapd reported an attribute searched in a database as not indexed",
+	}, []string{"namespace", "directory", "database", "attribute"})
+
+	slowSearches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "openldap_slow_searches_total",
+		Help: "Number of searches of a database slower than the index advisor threshold, by attribute filtered on",
+	}, []string{"namespace", "directory", "database", "attribute"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(replicationLagSeconds, replicationInSync, replicationPeerReachable,
+		unindexedSearches, slowSearches)
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+// SudoRuleReconciler reconciles a SudoRule object
+type SudoRuleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+const (
+	sudoRuleFinalizer = "openldap.my.domain/sudoRuleFinalizer"
+)
+
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=sudorules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=sudorules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=sudorules/finalizers,verbs=update
+// +kubebuilder:rbac:groups=openldap.my.domain,resources=directories,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile sudorule resource
+func (r *SudoRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	rule := &v1alpha1.SudoRule{}
+	if err := r.Get(ctx, req.NamespacedName, rule); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("sudorule not found, ignoring since it must have been deleted")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "failed to retrieve sudorule")
+		return ctrl.Result{}, err
+	}
+
+	// Set rule condition to Unknown if no condition is already defined
+	if len(rule.Status.Conditions) == 0 {
+		meta.SetStatusCondition(&rule.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.SudoRuleReadyCondition,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "Reconciling",
+			Message: "Starting reconciler for new rule",
+		})
+		if err := r.Status().Update(ctx, rule); err != nil {
+			logger.Error(err, "Failed to update sudorule status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	directory := &v1alpha1.Directory{}
+	directoryErr := r.Get(ctx, types.NamespacedName{Name: rule.Spec.DirectoryRef.Name, Namespace: rule.Namespace}, directory)
+	if directoryErr != nil && !apierrors.IsNotFound(directoryErr) {
+		logger.Error(directoryErr, "failed to retrieve directory")
+		return ctrl.Result{}, directoryErr
+	}
+
+	// Rule marked for deletion
+	if !rule.ObjectMeta.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(rule, sudoRuleFinalizer) {
+			if directoryErr == nil && directory.ObjectMeta.DeletionTimestamp.IsZero() {
+				logger.Info("removing entry of sudorule")
+				if err := r.deleteRule(ctx, rule, directory); err != nil {
+					logger.Error(err, "failed to remove entry of sudorule")
+					return ctrl.Result{}, err
+				}
+			}
+			controllerutil.RemoveFinalizer(rule, sudoRuleFinalizer)
+			if err := r.Update(ctx, rule); err != nil {
+				logger.Error(err, "failed to remove finalizer from sudorule")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Add finalizer if needed
+	if !controllerutil.ContainsFinalizer(rule, sudoRuleFinalizer) {
+		controllerutil.AddFinalizer(rule, sudoRuleFinalizer)
+		if err := r.Update(ctx, rule); err != nil {
+			logger.Error(err, "failed to update sudorule with finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := slapd.ValidateSudoRule(rule); err != nil {
+		return r.setNotReady(ctx, rule, "InvalidRule", err.Error())
+	}
+
+	if directoryErr != nil {
+		return r.setNotReady(ctx, rule, "DirectoryNotFound", fmt.Sprintf("directory %s not found", rule.Spec.DirectoryRef.Name))
+	}
+
+	db := directory.Database(rule.Spec.Database)
+	if db == nil {
+		return r.setNotReady(ctx, rule, "DatabaseNotFound", fmt.Sprintf("database %q not found in directory %s", rule.Spec.Database, directory.Name))
+	}
+
+	if !slices.Contains(directory.Spec.SlapdConfig.Schemas, v1alpha1.SchemaSudo) {
+		return r.setNotReady(ctx, rule, "SchemaNotLoaded", fmt.Sprintf("directory %s doesn't load the %s schema",
+			directory.Name, v1alpha1.SchemaSudo))
+	}
+
+	if !meta.IsStatusConditionTrue(directory.Status.Conditions, v1alpha1.DirectoryAvailableCondition) {
+		return r.setNotReady(ctx, rule, "DirectoryNotAvailable", fmt.Sprintf("directory %s is not available", directory.Name))
+	}
+
+	if err := r.reconcileRule(ctx, rule, directory, db); err != nil {
+		logger.Error(err, "failed to reconcile sudorule entry")
+		meta.SetStatusCondition(&rule.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.SudoRuleReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Reconciling",
+			Message: fmt.Sprintf("failed to reconcile entry for rule %s: %s", rule.Name, err.Error()),
+		})
+
+		if err := r.Status().Update(ctx, rule); err != nil {
+			logger.Error(err, "Failed to update sudorule status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	meta.SetStatusCondition(&rule.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.SudoRuleReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Reconciling",
+		Message: "Successfully reconciled sudoRole entry",
+	})
+	if err := r.Status().Update(ctx, rule); err != nil {
+		logger.Error(err, "Failed to update sudorule status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// setNotReady records why the rule can't be reconciled yet. The rule is reconciled again when it or its
+// directory changes
+func (r *SudoRuleReconciler) setNotReady(ctx context.Context, rule *v1alpha1.SudoRule, reason, message string) (ctrl.Result, error) {
+	meta.SetStatusCondition(&rule.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.SudoRuleReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	if err := r.Status().Update(ctx, rule); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update sudorule status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileRule creates or updates the sudoRole entry. The previous entry is removed when the DN of the rule
+// changes, e.g. because the sudoers organizational unit of the database was renamed
+func (r *SudoRuleReconciler) reconcileRule(
+	ctx context.Context, rule *v1alpha1.SudoRule, directory *v1alpha1.Directory, db *v1alpha1.DatabaseSpec,
+) error {
+	conn, err := connectDirectory(ctx, r, directory, db.RootDN())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	suffix, err := slapd.SuffixEntry(db.Suffix)
+	if err != nil {
+		return err
+	}
+	for _, parent := range []*slapd.Entry{suffix, slapd.SudoersEntry(db)} {
+		if _, err := conn.Add(parent); err != nil {
+			return err
+		}
+	}
+
+	dn := rule.DN(db)
+	if rule.Status.DN != "" && rule.Status.DN != dn {
+		if err := conn.Delete(rule.Status.DN); err != nil {
+			return err
+		}
+	}
+
+	if err := conn.Ensure(slapd.SudoRoleEntry(rule, db)); err != nil {
+		return err
+	}
+
+	rule.Status.DN = dn
+	return nil
+}
+
+func (r *SudoRuleReconciler) deleteRule(ctx context.Context, rule *v1alpha1.SudoRule, directory *v1alpha1.Directory) error {
+	db := directory.Database(rule.Spec.Database)
+	if db == nil {
+		return nil
+	}
+
+	conn, err := connectDirectory(ctx, r, directory, db.RootDN())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	dn := rule.Status.DN
+	if dn == "" {
+		dn = rule.DN(db)
+	}
+	return conn.Delete(dn)
+}
+
+// rulesForDirectory maps a directory to the rules referencing it
+func (r *SudoRuleReconciler) rulesForDirectory(ctx context.Context, obj client.Object) []reconcile.Request {
+	rules := &v1alpha1.SudoRuleList{}
+	if err := r.List(ctx, rules, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list sudorules")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, rule := range rules.Items {
+		if rule.Spec.DirectoryRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&rule)})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SudoRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.SudoRule{}).
+		Named("sudorule").
+		Watches(&v1alpha1.Directory{}, handler.EnqueueRequestsFromMapFunc(r.rulesForDirectory),
+			ctrlbuilder.WithPredicates(directoryDependentsPredicate)).
+		Complete(r)
+}
@@ -0,0 +1,126 @@
+// Package events delivers CloudEvents to HTTP sinks
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// specVersion is the version of the CloudEvents specification events are delivered with
+const specVersion = "1.0"
+
+// deliveryTimeout bounds each attempt to deliver an event
+const deliveryTimeout = 30 * time.Second
+
+// Event is a CloudEvent with a JSON payload
+type Event struct {
+	ID      string
+	Source  string
+	Type    string
+	Subject string
+	Time    time.Time
+	Data    any
+}
+
+// Sink delivers events to a webhook in the binary content mode of the CloudEvents HTTP binding
+type Sink struct {
+	URL     string
+	Headers map[string]string
+	// Attempts is the number of times delivery of an event is attempted before giving up
+	Attempts int
+	// Backoff is the delay before the first retry, doubling for each retry after
+	Backoff time.Duration
+
+	client *http.Client
+}
+
+// NewSink returns a sink delivering to url, verifying the certificate of https webhooks against caCert if given
+func NewSink(url string, caCert []byte, headers map[string]string, attempts int, backoff time.Duration) (*Sink, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(caCert) > 0 {
+		config := &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: x509.NewCertPool()}
+		if !config.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in CA certificate")
+		}
+		transport.TLSClientConfig = config
+	}
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	return &Sink{
+		URL:      url,
+		Headers:  headers,
+		Attempts: attempts,
+		Backoff:  backoff,
+		client:   &http.Client{Transport: transport, Timeout: deliveryTimeout},
+	}, nil
+}
+
+// Send delivers event, retrying failed attempts which may succeed later. Returns the error of the last attempt
+// if the event couldn't be delivered
+func (sink *Sink) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	backoff := sink.Backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := sink.send(ctx, event, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= sink.Attempts {
+			return fmt.Errorf("failed to deliver event %s after %d attempts: %w", event.ID, attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// send makes a single attempt to deliver event, returning whether a failed attempt should be retried
+func (sink *Sink) send(ctx context.Context, event Event, body []byte) (bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for name, value := range sink.Headers {
+		request.Header.Set(name, value)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("ce-specversion", specVersion)
+	request.Header.Set("ce-id", event.ID)
+	request.Header.Set("ce-source", event.Source)
+	request.Header.Set("ce-type", event.Type)
+	if event.Subject != "" {
+		request.Header.Set("ce-subject", event.Subject)
+	}
+	if !event.Time.IsZero() {
+		request.Header.Set("ce-time", event.Time.UTC().Format(time.RFC3339Nano))
+	}
+
+	response, err := sink.client.Do(request)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return false, nil
+	}
+	retry := response.StatusCode >= 500 || response.StatusCode == http.StatusTooManyRequests ||
+		response.StatusCode == http.StatusRequestTimeout
+	return retry, fmt.Errorf("webhook responded with %s", response.Status)
+}
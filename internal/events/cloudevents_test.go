@@ -0,0 +1,88 @@
+package events_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/paddyoneill/openldap-operator/internal/events"
+)
+
+var _ = Describe("Sink", func() {
+	var server *httptest.Server
+	var requests atomic.Int32
+	var statuses []int
+	var received *http.Request
+	var body string
+
+	event := events.Event{
+		ID:      "5e5b3b7a-2f0e-4d5c-8d3f-1c2b3a4d5e6f/20250101120000.000000Z#000000#001#000000",
+		Source:  "/namespaces/bar/directories/foo-directory",
+		Type:    "domain.my.openldap.entry.modified",
+		Subject: "uid=jdoe,ou=people,dc=example,dc=com",
+		Time:    time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		Data:    map[string]any{"dn": "uid=jdoe,ou=people,dc=example,dc=com"},
+	}
+
+	BeforeEach(func() {
+		requests.Store(0)
+		statuses = []int{http.StatusOK}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempt := int(requests.Add(1))
+			received = r
+			data, _ := io.ReadAll(r.Body)
+			body = string(data)
+			w.WriteHeader(statuses[min(attempt, len(statuses))-1])
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("delivers the event in binary content mode", func() {
+		sink, err := events.NewSink(server.URL, nil, map[string]string{"Authorization": "Bearer token"}, 1, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sink.Send(context.Background(), event)).To(Succeed())
+
+		Expect(received.Header.Get("ce-specversion")).To(Equal("1.0"))
+		Expect(received.Header.Get("ce-id")).To(Equal(event.ID))
+		Expect(received.Header.Get("ce-source")).To(Equal("/namespaces/bar/directories/foo-directory"))
+		Expect(received.Header.Get("ce-type")).To(Equal("domain.my.openldap.entry.modified"))
+		Expect(received.Header.Get("ce-subject")).To(Equal("uid=jdoe,ou=people,dc=example,dc=com"))
+		Expect(received.Header.Get("ce-time")).To(Equal("2025-01-01T12:00:00Z"))
+		Expect(received.Header.Get("Content-Type")).To(Equal("application/json"))
+		Expect(received.Header.Get("Authorization")).To(Equal("Bearer token"))
+		Expect(body).To(MatchJSON(`{"dn": "uid=jdoe,ou=people,dc=example,dc=com"}`))
+	})
+
+	It("retries failed deliveries", func() {
+		statuses = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusAccepted}
+		sink, err := events.NewSink(server.URL, nil, nil, 3, time.Millisecond)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sink.Send(context.Background(), event)).To(Succeed())
+		Expect(requests.Load()).To(Equal(int32(3)))
+	})
+
+	It("gives up after the maximum number of attempts", func() {
+		statuses = []int{http.StatusInternalServerError}
+		sink, err := events.NewSink(server.URL, nil, nil, 2, time.Millisecond)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sink.Send(context.Background(), event)).To(MatchError(ContainSubstring("after 2 attempts")))
+		Expect(requests.Load()).To(Equal(int32(2)))
+	})
+
+	It("doesn't retry events the webhook rejects", func() {
+		statuses = []int{http.StatusBadRequest}
+		sink, err := events.NewSink(server.URL, nil, nil, 5, time.Millisecond)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sink.Send(context.Background(), event)).To(MatchError(ContainSubstring("400 Bad Request")))
+		Expect(requests.Load()).To(Equal(int32(1)))
+	})
+})
@@ -0,0 +1,132 @@
+package logshipper
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redacted replaces the values of sensitive attributes in audit records
+const redacted = "[REDACTED]"
+
+// DefaultSensitiveAttributes are the attributes holding passwords or password hashes, whose values are always
+// redacted from audit records
+var DefaultSensitiveAttributes = []string{"userPassword", "authPassword", "pwdHistory", "sambaNTPassword", "sambaLMPassword"}
+
+// AuditRecord is a write recorded by the auditlog overlay
+type AuditRecord struct {
+	Time      *time.Time `json:"time,omitempty"`
+	Database  string     `json:"database,omitempty"`
+	Operation string     `json:"operation"`
+	Suffix    string     `json:"suffix,omitempty"`
+	Modifier  string     `json:"modifier,omitempty"`
+	Peer      string     `json:"peer,omitempty"`
+	Conn      *int64     `json:"conn,omitempty"`
+	DN        string     `json:"dn,omitempty"`
+	// Changes are the LDIF lines of the change following the changetype, with folded lines joined
+	Changes []string `json:"changes,omitempty"`
+}
+
+// AuditParser assembles the lines of an audit log into records. Each record is written by the auditlog overlay
+// between a "# <operation> <timestamp> <suffix> <modifier> <peer> conn=<id>" and a "# end <operation>" comment
+type AuditParser struct {
+	// Database is set on each of the records
+	Database string
+	// Sensitive are the attributes whose values are redacted in addition to DefaultSensitiveAttributes
+	Sensitive []string
+
+	record *AuditRecord
+	lines  []string
+}
+
+// Line adds a line of the audit log, returning the record it completes if any
+func (parser *AuditParser) Line(line string) *AuditRecord {
+	if strings.HasPrefix(line, "# end ") {
+		return parser.complete()
+	}
+	if strings.HasPrefix(line, "# ") {
+		parser.record = parseAuditHeader(parser.Database, strings.TrimPrefix(line, "# "))
+		parser.lines = nil
+		return nil
+	}
+	if parser.record == nil || line == "" {
+		return nil
+	}
+	if strings.HasPrefix(line, " ") && len(parser.lines) > 0 {
+		parser.lines[len(parser.lines)-1] += line[1:]
+		return nil
+	}
+	parser.lines = append(parser.lines, line)
+	return nil
+}
+
+func (parser *AuditParser) complete() *AuditRecord {
+	record := parser.record
+	if record == nil {
+		return nil
+	}
+
+	for _, line := range parser.lines {
+		switch {
+		case strings.HasPrefix(line, "dn: ") && record.DN == "":
+			record.DN = strings.TrimPrefix(line, "dn: ")
+		case strings.HasPrefix(line, "changetype: "):
+		default:
+			record.Changes = append(record.Changes, parser.redact(line))
+		}
+	}
+
+	parser.record = nil
+	parser.lines = nil
+	return record
+}
+
+// redact replaces the value of an LDIF line of a sensitive attribute. Lines naming the attribute modified, such
+// as "replace: userPassword", are kept
+func (parser *AuditParser) redact(line string) string {
+	attribute, _, found := strings.Cut(line, ":")
+	if !found {
+		return line
+	}
+	// Attribute options such as ;binary don't change the attribute
+	attribute, _, _ = strings.Cut(attribute, ";")
+	for _, sensitive := range slices.Concat(DefaultSensitiveAttributes, parser.Sensitive) {
+		if strings.EqualFold(attribute, sensitive) {
+			return attribute + ": " + redacted
+		}
+	}
+	return line
+}
+
+func parseAuditHeader(database string, header string) *AuditRecord {
+	record := &AuditRecord{Database: database}
+
+	fields := strings.Fields(header)
+	if len(fields) > 0 {
+		record.Operation = fields[0]
+		fields = fields[1:]
+	}
+	if len(fields) > 0 {
+		if seconds, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+			t := time.Unix(seconds, 0).UTC()
+			record.Time = &t
+			fields = fields[1:]
+		}
+	}
+	if len(fields) > 0 && strings.HasPrefix(fields[len(fields)-1], "conn=") {
+		record.Conn = parseInt(strings.TrimPrefix(fields[len(fields)-1], "conn="))
+		fields = fields[:len(fields)-1]
+	}
+	if len(fields) > 0 && (strings.HasPrefix(fields[len(fields)-1], "IP=") || strings.HasPrefix(fields[len(fields)-1], "PATH=")) {
+		record.Peer = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+	if len(fields) > 0 {
+		record.Suffix = fields[0]
+	}
+	if len(fields) > 1 {
+		record.Modifier = strings.Join(fields[1:], " ")
+	}
+	return record
+}
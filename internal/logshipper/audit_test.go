@@ -0,0 +1,120 @@
+package logshipper_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/paddyoneill/openldap-operator/internal/logshipper"
+)
+
+var _ = Describe("AuditParser", func() {
+	var parser *logshipper.AuditParser
+
+	parse := func(lines ...string) []*logshipper.AuditRecord {
+		records := []*logshipper.AuditRecord{}
+		for _, line := range lines {
+			if record := parser.Line(line); record != nil {
+				records = append(records, record)
+			}
+		}
+		return records
+	}
+
+	BeforeEach(func() {
+		parser = &logshipper.AuditParser{Database: "example"}
+	})
+
+	It("parses a modification", func() {
+		records := parse(
+			"# modify 1738650307 dc=example,dc=com cn=admin,dc=example,dc=com IP=10.0.0.5:51234 conn=1000",
+			"dn: uid=jdoe,ou=people,dc=example,dc=com",
+			"changetype: modify",
+			"replace: mail",
+			"mail: jdoe@example.com",
+			"-",
+			"replace: modifiersName",
+			"modifiersName: cn=admin,dc=example,dc=com",
+			"-",
+			"# end modify 1738650307",
+			"",
+		)
+		Expect(records).To(HaveLen(1))
+		Expect(*records[0]).To(Equal(logshipper.AuditRecord{
+			Time:      ptr.To(time.Unix(1738650307, 0).UTC()),
+			Database:  "example",
+			Operation: "modify",
+			Suffix:    "dc=example,dc=com",
+			Modifier:  "cn=admin,dc=example,dc=com",
+			Peer:      "IP=10.0.0.5:51234",
+			Conn:      ptr.To(int64(1000)),
+			DN:        "uid=jdoe,ou=people,dc=example,dc=com",
+			Changes: []string{
+				"replace: mail",
+				"mail: jdoe@example.com",
+				"-",
+				"replace: modifiersName",
+				"modifiersName: cn=admin,dc=example,dc=com",
+				"-",
+			},
+		}))
+	})
+
+	It("joins folded lines", func() {
+		records := parse(
+			"# add 1738650307 dc=example,dc=com cn=admin,dc=example,dc=com IP=10.0.0.5:51234 conn=1000",
+			"dn: uid=jdoe,ou=people,dc=example,dc=com",
+			"changetype: add",
+			"description: a description which is long enough to be folded by the auditlo",
+			" g overlay",
+			"# end add 1738650307",
+		)
+		Expect(records[0].Changes).To(Equal([]string{
+			"description: a description which is long enough to be folded by the auditlog overlay",
+		}))
+	})
+
+	It("parses writes without a modifier", func() {
+		records := parse(
+			"# delete 1738650307 dc=example,dc=com IP=10.0.0.5:51234 conn=1001",
+			"dn: uid=jdoe,ou=people,dc=example,dc=com",
+			"changetype: delete",
+			"# end delete 1738650307",
+		)
+		Expect(records[0].Suffix).To(Equal("dc=example,dc=com"))
+		Expect(records[0].Modifier).To(BeEmpty())
+		Expect(records[0].Peer).To(Equal("IP=10.0.0.5:51234"))
+	})
+
+	It("ignores lines outside of a record", func() {
+		Expect(parse("dn: uid=jdoe,ou=people,dc=example,dc=com", "# end modify 1738650307")).To(BeEmpty())
+	})
+
+	It("redacts the values of sensitive attributes", func() {
+		parser.Sensitive = []string{"carLicense"}
+		records := parse(
+			"# modify 1738650307 dc=example,dc=com cn=admin,dc=example,dc=com IP=10.0.0.5:51234 conn=1000",
+			"dn: uid=jdoe,ou=people,dc=example,dc=com",
+			"changetype: modify",
+			"replace: userPassword",
+			"userPassword:: e1NTSEF9YWJjZGVm",
+			"-",
+			"add: carLicense",
+			"carlicense;lang-en: ABC 123",
+			"-",
+			"# end modify 1738650307",
+		)
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Changes).To(Equal([]string{
+			"replace: userPassword",
+			"userPassword: [REDACTED]",
+			"-",
+			"add: carLicense",
+			"carlicense: [REDACTED]",
+			"-",
+		}))
+	})
+})
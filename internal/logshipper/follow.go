@@ -0,0 +1,98 @@
+package logshipper
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// pollInterval is how often the log file is checked for new lines once the end has been reached
+const pollInterval = 250 * time.Millisecond
+
+// Follow calls emit with each line written to the file at path until ctx is cancelled. The file is reopened
+// when it is rotated, after the remaining lines of the rotated file have been read. Waits for the file to be
+// created if it doesn't exist yet
+func Follow(ctx context.Context, path string, emit func(line string)) error {
+	var file *os.File
+	var reader *bufio.Reader
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+
+	partial := ""
+	drain := func() error {
+		for {
+			line, err := reader.ReadString('\n')
+			if errors.Is(err, io.EOF) {
+				partial += line
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			emit(partial + line[:len(line)-1])
+			partial = ""
+		}
+	}
+
+	for {
+		if file == nil {
+			opened, err := os.Open(path)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			if opened != nil {
+				file = opened
+				reader = bufio.NewReader(file)
+			}
+		}
+
+		if file != nil {
+			if err := drain(); err != nil {
+				return err
+			}
+
+			if rotated(file, path) {
+				// Lines written before the file was rotated are read before moving on to the new file
+				if err := drain(); err != nil {
+					return err
+				}
+				if partial != "" {
+					emit(partial)
+					partial = ""
+				}
+				file.Close()
+				file = nil
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// rotated returns true if path no longer refers to the open file, or the file has been truncated
+func rotated(file *os.File, path string) bool {
+	current, err := os.Stat(path)
+	if err != nil {
+		return errors.Is(err, os.ErrNotExist)
+	}
+	open, err := file.Stat()
+	if err != nil {
+		return true
+	}
+	if !os.SameFile(open, current) {
+		return true
+	}
+	offset, err := file.Seek(0, io.SeekCurrent)
+	return err == nil && current.Size() < offset
+}
@@ -0,0 +1,72 @@
+package logshipper_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/paddyoneill/openldap-operator/internal/logshipper"
+)
+
+var _ = Describe("Follow", func() {
+	var path string
+	var lines []string
+	var mutex sync.Mutex
+	var cancel context.CancelFunc
+	var done chan error
+
+	received := func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string{}, lines...)
+	}
+
+	appendLines := func(name string, content string) {
+		file, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = file.WriteString(content)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(file.Close()).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "slapd.log")
+		lines = nil
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		done = make(chan error)
+		go func() {
+			done <- logshipper.Follow(ctx, path, func(line string) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				lines = append(lines, line)
+			})
+		}()
+	})
+
+	AfterEach(func() {
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+	})
+
+	It("waits for the file to be created and follows it", func() {
+		appendLines(path, "first\nsec")
+		Eventually(received).Should(Equal([]string{"first"}))
+		appendLines(path, "ond\n")
+		Eventually(received).Should(Equal([]string{"first", "second"}))
+	})
+
+	It("follows the file after rotation", func() {
+		appendLines(path, "first\n")
+		Eventually(received).Should(Equal([]string{"first"}))
+		appendLines(path, "second\n")
+		Expect(os.Rename(path, path+".0")).To(Succeed())
+		appendLines(path, "third\n")
+		Eventually(received).Should(Equal([]string{"first", "second", "third"}))
+	})
+})
@@ -0,0 +1,13 @@
+package logshipper_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestLogshipper(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Logshipper Suite")
+}
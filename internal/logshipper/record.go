@@ -0,0 +1,103 @@
+// Package logshipper parses slapd log lines into structured records
+package logshipper
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	connField     = regexp.MustCompile(`\bconn=(\d+)`)
+	opField       = regexp.MustCompile(`\bop=(\d+) ([A-Z]+(?: RESULT)?)`)
+	dnField       = regexp.MustCompile(`\b(?:dn|base)="((?:[^"\\]|\\.)*)"`)
+	filterField   = regexp.MustCompile(`\bfilter="((?:[^"\\]|\\.)*)"`)
+	resultField   = regexp.MustCompile(`\berr=(\d+)`)
+	elapsedField  = regexp.MustCompile(`\betime=([0-9.]+)`)
+	nentriesField = regexp.MustCompile(`\bnentries=(\d+)`)
+)
+
+// Record is a structured slapd log line
+type Record struct {
+	Time      *time.Time `json:"time,omitempty"`
+	Conn      *int64     `json:"conn,omitempty"`
+	Op        *int64     `json:"op,omitempty"`
+	Operation string     `json:"operation,omitempty"`
+	DN        string     `json:"dn,omitempty"`
+	Filter    string     `json:"filter,omitempty"`
+	Result    *int64     `json:"result,omitempty"`
+	// Elapsed is the time taken by the operation in seconds
+	Elapsed *float64 `json:"elapsed,omitempty"`
+	Entries *int64   `json:"entries,omitempty"`
+	Message string   `json:"message"`
+}
+
+// Parse parses a line of the slapd log, formatted as a hexadecimal timestamp, the thread ID and the message.
+// Fields which aren't present in the message are left unset
+func Parse(line string) Record {
+	record := Record{}
+
+	message := line
+	fields := strings.SplitN(line, " ", 3)
+	if len(fields) == 3 && strings.HasPrefix(fields[1], "0x") {
+		if t, ok := parseTimestamp(fields[0]); ok {
+			record.Time = &t
+			message = fields[2]
+		}
+	}
+	record.Message = message
+
+	if match := connField.FindStringSubmatch(message); match != nil {
+		record.Conn = parseInt(match[1])
+	}
+	if match := opField.FindStringSubmatch(message); match != nil {
+		record.Op = parseInt(match[1])
+		record.Operation = match[2]
+	}
+	if match := dnField.FindStringSubmatch(message); match != nil {
+		record.DN = match[1]
+	}
+	if match := filterField.FindStringSubmatch(message); match != nil {
+		record.Filter = match[1]
+	}
+	if match := resultField.FindStringSubmatch(message); match != nil {
+		record.Result = parseInt(match[1])
+	}
+	if match := elapsedField.FindStringSubmatch(message); match != nil {
+		if elapsed, err := strconv.ParseFloat(match[1], 64); err == nil {
+			record.Elapsed = &elapsed
+		}
+	}
+	if match := nentriesField.FindStringSubmatch(message); match != nil {
+		record.Entries = parseInt(match[1])
+	}
+
+	return record
+}
+
+// parseTimestamp parses the timestamp slapd prefixes log lines with, the seconds and nanoseconds since the
+// epoch in hexadecimal separated by a dot
+func parseTimestamp(value string) (time.Time, bool) {
+	seconds, fraction, found := strings.Cut(value, ".")
+	if !found {
+		return time.Time{}, false
+	}
+	sec, err := strconv.ParseInt(seconds, 16, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	nsec, err := strconv.ParseInt(fraction, 16, 64)
+	if err != nil || nsec >= int64(time.Second) {
+		return time.Time{}, false
+	}
+	return time.Unix(sec, nsec).UTC(), true
+}
+
+func parseInt(value string) *int64 {
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
@@ -0,0 +1,52 @@
+package logshipper_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/paddyoneill/openldap-operator/internal/logshipper"
+)
+
+var _ = Describe("Parse", func() {
+	It("parses the timestamp and thread of the line", func() {
+		record := logshipper.Parse("67a1b2c3.1dcd6500 0x7f3a2b4c5700 conn=1000 fd=12 ACCEPT from IP=10.0.0.5:51234 (IP=0.0.0.0:389)")
+		Expect(*record.Time).To(Equal(time.Unix(0x67a1b2c3, 500000000).UTC()))
+		Expect(record.Message).To(Equal("conn=1000 fd=12 ACCEPT from IP=10.0.0.5:51234 (IP=0.0.0.0:389)"))
+		Expect(record.Conn).To(Equal(ptr.To(int64(1000))))
+		Expect(record.Op).To(BeNil())
+	})
+
+	It("parses binds", func() {
+		record := logshipper.Parse(`67a1b2c3.1dcd6500 0x7f3a2b4c5700 conn=1000 op=0 BIND dn="cn=admin,dc=example,dc=com" method=128`)
+		Expect(record.Op).To(Equal(ptr.To(int64(0))))
+		Expect(record.Operation).To(Equal("BIND"))
+		Expect(record.DN).To(Equal("cn=admin,dc=example,dc=com"))
+	})
+
+	It("parses searches", func() {
+		record := logshipper.Parse(`67a1b2c3.1dcd6500 0x7f3a2b4c5700 conn=1000 op=1 SRCH base="ou=people,dc=example,dc=com" ` +
+			`scope=2 deref=0 filter="(&(objectClass=person)(uid=jdoe))"`)
+		Expect(record.Operation).To(Equal("SRCH"))
+		Expect(record.DN).To(Equal("ou=people,dc=example,dc=com"))
+		Expect(record.Filter).To(Equal("(&(objectClass=person)(uid=jdoe))"))
+	})
+
+	It("parses results", func() {
+		record := logshipper.Parse(`67a1b2c3.1dcd6500 0x7f3a2b4c5700 conn=1000 op=1 SEARCH RESULT tag=101 err=32 ` +
+			`qtime=0.000012 etime=0.001532 nentries=0 text=`)
+		Expect(record.Operation).To(Equal("SEARCH RESULT"))
+		Expect(record.Result).To(Equal(ptr.To(int64(32))))
+		Expect(record.Elapsed).To(Equal(ptr.To(0.001532)))
+		Expect(record.Entries).To(Equal(ptr.To(int64(0))))
+	})
+
+	It("keeps lines without a timestamp as the message", func() {
+		record := logshipper.Parse("slapd starting")
+		Expect(record.Time).To(BeNil())
+		Expect(record.Message).To(Equal("slapd starting"))
+	})
+})
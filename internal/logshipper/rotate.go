@@ -0,0 +1,36 @@
+package logshipper
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Rotate renames the file at path once it reaches maxSize, keeping maxFiles rotated files numbered from 1, the
+// most recent. Writers appending to path create a new file on their next write
+func Rotate(path string, maxSize int64, maxFiles int) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if info.Size() < maxSize {
+		return nil
+	}
+
+	if err := os.Remove(rotatedName(path, maxFiles)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for i := maxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(rotatedName(path, i), rotatedName(path, i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(path, rotatedName(path, 1))
+}
+
+func rotatedName(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
@@ -0,0 +1,46 @@
+package logshipper_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/paddyoneill/openldap-operator/internal/logshipper"
+)
+
+var _ = Describe("Rotate", func() {
+	var path string
+
+	content := func(name string) string {
+		data, err := os.ReadFile(name)
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "audit.ldif")
+	})
+
+	It("doesn't rotate files below the maximum size", func() {
+		Expect(os.WriteFile(path, []byte("small\n"), 0o644)).To(Succeed())
+		Expect(logshipper.Rotate(path, 1024, 2)).To(Succeed())
+		Expect(content(path)).To(Equal("small\n"))
+	})
+
+	It("doesn't fail when the file doesn't exist", func() {
+		Expect(logshipper.Rotate(path, 1024, 2)).To(Succeed())
+	})
+
+	It("keeps the maximum number of rotated files", func() {
+		for _, generation := range []string{"first\n", "second\n", "third\n"} {
+			Expect(os.WriteFile(path, []byte(generation), 0o644)).To(Succeed())
+			Expect(logshipper.Rotate(path, 4, 2)).To(Succeed())
+		}
+		Expect(path).ToNot(BeAnExistingFile())
+		Expect(content(path + ".1")).To(Equal("third\n"))
+		Expect(content(path + ".2")).To(Equal("second\n"))
+		Expect(path + ".3").ToNot(BeAnExistingFile())
+	})
+})
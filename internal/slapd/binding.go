@@ -0,0 +1,80 @@
+package slapd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+// BindingEntry renders the service account entry of a binding. password is the hashed password
+func BindingEntry(binding *v1alpha1.LdapBinding, db *v1alpha1.DatabaseSpec, password string) *Entry {
+	return NewEntry(binding.BindDN(db)).
+		Add("objectClass", "applicationProcess", "simpleSecurityObject").
+		Add("cn", binding.Name).
+		Add("description", fmt.Sprintf("Service account of LdapBinding %s/%s", binding.Namespace, binding.Name)).
+		Add("userPassword", password)
+}
+
+// ValidateBindingScope checks the scope of a binding is a DN at or below the suffix of the database. The scope is
+// written into olcAccess, so quotes and line breaks which could end the clause are rejected as well
+func ValidateBindingScope(binding *v1alpha1.LdapBinding, db *v1alpha1.DatabaseSpec) error {
+	scope := binding.Scope(db)
+	if strings.ContainsAny(scope, "\"\r\n") {
+		return fmt.Errorf("scope %q contains quotes or line breaks", scope)
+	}
+	dn, err := ldap.ParseDN(scope)
+	if err != nil {
+		return fmt.Errorf("invalid scope %q: %w", scope, err)
+	}
+	suffix, err := ldap.ParseDN(db.Suffix)
+	if err != nil {
+		return err
+	}
+	if !suffix.EqualFold(dn) && !suffix.AncestorOfFold(dn) {
+		return fmt.Errorf("scope %s is not within the suffix %s of database %s", scope, db.Suffix, db.Name)
+	}
+	return nil
+}
+
+// BindingSearchBase returns the search base of a client binding as binding for the entries below base: base itself
+// when it lies within the scope of the binding, or the scope when it lies within base. Entries of a base disjoint
+// from the scope can't be read by the binding. The scope must have been checked with ValidateBindingScope
+func BindingSearchBase(binding *v1alpha1.LdapBinding, db *v1alpha1.DatabaseSpec, base string) (string, error) {
+	scope := binding.Scope(db)
+	scopeDN, err := ldap.ParseDN(scope)
+	if err != nil {
+		return "", err
+	}
+	baseDN, err := ldap.ParseDN(base)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case scopeDN.EqualFold(baseDN) || scopeDN.AncestorOfFold(baseDN):
+		return base, nil
+	case baseDN.AncestorOfFold(scopeDN):
+		return scope, nil
+	}
+	return "", fmt.Errorf("%s is outside the scope %s of ldapbinding %s", base, scope, binding.Name)
+}
+
+// BindingAccess renders the access control granting a binding access to its scope. Other clients fall through
+// to the following access controls. The scope must have been checked with ValidateBindingScope
+func BindingAccess(binding *v1alpha1.LdapBinding, db *v1alpha1.DatabaseSpec) string {
+	level := "read"
+	if binding.Spec.Access == v1alpha1.BindingAccessWrite {
+		level = "write"
+	}
+	return fmt.Sprintf(`to dn.subtree="%s" by dn.exact="%s" %s by * break`, binding.Scope(db), binding.BindDN(db), level)
+}
+
+// ServicesEntry renders the organizational unit holding service accounts
+func ServicesEntry(db *v1alpha1.DatabaseSpec) *Entry {
+	return NewEntry(db.ServicesDN()).
+		Add("objectClass", "top", "organizationalUnit").
+		Add("ou", "services")
+}
@@ -0,0 +1,100 @@
+package slapd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("Binding", func() {
+	var db *v1alpha1.DatabaseSpec
+	var binding *v1alpha1.LdapBinding
+
+	BeforeEach(func() {
+		db = &v1alpha1.DatabaseSpec{
+			Name:   "example",
+			Suffix: "dc=example,dc=com",
+		}
+		binding = &v1alpha1.LdapBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app",
+				Namespace: "bar",
+			},
+			Spec: v1alpha1.LdapBindingSpec{
+				DirectoryRef: corev1.LocalObjectReference{Name: "foo-directory"},
+			},
+		}
+	})
+
+	Context("service account entry", func() {
+		It("is created below ou=services", func() {
+			entry := slapd.BindingEntry(binding, db, "{SSHA}hash")
+			Expect(entry.DN).To(Equal("cn=app,ou=services,dc=example,dc=com"))
+			Expect(entry.Get("objectClass")).To(Equal([]string{"applicationProcess", "simpleSecurityObject"}))
+			Expect(entry.Get("userPassword")).To(Equal([]string{"{SSHA}hash"}))
+		})
+	})
+
+	Context("access control", func() {
+		It("grants read access to the suffix by default", func() {
+			Expect(slapd.BindingAccess(binding, db)).To(Equal(
+				`to dn.subtree="dc=example,dc=com" by dn.exact="cn=app,ou=services,dc=example,dc=com" read by * break`))
+		})
+
+		It("grants write access to the configured scope", func() {
+			binding.Spec.Access = v1alpha1.BindingAccessWrite
+			binding.Spec.Scope = "ou=people,dc=example,dc=com"
+			Expect(slapd.BindingAccess(binding, db)).To(Equal(
+				`to dn.subtree="ou=people,dc=example,dc=com" by dn.exact="cn=app,ou=services,dc=example,dc=com" write by * break`))
+		})
+	})
+
+	Context("scope", func() {
+		It("accepts the suffix and subtrees below it", func() {
+			Expect(slapd.ValidateBindingScope(binding, db)).To(Succeed())
+			binding.Spec.Scope = "ou=People,DC=example,dc=com"
+			Expect(slapd.ValidateBindingScope(binding, db)).To(Succeed())
+		})
+
+		It("rejects subtrees outside the suffix", func() {
+			binding.Spec.Scope = "dc=other,dc=com"
+			Expect(slapd.ValidateBindingScope(binding, db)).To(MatchError(ContainSubstring("not within the suffix")))
+			binding.Spec.Scope = "cn=config"
+			Expect(slapd.ValidateBindingScope(binding, db)).NotTo(Succeed())
+		})
+
+		It("rejects values which would inject access clauses", func() {
+			binding.Spec.Scope = `dc=example,dc=com" by * write by * break`
+			Expect(slapd.ValidateBindingScope(binding, db)).NotTo(Succeed())
+			binding.Spec.Scope = "dc=example,dc=com\nolcRootPW: secret"
+			Expect(slapd.ValidateBindingScope(binding, db)).NotTo(Succeed())
+		})
+
+		It("rejects values which aren't DNs", func() {
+			binding.Spec.Scope = "not a dn"
+			Expect(slapd.ValidateBindingScope(binding, db)).NotTo(Succeed())
+		})
+	})
+
+	Context("search base", func() {
+		It("keeps bases within the scope", func() {
+			Expect(slapd.BindingSearchBase(binding, db, db.PeopleDN())).To(Equal("ou=people,dc=example,dc=com"))
+		})
+
+		It("narrows bases to the scope below them", func() {
+			binding.Spec.Scope = "ou=staff,ou=People,dc=example,dc=com"
+			Expect(slapd.BindingSearchBase(binding, db, db.PeopleDN())).To(Equal("ou=staff,ou=People,dc=example,dc=com"))
+		})
+
+		It("rejects bases outside the scope", func() {
+			binding.Spec.Scope = "ou=people,dc=example,dc=com"
+			_, err := slapd.BindingSearchBase(binding, db, db.GroupsDN())
+			Expect(err).To(MatchError("ou=groups,dc=example,dc=com is outside the scope ou=people,dc=example,dc=com of ldapbinding app"))
+		})
+	})
+})
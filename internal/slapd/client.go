@@ -0,0 +1,257 @@
+package slapd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+const (
+	dialTimeout    = 10 * time.Second
+	requestTimeout = 30 * time.Second
+)
+
+// Client is an authenticated connection to a slapd instance
+type Client struct {
+	conn ldap.Client
+}
+
+// Connect dials the slapd instance at uri and binds as bindDN
+func Connect(uri, bindDN, password string) (*Client, error) {
+	conn, err := ldap.DialURL(uri, ldap.DialWithDialer(&net.Dialer{Timeout: dialTimeout}))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(requestTimeout)
+
+	if err := conn.Bind(bindDN, password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &Client{conn: conn}, nil
+}
+
+// ConnectTLS dials the LDAP server at uri and binds as bindDN, verifying the server's certificate against caCert
+// if given. ldap:// connections are upgraded with StartTLS if startTLS is set
+func ConnectTLS(uri, bindDN, password string, caCert []byte, startTLS bool) (*Client, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: parsed.Hostname()}
+	if len(caCert) > 0 {
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in CA certificate")
+		}
+	}
+
+	conn, err := ldap.DialURL(uri, ldap.DialWithDialer(&net.Dialer{Timeout: dialTimeout}), ldap.DialWithTLSConfig(config))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(requestTimeout)
+
+	if startTLS && strings.HasPrefix(uri, "ldap://") {
+		if err := conn.StartTLS(config); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	if err := conn.Bind(bindDN, password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &Client{conn: conn}, nil
+}
+
+func (client *Client) Close() error {
+	return client.conn.Close()
+}
+
+// Get returns the entry with the given DN, or nil if it doesn't exist
+func (client *Client) Get(dn string, attributes ...string) (*ldap.Entry, error) {
+	result, err := client.conn.Search(ldap.NewSearchRequest(
+		dn, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=*)", attributes, nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(result.Entries) == 0 {
+		return nil, nil
+	}
+	return result.Entries[0], nil
+}
+
+// Search returns the entries below base matching filter
+func (client *Client) Search(base string, scope int, filter string, attributes ...string) ([]*ldap.Entry, error) {
+	result, err := client.conn.Search(ldap.NewSearchRequest(
+		base, scope, ldap.NeverDerefAliases, 0, 0, false,
+		filter, attributes, nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return result.Entries, nil
+}
+
+// Add creates entry. Returns false without error if the entry already exists
+func (client *Client) Add(entry *Entry) (bool, error) {
+	request := ldap.NewAddRequest(entry.DN, nil)
+	for _, attribute := range entry.Attributes {
+		if len(attribute.Values) == 0 {
+			continue
+		}
+		request.Attribute(attribute.Name, attribute.Values)
+	}
+
+	if err := client.conn.Add(request); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultEntryAlreadyExists) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to add %s: %w", entry.DN, err)
+	}
+	return true, nil
+}
+
+// Ensure creates entry if it doesn't exist, otherwise replaces the values of attributes which differ.
+// Attributes without values are removed. The objectClass and naming attributes of an existing entry are left untouched
+func (client *Client) Ensure(entry *Entry) error {
+	return client.ensure(entry, false)
+}
+
+// EnsureClasses is like Ensure but also replaces the objectClass values of an existing entry, allowing auxiliary
+// classes to be added and removed along with their attributes
+func (client *Client) EnsureClasses(entry *Entry) error {
+	return client.ensure(entry, true)
+}
+
+func (client *Client) ensure(entry *Entry, classes bool) error {
+	names := make([]string, 0, len(entry.Attributes))
+	for _, attribute := range entry.Attributes {
+		names = append(names, attribute.Name)
+	}
+
+	existing, err := client.Get(entry.DN, names...)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		_, err := client.Add(entry)
+		return err
+	}
+
+	request := ldap.NewModifyRequest(entry.DN, nil)
+	for _, attribute := range entry.Attributes {
+		if (!classes && strings.EqualFold(attribute.Name, "objectClass")) || isNamingAttribute(entry.DN, attribute.Name) {
+			continue
+		}
+		values := existing.GetEqualFoldAttributeValues(attribute.Name)
+		if equalValues(attribute.Name, values, attribute.Values) {
+			continue
+		}
+		if len(attribute.Values) == 0 {
+			request.Delete(attribute.Name, nil)
+			continue
+		}
+		request.Replace(attribute.Name, attribute.Values)
+	}
+	if len(request.Changes) == 0 {
+		return nil
+	}
+
+	if err := client.conn.Modify(request); err != nil {
+		return fmt.Errorf("failed to modify %s: %w", entry.DN, err)
+	}
+	return nil
+}
+
+// Replace sets the values of a single attribute of an existing entry
+func (client *Client) Replace(dn, attribute string, values ...string) error {
+	request := ldap.NewModifyRequest(dn, nil)
+	request.Replace(attribute, values)
+	return client.conn.Modify(request)
+}
+
+// Delete removes the entry with the given DN. Deleting a missing entry is not an error
+func (client *Client) Delete(dn string) error {
+	if err := client.conn.Del(ldap.NewDelRequest(dn, nil)); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete %s: %w", dn, err)
+	}
+	return nil
+}
+
+// DeleteTree removes the entry with the given DN along with the entries below it
+func (client *Client) DeleteTree(dn string) error {
+	children, err := client.Search(dn, ldap.ScopeSingleLevel, "(objectClass=*)", "1.1")
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := client.DeleteTree(child.DN); err != nil {
+			return err
+		}
+	}
+	return client.Delete(dn)
+}
+
+func isNamingAttribute(dn, attribute string) bool {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return false
+	}
+	for _, rdn := range parsed.RDNs[0].Attributes {
+		if strings.EqualFold(rdn.Type, attribute) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalValues compares existing values with desired values in the normalised form slapd stores them in
+func equalValues(attribute string, existing, desired []string) bool {
+	if len(existing) != len(desired) {
+		return false
+	}
+	for i := range existing {
+		if NormalizeValue(attribute, existing[i]) != NormalizeValue(attribute, desired[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func stripOrderingPrefix(value string) string {
+	if !strings.HasPrefix(value, "{") {
+		return value
+	}
+	end := strings.Index(value, "}")
+	if end < 0 {
+		return value
+	}
+	for _, c := range value[1:end] {
+		if (c < '0' || c > '9') && c != '-' {
+			return value
+		}
+	}
+	return value[end+1:]
+}
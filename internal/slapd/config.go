@@ -0,0 +1,119 @@
+package slapd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/utils"
+)
+
+// ErrDatabaseNotFound is returned when no database with the requested suffix exists
+var ErrDatabaseNotFound = errors.New("database not found")
+
+// DatabaseEntry renders the cn=config entry of a data database. rootPW is the hashed password of the root DN,
+// access the complete list of access controls and replica the replication role of the instance, if replicated
+func DatabaseEntry(db *v1alpha1.DatabaseSpec, rootPW string, access []string, replica *Replica) *Entry {
+	entry := NewEntry(fmt.Sprintf("olcDatabase=mdb,%s", ConfigDN))
+	entry.Add("objectClass", "olcDatabaseConfig", "olcMdbConfig")
+	entry.Add("olcDatabase", "mdb")
+	entry.Add("olcSuffix", db.Suffix)
+	entry.Add("olcRootDN", db.RootDN())
+	entry.Add("olcRootPW", rootPW)
+	entry.Add("olcDbDirectory", DatabaseDirectory(db))
+	if len(access) > 0 {
+		entry.Add("olcAccess", access...)
+	}
+	entry.Add("olcDbIndex", IndexDirectives(db, replica)...)
+	if replica != nil {
+		entry.Add("olcLimits", fmt.Sprintf(`dn.exact="%s" time=unlimited size=unlimited`, db.RootDN()))
+	}
+	external := replica.ExternalFor(db)
+	entry.Add("olcSyncrepl", SyncreplDirectives(db, replica)...)
+	switch {
+	case external != nil:
+		entry.Add("olcUpdateRef", external.URI)
+	case replica.Chained():
+		entry.Add("olcUpdateRef", replica.UpdateRef)
+	default:
+		entry.Add("olcUpdateRef")
+	}
+	if external == nil && replica != nil && replica.Provider && len(replica.ProvidersFor(db)) > 0 {
+		entry.Add("olcMultiProvider", "TRUE")
+	} else {
+		entry.Add("olcMultiProvider")
+	}
+	return entry
+}
+
+// TLSEntry renders the TLS attributes of the global config entry
+func TLSEntry() *Entry {
+	return NewEntry(ConfigDN).
+		Add("olcTLSCertificateFile", fmt.Sprintf("%s/tls.crt", TLSDir)).
+		Add("olcTLSCertificateKeyFile", fmt.Sprintf("%s/tls.key", TLSDir)).
+		Add("olcTLSCACertificateFile", fmt.Sprintf("%s/ca.crt", TLSDir))
+}
+
+// FindDatabase returns the DN of the database with the given suffix
+func (client *Client) FindDatabase(suffix string) (string, error) {
+	entries, err := client.Search(ConfigDN, ldap.ScopeSingleLevel,
+		fmt.Sprintf("(&(objectClass=olcDatabaseConfig)(olcSuffix=%s))", ldap.EscapeFilter(suffix)), "olcSuffix")
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", ErrDatabaseNotFound
+	}
+	return entries[0].DN, nil
+}
+
+// EnsureDatabase creates or updates the config of a data database and returns its DN.
+// The root DN password is only rehashed when it no longer matches password
+func (client *Client) EnsureDatabase(db *v1alpha1.DatabaseSpec, password []byte, access []string, replica *Replica) (string, error) {
+	dn, err := client.FindDatabase(db.Suffix)
+	if err != nil && !errors.Is(err, ErrDatabaseNotFound) {
+		return "", err
+	}
+
+	rootPW, err := client.PasswordHash(dn, "olcRootPW", password)
+	if err != nil {
+		return "", err
+	}
+
+	return client.ensureDatabaseEntry(db.Suffix, DatabaseEntry(db, rootPW, access, replica))
+}
+
+// ensureDatabaseEntry creates or updates the database with the given suffix from entry and returns its DN
+func (client *Client) ensureDatabaseEntry(suffix string, entry *Entry) (string, error) {
+	dn, err := client.FindDatabase(suffix)
+	if err != nil && !errors.Is(err, ErrDatabaseNotFound) {
+		return "", err
+	}
+
+	if dn == "" {
+		if _, err := client.Add(entry); err != nil {
+			return "", err
+		}
+		return client.FindDatabase(suffix)
+	}
+
+	entry.DN = dn
+	return dn, client.Ensure(entry)
+}
+
+// PasswordHash returns the existing hash held in attribute of the entry dn if it matches password,
+// otherwise a new hash of password
+func (client *Client) PasswordHash(dn, attribute string, password []byte) (string, error) {
+	if dn != "" {
+		existing, err := client.Get(dn, attribute)
+		if err != nil {
+			return "", err
+		}
+		if existing != nil && utils.VerifyPassword(existing.GetAttributeValue(attribute), password) {
+			return existing.GetAttributeValue(attribute), nil
+		}
+	}
+	return utils.HashPassword(password)
+}
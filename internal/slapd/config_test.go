@@ -0,0 +1,50 @@
+package slapd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("Config", func() {
+	Context("database entry", func() {
+		var entry *slapd.Entry
+
+		BeforeEach(func() {
+			entry = slapd.DatabaseEntry(&v1alpha1.DatabaseSpec{
+				Name:   "example",
+				Suffix: "dc=example,dc=com",
+			}, "{SSHA}hash", []string{"to * by * read"}, nil)
+		})
+
+		It("is created below cn=config", func() {
+			Expect(entry.DN).To(Equal("olcDatabase=mdb,cn=config"))
+		})
+
+		It("sets the database attributes", func() {
+			Expect(entry.Get("objectClass")).To(Equal([]string{"olcDatabaseConfig", "olcMdbConfig"}))
+			Expect(entry.Get("olcSuffix")).To(Equal([]string{"dc=example,dc=com"}))
+			Expect(entry.Get("olcRootDN")).To(Equal([]string{"cn=admin,dc=example,dc=com"}))
+			Expect(entry.Get("olcRootPW")).To(Equal([]string{"{SSHA}hash"}))
+			Expect(entry.Get("olcDbDirectory")).To(Equal([]string{"/var/lib/openldap/example"}))
+			Expect(entry.Get("olcAccess")).To(Equal([]string{"to * by * read"}))
+		})
+
+		It("removes replication attributes when not replicated", func() {
+			Expect(entry.Get("olcSyncrepl")).To(BeEmpty())
+			Expect(entry.Get("olcMultiProvider")).To(BeEmpty())
+		})
+	})
+
+	Context("tls entry", func() {
+		It("sets the certificate files in cn=config", func() {
+			entry := slapd.TLSEntry()
+			Expect(entry.DN).To(Equal("cn=config"))
+			Expect(entry.Get("olcTLSCertificateFile")).To(Equal([]string{"/etc/openldap/tls/tls.crt"}))
+			Expect(entry.Get("olcTLSCertificateKeyFile")).To(Equal([]string{"/etc/openldap/tls/tls.key"}))
+			Expect(entry.Get("olcTLSCACertificateFile")).To(Equal([]string{"/etc/openldap/tls/ca.crt"}))
+		})
+	})
+})
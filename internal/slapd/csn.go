@@ -0,0 +1,118 @@
+package slapd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csnTimeLayout is the layout of the timestamp of a CSN, e.g. 20250101120000.000000Z
+const csnTimeLayout = "20060102150405.000000Z"
+
+// CSN is a change sequence number identifying the last change a server made to a database
+type CSN struct {
+	Time     time.Time
+	Count    int
+	ServerID int
+	Mod      int
+}
+
+// ParseCSN parses a CSN of the form <timestamp>#<count>#<sid>#<mod>
+func ParseCSN(value string) (CSN, error) {
+	parts := strings.Split(value, "#")
+	if len(parts) != 4 {
+		return CSN{}, fmt.Errorf("invalid CSN %s", value)
+	}
+
+	t, err := time.Parse(csnTimeLayout, parts[0])
+	if err != nil {
+		return CSN{}, fmt.Errorf("invalid CSN timestamp %s: %w", value, err)
+	}
+
+	fields := make([]int, 3)
+	for i, part := range parts[1:] {
+		field, err := strconv.ParseInt(part, 16, 32)
+		if err != nil {
+			return CSN{}, fmt.Errorf("invalid CSN %s: %w", value, err)
+		}
+		fields[i] = int(field)
+	}
+
+	return CSN{Time: t, Count: fields[0], ServerID: fields[1], Mod: fields[2]}, nil
+}
+
+// ReplicaLag is the replication state of one instance compared to the others
+type ReplicaLag struct {
+	// Lag is the largest time by which a CSN of the instance trails the newest CSN of the same server
+	Lag time.Duration
+	// InSync is true if the instance holds the newest CSN of every server
+	InSync bool
+}
+
+// CompareCSNs compares the context CSNs of a database held by each instance, keyed by instance name
+func CompareCSNs(contextCSNs map[string][]CSN) map[string]ReplicaLag {
+	newest := map[int]time.Time{}
+	for _, csns := range contextCSNs {
+		for _, csn := range csns {
+			if csn.Time.After(newest[csn.ServerID]) {
+				newest[csn.ServerID] = csn.Time
+			}
+		}
+	}
+
+	result := map[string]ReplicaLag{}
+	for name, csns := range contextCSNs {
+		held := map[int]time.Time{}
+		for _, csn := range csns {
+			held[csn.ServerID] = csn.Time
+		}
+
+		state := ReplicaLag{InSync: true}
+		for sid, t := range newest {
+			current, found := held[sid]
+			if !found {
+				state.InSync = false
+				continue
+			}
+			if current.Before(t) {
+				state.InSync = false
+				if lag := t.Sub(current); lag > state.Lag {
+					state.Lag = lag
+				}
+			}
+		}
+		result[name] = state
+	}
+	return result
+}
+
+// ContextCSNs returns the context CSNs of the database with the given suffix, sorted by server ID
+func (client *Client) ContextCSNs(suffix string) ([]CSN, error) {
+	entry, err := client.Get(suffix, "contextCSN")
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	csns := []CSN{}
+	for _, value := range entry.GetAttributeValues("contextCSN") {
+		csn, err := ParseCSN(value)
+		if err != nil {
+			return nil, err
+		}
+		csns = append(csns, csn)
+	}
+	sort.Slice(csns, func(i, j int) bool {
+		return csns[i].ServerID < csns[j].ServerID
+	})
+	return csns, nil
+}
+
+// String formats the CSN as stored in contextCSN
+func (csn CSN) String() string {
+	return fmt.Sprintf("%s#%06x#%03x#%06x", csn.Time.UTC().Format(csnTimeLayout), csn.Count, csn.ServerID, csn.Mod)
+}
@@ -0,0 +1,74 @@
+package slapd_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("CSN", func() {
+	Context("parsing", func() {
+		It("parses the fields of a CSN", func() {
+			csn, err := slapd.ParseCSN("20250101120000.123456Z#000002#00a#000000")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(csn.Time).To(Equal(time.Date(2025, 1, 1, 12, 0, 0, 123456000, time.UTC)))
+			Expect(csn.Count).To(Equal(2))
+			Expect(csn.ServerID).To(Equal(10))
+			Expect(csn.Mod).To(Equal(0))
+		})
+
+		It("formats a CSN as it was parsed", func() {
+			csn, err := slapd.ParseCSN("20250101120000.123456Z#000002#00a#000000")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(csn.String()).To(Equal("20250101120000.123456Z#000002#00a#000000"))
+		})
+
+		It("rejects malformed CSNs", func() {
+			_, err := slapd.ParseCSN("20250101120000.123456Z#000002")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("comparing", func() {
+		csn := func(value string) slapd.CSN {
+			parsed, err := slapd.ParseCSN(value)
+			Expect(err).ToNot(HaveOccurred())
+			return parsed
+		}
+
+		It("reports instances holding the newest CSNs as in sync", func() {
+			lags := slapd.CompareCSNs(map[string][]slapd.CSN{
+				"a": {csn("20250101120000.000000Z#000000#001#000000")},
+				"b": {csn("20250101120000.000000Z#000000#001#000000")},
+			})
+			Expect(lags["a"]).To(Equal(slapd.ReplicaLag{InSync: true}))
+			Expect(lags["b"]).To(Equal(slapd.ReplicaLag{InSync: true}))
+		})
+
+		It("measures lag per server ID", func() {
+			lags := slapd.CompareCSNs(map[string][]slapd.CSN{
+				"a": {
+					csn("20250101120000.000000Z#000000#001#000000"),
+					csn("20250101115000.000000Z#000000#002#000000"),
+				},
+				"b": {
+					csn("20250101115900.000000Z#000000#001#000000"),
+					csn("20250101115000.000000Z#000000#002#000000"),
+				},
+			})
+			Expect(lags["a"]).To(Equal(slapd.ReplicaLag{InSync: true}))
+			Expect(lags["b"]).To(Equal(slapd.ReplicaLag{Lag: time.Minute, InSync: false}))
+		})
+
+		It("reports instances missing a server's changes as out of sync", func() {
+			lags := slapd.CompareCSNs(map[string][]slapd.CSN{
+				"a": {csn("20250101120000.000000Z#000000#001#000000")},
+				"b": {},
+			})
+			Expect(lags["b"].InSync).To(BeFalse())
+		})
+	})
+})
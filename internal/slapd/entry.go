@@ -0,0 +1,45 @@
+package slapd
+
+import (
+	"strings"
+)
+
+// Entry is an LDAP entry with attributes kept in the order they were added
+type Entry struct {
+	DN         string
+	Attributes []Attribute
+}
+
+// Attribute is a named set of values of an entry
+type Attribute struct {
+	Name   string
+	Values []string
+}
+
+func NewEntry(dn string) *Entry {
+	return &Entry{
+		DN: dn,
+	}
+}
+
+// Add appends values to the named attribute, creating the attribute if it doesn't exist
+func (entry *Entry) Add(name string, values ...string) *Entry {
+	for i := range entry.Attributes {
+		if strings.EqualFold(entry.Attributes[i].Name, name) {
+			entry.Attributes[i].Values = append(entry.Attributes[i].Values, values...)
+			return entry
+		}
+	}
+	entry.Attributes = append(entry.Attributes, Attribute{Name: name, Values: values})
+	return entry
+}
+
+// Get returns the values of the named attribute
+func (entry *Entry) Get(name string) []string {
+	for _, attribute := range entry.Attributes {
+		if strings.EqualFold(attribute.Name, name) {
+			return attribute.Values
+		}
+	}
+	return nil
+}
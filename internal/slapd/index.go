@@ -0,0 +1,152 @@
+package slapd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+const (
+	// MonitorDN is the suffix of the monitor database
+	MonitorDN = "cn=Monitor"
+	// onlineIndexTask is the name of the back-mdb task building indexes added to a running database
+	onlineIndexTask = "mdb_online_index"
+)
+
+// replicationIndexes are maintained on every replicated database for syncrepl to look up entries efficiently
+var replicationIndexes = []string{"objectClass eq", "entryCSN eq", "entryUUID eq"}
+
+// IndexDirectives returns the olcDbIndex values of db. The indexes required by replication are merged with the
+// configured indexes so that each attribute is indexed by a single value
+func IndexDirectives(db *v1alpha1.DatabaseSpec, replica *Replica) []string {
+	attributes := []string{}
+	types := map[string][]string{}
+	add := func(attribute, kind string) {
+		key := strings.ToLower(attribute)
+		if _, found := types[key]; !found {
+			attributes = append(attributes, attribute)
+		}
+		for _, existing := range types[key] {
+			if existing == kind {
+				return
+			}
+		}
+		types[key] = append(types[key], kind)
+	}
+
+	if replica != nil {
+		for _, index := range replicationIndexes {
+			attribute, kind, _ := strings.Cut(index, " ")
+			add(attribute, kind)
+		}
+	}
+	for _, index := range db.Indexes {
+		for _, attribute := range index.Attributes {
+			for _, kind := range index.Types {
+				add(attribute, string(kind))
+			}
+		}
+	}
+
+	directives := []string{}
+	for _, attribute := range attributes {
+		directives = append(directives, fmt.Sprintf("%s %s", attribute, strings.Join(types[strings.ToLower(attribute)], ",")))
+	}
+	return directives
+}
+
+// MonitorDatabaseEntry renders the monitor database, readable only by the config root DN. It reports the
+// progress of online indexing and the number of entries of each database
+func MonitorDatabaseEntry() *Entry {
+	return NewEntry(fmt.Sprintf("olcDatabase=monitor,%s", ConfigDN)).
+		Add("objectClass", "olcDatabaseConfig", "olcMonitorConfig").
+		Add("olcDatabase", "monitor").
+		Add("olcAccess", fmt.Sprintf(`to * by dn.exact="%s" read by * none`, ConfigRootDN))
+}
+
+// EnsureMonitorDatabase creates the monitor database unless it already exists
+func (client *Client) EnsureMonitorDatabase() error {
+	if err := client.EnsureModules("back_monitor"); err != nil {
+		return err
+	}
+
+	existing, err := client.Search(ConfigDN, ldap.ScopeSingleLevel, "(objectClass=olcMonitorConfig)", "olcDatabase")
+	if err != nil {
+		return err
+	}
+	entry := MonitorDatabaseEntry()
+	if len(existing) > 0 {
+		entry.DN = existing[0].DN
+	}
+	return client.Ensure(entry)
+}
+
+// IndexingSuffixes returns the suffixes of the databases building indexes online, read from the thread pool
+// tasks of the monitor database
+func (client *Client) IndexingSuffixes() (map[string]bool, error) {
+	entries, err := client.Search(fmt.Sprintf("cn=Threads,%s", MonitorDN), ldap.ScopeWholeSubtree,
+		"(|(cn=Runqueue)(cn=Tasklist))", "monitoredInfo")
+	if err != nil {
+		return nil, err
+	}
+
+	suffixes := map[string]bool{}
+	for _, entry := range entries {
+		for _, value := range entry.GetAttributeValues("monitoredInfo") {
+			if suffix, found := parseIndexTask(value); found {
+				suffixes[strings.ToLower(suffix)] = true
+			}
+		}
+	}
+	return suffixes, nil
+}
+
+// DatabaseEntries returns the number of entries of the database with the given suffix as counted by back-mdb and
+// reported by the monitor database, without searching the database itself. found is false if the monitor
+// database doesn't report the database
+func (client *Client) DatabaseEntries(suffix string) (entries int64, found bool, err error) {
+	results, err := client.Search(fmt.Sprintf("cn=Databases,%s", MonitorDN), ldap.ScopeSingleLevel,
+		fmt.Sprintf("(namingContexts=%s)", ldap.EscapeFilter(suffix)), "olmMDBEntries")
+	if err != nil {
+		return 0, false, err
+	}
+	for _, entry := range results {
+		value := entry.GetEqualFoldAttributeValue("olmMDBEntries")
+		if value == "" {
+			continue
+		}
+		entries, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid olmMDBEntries %q of %s: %w", value, suffix, err)
+		}
+		return entries, true, nil
+	}
+	return 0, false, nil
+}
+
+// DatabaseIndexes returns the olcDbIndex values of the database with the given suffix
+func (client *Client) DatabaseIndexes(suffix string) ([]string, error) {
+	dn, err := client.FindDatabase(suffix)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := client.Get(dn, "olcDbIndex")
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	return entry.GetAttributeValues("olcDbIndex"), nil
+}
+
+// parseIndexTask returns the suffix of an online indexing task listed by the monitor database, formatted as
+// {0}mdb_online_index(dc=example,dc=com)
+func parseIndexTask(value string) (string, bool) {
+	_, task, found := strings.Cut(value, onlineIndexTask+"(")
+	if !found || !strings.HasSuffix(task, ")") {
+		return "", false
+	}
+	return strings.TrimSuffix(task, ")"), true
+}
@@ -0,0 +1,63 @@
+package slapd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("Index", func() {
+	var db *v1alpha1.DatabaseSpec
+
+	BeforeEach(func() {
+		db = &v1alpha1.DatabaseSpec{
+			Name:   "example",
+			Suffix: "dc=example,dc=com",
+			Indexes: []v1alpha1.IndexSpec{
+				{Attributes: []string{"uid", "mail"}, Types: []v1alpha1.IndexType{"eq", "sub"}},
+				{Attributes: []string{"objectClass"}, Types: []v1alpha1.IndexType{"eq"}},
+				{Attributes: []string{"cn"}, Types: []v1alpha1.IndexType{"pres"}},
+			},
+		}
+	})
+
+	It("renders an index per attribute", func() {
+		Expect(slapd.IndexDirectives(db, nil)).To(Equal([]string{
+			"uid eq,sub",
+			"mail eq,sub",
+			"objectClass eq",
+			"cn pres",
+		}))
+	})
+
+	It("merges the indexes required by replication", func() {
+		db.Indexes[1].Types = []v1alpha1.IndexType{"pres"}
+		Expect(slapd.IndexDirectives(db, &slapd.Replica{})).To(Equal([]string{
+			"objectClass eq,pres",
+			"entryCSN eq",
+			"entryUUID eq",
+			"uid eq,sub",
+			"mail eq,sub",
+			"cn pres",
+		}))
+	})
+
+	It("removes olcDbIndex from databases without indexes", func() {
+		db.Indexes = nil
+		entry := slapd.DatabaseEntry(db, "{SSHA}hash", nil, nil)
+		Expect(entry.Get("olcDbIndex")).To(BeEmpty())
+	})
+
+	It("renders the indexes on the database", func() {
+		entry := slapd.DatabaseEntry(db, "{SSHA}hash", nil, nil)
+		Expect(entry.Get("olcDbIndex")).To(HaveLen(4))
+	})
+
+	It("renders the monitor database", func() {
+		entry := slapd.MonitorDatabaseEntry()
+		Expect(entry.DN).To(Equal("olcDatabase=monitor,cn=config"))
+		Expect(entry.Get("olcAccess")).To(Equal([]string{`to * by dn.exact="cn=config" read by * none`}))
+	})
+})
@@ -0,0 +1,95 @@
+package slapd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// ParseLDIF parses LDIF content records into entries. Only records adding entries are supported
+func ParseLDIF(data []byte) ([]*Entry, error) {
+	var entries []*Entry
+	var entry *Entry
+
+	lines, err := unfoldLines(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for number, line := range lines {
+		if line == "" {
+			entry = nil
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number+1, err)
+		}
+
+		if entry == nil {
+			if strings.EqualFold(name, "version") {
+				continue
+			}
+			if !strings.EqualFold(name, "dn") {
+				return nil, fmt.Errorf("line %d: expected dn, found %s", number+1, name)
+			}
+			entry = NewEntry(value)
+			entries = append(entries, entry)
+			continue
+		}
+
+		if strings.EqualFold(name, "changetype") {
+			if !strings.EqualFold(value, "add") {
+				return nil, fmt.Errorf("line %d: unsupported changetype %s", number+1, value)
+			}
+			continue
+		}
+		entry.Add(name, value)
+	}
+
+	return entries, nil
+}
+
+// unfoldLines joins continuation lines, which start with a single space, onto the preceding line
+func unfoldLines(data []byte) ([]string, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.HasPrefix(line, " ") && len(lines) > 0 && lines[len(lines)-1] != "" {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, scanner.Err()
+}
+
+func parseLine(line string) (string, string, error) {
+	name, value, found := strings.Cut(line, ":")
+	if !found {
+		return "", "", fmt.Errorf("missing separator in %q", line)
+	}
+
+	switch {
+	case strings.HasPrefix(value, ":"):
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[1:]))
+		if err != nil {
+			return "", "", fmt.Errorf("invalid base64 value for %s: %w", name, err)
+		}
+		return name, string(decoded), nil
+	case strings.HasPrefix(value, "<"):
+		return "", "", fmt.Errorf("URL values are not supported for %s", name)
+	}
+
+	return name, strings.TrimLeft(value, " "), nil
+}
@@ -0,0 +1,78 @@
+package slapd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("LDIF", func() {
+	Context("parse valid ldif", func() {
+		var entries []*slapd.Entry
+		var err error
+
+		BeforeEach(func() {
+			entries, err = slapd.ParseLDIF([]byte(`version: 1
+
+# people
+dn: ou=people,dc=example,dc=com
+objectClass: top
+objectClass: organizationalUnit
+ou: people
+
+dn: uid=alice,ou=people,dc=example,dc=com
+changetype: add
+objectClass: inetOrgPerson
+uid: alice
+cn: Alice
+sn:: TGlkZGVsbA==
+description: a long description which is
+  folded over two lines
+`))
+		})
+
+		It("doesn't return an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns each record", func() {
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0].DN).To(Equal("ou=people,dc=example,dc=com"))
+			Expect(entries[1].DN).To(Equal("uid=alice,ou=people,dc=example,dc=com"))
+		})
+
+		It("collects multi-valued attributes", func() {
+			Expect(entries[0].Get("objectClass")).To(Equal([]string{"top", "organizationalUnit"}))
+		})
+
+		It("decodes base64 values", func() {
+			Expect(entries[1].Get("sn")).To(Equal([]string{"Liddell"}))
+		})
+
+		It("unfolds continuation lines", func() {
+			Expect(entries[1].Get("description")).To(Equal([]string{"a long description which is folded over two lines"}))
+		})
+
+		It("doesn't include changetype as an attribute", func() {
+			Expect(entries[1].Get("changetype")).To(BeNil())
+		})
+	})
+
+	Context("parse invalid ldif", func() {
+		It("rejects records not starting with a dn", func() {
+			_, err := slapd.ParseLDIF([]byte("cn: foo\n"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects changetypes other than add", func() {
+			_, err := slapd.ParseLDIF([]byte("dn: cn=foo,dc=example,dc=com\nchangetype: delete\n"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects lines without a separator", func() {
+			_, err := slapd.ParseLDIF([]byte("dn: cn=foo,dc=example,dc=com\nfoo\n"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,46 @@
+package slapd
+
+import (
+	"fmt"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+// logFileRotate keeps the log file read by the log shipping sidecar to three files of at most 10MB, rotating by
+// size only
+const logFileRotate = "3 10 0"
+
+// LogFile returns the path of the log file slapd writes to when logs are shipped by a sidecar
+func LogFile() string {
+	return fmt.Sprintf("%s/slapd.log", LogDir)
+}
+
+// AuditLogFile returns the path of the file the auditlog overlay of a database writes to
+func AuditLogFile(db *v1alpha1.DatabaseSpec) string {
+	return fmt.Sprintf("%s/audit-%s.ldif", LogDir, db.Name)
+}
+
+// LoggingEntry renders the logging attributes of the global config entry. slapd additionally writes its log to
+// a rotated file when logs are shipped as JSON, continuing to log to stderr
+func LoggingEntry(directory *v1alpha1.Directory) *Entry {
+	entry := NewEntry(ConfigDN)
+
+	// The log level of the image is left in place unless logging is configured, after which the configured
+	// levels replace it
+	if directory.Spec.Logging != nil && len(directory.Spec.Logging.Level) > 0 {
+		levels := []string{}
+		for _, level := range directory.Spec.Logging.Level {
+			levels = append(levels, string(level))
+		}
+		entry.Add("olcLogLevel", levels...)
+	}
+
+	if directory.JSONLogging() {
+		entry.Add("olcLogFile", LogFile())
+		entry.Add("olcLogFileRotate", logFileRotate)
+	} else {
+		entry.Add("olcLogFile")
+		entry.Add("olcLogFileRotate")
+	}
+	return entry
+}
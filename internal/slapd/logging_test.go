@@ -0,0 +1,46 @@
+package slapd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("Logging", func() {
+	var directory *v1alpha1.Directory
+
+	BeforeEach(func() {
+		directory = &v1alpha1.Directory{}
+	})
+
+	It("leaves the log level of the image in place without logging configured", func() {
+		entry := slapd.LoggingEntry(directory)
+		Expect(entry.DN).To(Equal("cn=config"))
+		Expect(entry.Attributes).ToNot(ContainElement(HaveField("Name", "olcLogLevel")))
+		Expect(entry.Get("olcLogFile")).To(BeEmpty())
+	})
+
+	It("sets the log level", func() {
+		directory.Spec.Logging = &v1alpha1.LoggingSpec{Level: []v1alpha1.LogLevel{"stats", "sync"}}
+		entry := slapd.LoggingEntry(directory)
+		Expect(entry.Get("olcLogLevel")).To(Equal([]string{"stats", "sync"}))
+		Expect(entry.Get("olcLogFile")).To(BeEmpty())
+	})
+
+	It("writes a rotated log file when logs are shipped as JSON", func() {
+		directory.Spec.Logging = &v1alpha1.LoggingSpec{
+			Level:  []v1alpha1.LogLevel{"stats"},
+			Format: v1alpha1.LogFormatJSON,
+		}
+		entry := slapd.LoggingEntry(directory)
+		Expect(entry.Get("olcLogFile")).To(Equal([]string{"/var/log/slapd/slapd.log"}))
+		Expect(entry.Get("olcLogFileRotate")).To(Equal([]string{"3 10 0"}))
+	})
+
+	It("removes levels which are no longer configured", func() {
+		directory.Spec.Logging = &v1alpha1.LoggingSpec{Level: []v1alpha1.LogLevel{"stats"}}
+		Expect(slapd.LoggingEntry(directory).Get("olcLogLevel")).To(Equal([]string{"stats"}))
+	})
+})
@@ -0,0 +1,180 @@
+package slapd
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	searchLine     = regexp.MustCompile(`conn=(\d+) op=(\d+) SRCH base="([^"]*)" .*filter="(.*)"`)
+	resultLine     = regexp.MustCompile(`conn=(\d+) op=(\d+) SEARCH RESULT .*etime=([0-9.]+)`)
+	unindexedLine  = regexp.MustCompile(`mdb_(equality|substring|presence|approx|inequality)_candidates: \(([^)]+)\) not indexed`)
+	filterItem     = regexp.MustCompile(`\(([A-Za-z][A-Za-z0-9;.-]*)(=|~=|>=|<=)([^()]*)\)`)
+	candidateTypes = map[string]string{
+		"equality":   "eq",
+		"inequality": "eq",
+		"substring":  "sub",
+		"presence":   "pres",
+		"approx":     "approx",
+	}
+)
+
+// IndexFinding counts searches of a database which would benefit from an index of an attribute
+type IndexFinding struct {
+	// Suffix of the database searched
+	Suffix    string
+	Attribute string
+	// Type of index, e.g. eq or sub
+	Type string
+	// Unindexed is the number of times slapd reported the attribute as not indexed
+	Unindexed int64
+	// Slow is the number of searches slower than the threshold filtering on the attribute
+	Slow int64
+}
+
+type logSearch struct {
+	suffix string
+	filter string
+}
+
+// LogsAfter reads log lines prefixed with the timestamps the kubelet adds when asked to, returning the lines
+// logged after the given time without their timestamps along with the time of the last line read. Times are
+// compared at the microsecond precision they are recorded at, as the API only filters logs to the second
+func LogsAfter(r io.Reader, after time.Time) (io.Reader, time.Time, error) {
+	after = after.Truncate(time.Microsecond)
+	last := after
+	lines := &bytes.Buffer{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		timestamp, line, found := strings.Cut(scanner.Text(), " ")
+		logged, err := time.Parse(time.RFC3339Nano, timestamp)
+		if !found || err != nil {
+			continue
+		}
+		logged = logged.Truncate(time.Microsecond)
+		if !logged.After(after) {
+			continue
+		}
+		if logged.After(last) {
+			last = logged
+		}
+		lines.WriteString(line)
+		lines.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, after, err
+	}
+	return lines, last, nil
+}
+
+// AnalyzeLog reads slapd log lines from r, counting the attributes slapd reports as not indexed and the
+// attributes filtered on by searches taking longer than slow. Searches are attributed to the longest of
+// suffixes containing their base. slapd doesn't log which operation an attribute wasn't indexed for, so it is
+// attributed to the last search logged
+func AnalyzeLog(r io.Reader, suffixes []string, slow time.Duration) ([]IndexFinding, error) {
+	findings := map[string]*IndexFinding{}
+	record := func(suffix, attribute, kind string) *IndexFinding {
+		key := strings.ToLower(suffix + "\x00" + attribute + "\x00" + kind)
+		if findings[key] == nil {
+			findings[key] = &IndexFinding{Suffix: suffix, Attribute: attribute, Type: kind}
+		}
+		return findings[key]
+	}
+
+	searches := map[string]logSearch{}
+	last := ""
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if match := searchLine.FindStringSubmatch(line); match != nil {
+			suffix := matchSuffix(match[3], suffixes)
+			searches[match[1]+"/"+match[2]] = logSearch{suffix: suffix, filter: match[4]}
+			last = suffix
+			continue
+		}
+
+		if match := unindexedLine.FindStringSubmatch(line); match != nil {
+			if last != "" {
+				record(last, match[2], candidateTypes[match[1]]).Unindexed++
+			}
+			continue
+		}
+
+		if match := resultLine.FindStringSubmatch(line); match != nil {
+			key := match[1] + "/" + match[2]
+			search, found := searches[key]
+			delete(searches, key)
+			etime, err := strconv.ParseFloat(match[3], 64)
+			if !found || search.suffix == "" || err != nil || time.Duration(etime*float64(time.Second)) < slow {
+				continue
+			}
+			for _, item := range filterAttributes(search.filter) {
+				record(search.suffix, item[0], item[1]).Slow++
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	result := []IndexFinding{}
+	for _, finding := range findings {
+		result = append(result, *finding)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Unindexed+result[i].Slow != result[j].Unindexed+result[j].Slow {
+			return result[i].Unindexed+result[i].Slow > result[j].Unindexed+result[j].Slow
+		}
+		return result[i].Suffix+result[i].Attribute+result[i].Type < result[j].Suffix+result[j].Attribute+result[j].Type
+	})
+	return result, nil
+}
+
+// filterAttributes returns the attributes a search filter tests with the type of index each test uses.
+// objectClass is left out as every database indexes it
+func filterAttributes(filter string) [][2]string {
+	attributes := [][2]string{}
+	seen := map[string]bool{}
+	for _, match := range filterItem.FindAllStringSubmatch(filter, -1) {
+		attribute, operator, value := match[1], match[2], match[3]
+		if strings.EqualFold(attribute, "objectClass") {
+			continue
+		}
+		kind := "eq"
+		switch {
+		case operator == "~=":
+			kind = "approx"
+		case value == "*":
+			kind = "pres"
+		case strings.Contains(value, "*"):
+			kind = "sub"
+		}
+		if key := strings.ToLower(attribute) + kind; !seen[key] {
+			seen[key] = true
+			attributes = append(attributes, [2]string{attribute, kind})
+		}
+	}
+	return attributes
+}
+
+// matchSuffix returns the longest of suffixes containing dn, or "" if none does
+func matchSuffix(dn string, suffixes []string) string {
+	dn = strings.ToLower(dn)
+	match := ""
+	for _, suffix := range suffixes {
+		lower := strings.ToLower(suffix)
+		if (dn == lower || strings.HasSuffix(dn, ","+lower)) && len(suffix) > len(match) {
+			match = suffix
+		}
+	}
+	return match
+}
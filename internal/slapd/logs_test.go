@@ -0,0 +1,63 @@
+package slapd_test
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("Logs", func() {
+	const log = `66a0f0a1.1b2c3d4e 0x7f0000000001 conn=1000 op=1 SRCH base="ou=people,dc=example,dc=com" scope=2 deref=0 filter="(&(objectClass=person)(uid=alice))"
+66a0f0a1.1b2c3d4f 0x7f0000000001 <= mdb_equality_candidates: (uid) not indexed
+66a0f0a1.1b2c3d50 0x7f0000000001 conn=1000 op=1 SEARCH RESULT tag=101 err=0 qtime=0.000010 etime=0.000300 nentries=1 text=
+66a0f0a2.1b2c3d4e 0x7f0000000002 conn=1001 op=1 SRCH base="dc=other,dc=com" scope=2 deref=0 filter="(cn=*smith*)"
+66a0f0a2.1b2c3d4f 0x7f0000000002 <= mdb_substring_candidates: (cn) not indexed
+66a0f0a4.1b2c3d50 0x7f0000000002 conn=1001 op=1 SEARCH RESULT tag=101 err=0 qtime=0.000010 etime=2.500000 nentries=12 text=
+66a0f0a5.1b2c3d4e 0x7f0000000001 conn=1000 op=2 SRCH base="dc=example,dc=com" scope=2 deref=0 filter="(uid=bob)"
+66a0f0a5.1b2c3d4f 0x7f0000000001 <= mdb_equality_candidates: (uid) not indexed
+66a0f0a5.1b2c3d50 0x7f0000000001 conn=1000 op=2 SEARCH RESULT tag=101 err=0 qtime=0.000010 etime=0.000250 nentries=1 text=
+`
+
+	It("counts unindexed and slow searches per database and attribute", func() {
+		findings, err := slapd.AnalyzeLog(strings.NewReader(log), []string{"dc=example,dc=com", "dc=other,dc=com"}, time.Second)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findings).To(Equal([]slapd.IndexFinding{
+			{Suffix: "dc=example,dc=com", Attribute: "uid", Type: "eq", Unindexed: 2},
+			{Suffix: "dc=other,dc=com", Attribute: "cn", Type: "sub", Unindexed: 1, Slow: 1},
+		}))
+	})
+
+	It("ignores searches outside the databases", func() {
+		findings, err := slapd.AnalyzeLog(strings.NewReader(log), []string{"dc=unrelated,dc=com"}, time.Second)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findings).To(BeEmpty())
+	})
+
+	It("reads the lines logged after a time to the microsecond", func() {
+		timestamped := `2025-06-01T10:00:00.000001500Z first
+2025-06-01T10:00:00.000002000Z second
+not a timestamped line
+2025-06-01T10:00:01.250000000Z third
+`
+		after := time.Date(2025, 6, 1, 10, 0, 0, 1900, time.UTC)
+		lines, last, err := slapd.LogsAfter(strings.NewReader(timestamped), after)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(last).To(Equal(time.Date(2025, 6, 1, 10, 0, 1, 250000000, time.UTC)))
+		content, err := io.ReadAll(lines)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("second\nthird\n"))
+
+		By("reading nothing again from the last line read")
+		lines, again, err := slapd.LogsAfter(strings.NewReader(timestamped), last)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(again).To(Equal(last))
+		content, err = io.ReadAll(lines)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(BeEmpty())
+	})
+})
@@ -0,0 +1,136 @@
+package slapd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// ErrGroupNotFound is returned when the group whose members are resolved doesn't exist
+var ErrGroupNotFound = errors.New("group not found")
+
+// Reader looks up entries of a directory. It is implemented by Client
+type Reader interface {
+	Get(dn string, attributes ...string) (*ldap.Entry, error)
+	Search(base string, scope int, filter string, attributes ...string) ([]*ldap.Entry, error)
+}
+
+// groupClasses are the object classes of entries holding members
+var groupClasses = []string{"groupOfNames", "groupOfUniqueNames", "posixGroup"}
+
+// MemberResolver resolves the users of a group, following nested groups
+type MemberResolver struct {
+	// Directory the group is read from
+	Directory Reader
+	// Base of the searches looking up the users listed by uid in posixGroup entries
+	Base string
+	// Attribute of user entries returned for each member
+	UsernameAttribute string
+	// Maximum depth of nested groups followed
+	MaxDepth int
+}
+
+// Members returns the sorted usernames of the users of the group with the given DN. Members of groupOfNames and
+// groupOfUniqueNames entries which are groups themselves are resolved in turn, each group being resolved once so
+// cyclic memberships terminate. Members without the username attribute or which no longer exist are skipped
+func (resolver *MemberResolver) Members(dn string) ([]string, error) {
+	usernames := map[string]struct{}{}
+	visited := map[string]struct{}{}
+	group, err := resolver.Directory.Get(dn, resolver.attributes()...)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil || !isGroup(group) {
+		return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, dn)
+	}
+	if err := resolver.resolve(group, 0, usernames, visited); err != nil {
+		return nil, err
+	}
+
+	members := make([]string, 0, len(usernames))
+	for username := range usernames {
+		members = append(members, username)
+	}
+	slices.Sort(members)
+	return members, nil
+}
+
+func (resolver *MemberResolver) resolve(group *ldap.Entry, depth int, usernames, visited map[string]struct{}) error {
+	visited[strings.ToLower(group.DN)] = struct{}{}
+
+	for _, uid := range group.GetEqualFoldAttributeValues("memberUid") {
+		if err := resolver.addUID(uid, usernames); err != nil {
+			return err
+		}
+	}
+
+	members := slices.Concat(group.GetEqualFoldAttributeValues("member"), group.GetEqualFoldAttributeValues("uniqueMember"))
+	for _, member := range members {
+		// groupOfNames without members hold the empty DN
+		if member == "" {
+			continue
+		}
+		if _, seen := visited[strings.ToLower(member)]; seen {
+			continue
+		}
+		entry, err := resolver.Directory.Get(member, resolver.attributes()...)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			continue
+		}
+		if !isGroup(entry) {
+			if username := entry.GetEqualFoldAttributeValue(resolver.UsernameAttribute); username != "" {
+				usernames[username] = struct{}{}
+			}
+			continue
+		}
+		if depth >= resolver.MaxDepth {
+			return fmt.Errorf("group %s is nested deeper than %d groups", member, resolver.MaxDepth)
+		}
+		if err := resolver.resolve(entry, depth+1, usernames, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addUID adds the user listed by uid in a posixGroup, looking up its username unless it is the uid itself
+func (resolver *MemberResolver) addUID(uid string, usernames map[string]struct{}) error {
+	if strings.EqualFold(resolver.UsernameAttribute, "uid") {
+		usernames[uid] = struct{}{}
+		return nil
+	}
+
+	entries, err := resolver.Directory.Search(resolver.Base, ldap.ScopeWholeSubtree,
+		fmt.Sprintf("(&(objectClass=posixAccount)(uid=%s))", ldap.EscapeFilter(uid)), resolver.UsernameAttribute)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if username := entry.GetEqualFoldAttributeValue(resolver.UsernameAttribute); username != "" {
+			usernames[username] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func (resolver *MemberResolver) attributes() []string {
+	return []string{"objectClass", "member", "uniqueMember", "memberUid", resolver.UsernameAttribute}
+}
+
+// isGroup returns whether the entry holds members
+func isGroup(entry *ldap.Entry) bool {
+	for _, class := range entry.GetEqualFoldAttributeValues("objectClass") {
+		for _, groupClass := range groupClasses {
+			if strings.EqualFold(class, groupClass) {
+				return true
+			}
+		}
+	}
+	return false
+}
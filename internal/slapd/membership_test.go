@@ -0,0 +1,121 @@
+package slapd_test
+
+import (
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+// fakeDirectory serves entries from memory, keyed by lower case DN
+type fakeDirectory map[string]*ldap.Entry
+
+func (directory fakeDirectory) add(dn string, attributes map[string][]string) {
+	directory[strings.ToLower(dn)] = ldap.NewEntry(dn, attributes)
+}
+
+func (directory fakeDirectory) Get(dn string, attributes ...string) (*ldap.Entry, error) {
+	return directory[strings.ToLower(dn)], nil
+}
+
+func (directory fakeDirectory) Search(base string, scope int, filter string, attributes ...string) ([]*ldap.Entry, error) {
+	entries := []*ldap.Entry{}
+	for _, entry := range directory {
+		if strings.Contains(filter, "(uid="+entry.GetAttributeValue("uid")+")") {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+var _ = Describe("Membership", func() {
+	var directory fakeDirectory
+	var resolver *slapd.MemberResolver
+
+	user := func(uid string) {
+		directory.add("uid="+uid+",ou=people,dc=example,dc=com", map[string][]string{
+			"objectClass": {"inetOrgPerson", "posixAccount"},
+			"uid":         {uid},
+			"mail":        {uid + "@example.com"},
+		})
+	}
+	group := func(cn string, members ...string) {
+		directory.add("cn="+cn+",ou=groups,dc=example,dc=com", map[string][]string{
+			"objectClass": {"groupOfNames"},
+			"cn":          {cn},
+			"member":      members,
+		})
+	}
+
+	BeforeEach(func() {
+		directory = fakeDirectory{}
+		resolver = &slapd.MemberResolver{
+			Directory:         directory,
+			Base:              "dc=example,dc=com",
+			UsernameAttribute: "uid",
+			MaxDepth:          10,
+		}
+		user("jdoe")
+		user("asmith")
+		user("bjones")
+	})
+
+	It("returns the users of a group", func() {
+		group("developers", "uid=jdoe,ou=people,dc=example,dc=com", "uid=asmith,ou=people,dc=example,dc=com")
+		Expect(resolver.Members("cn=developers,ou=groups,dc=example,dc=com")).To(Equal([]string{"asmith", "jdoe"}))
+	})
+
+	It("follows nested groups", func() {
+		group("frontend", "uid=jdoe,ou=people,dc=example,dc=com")
+		group("backend", "uid=bjones,ou=people,dc=example,dc=com")
+		group("developers", "cn=frontend,ou=groups,dc=example,dc=com", "cn=backend,ou=groups,dc=example,dc=com",
+			"uid=asmith,ou=people,dc=example,dc=com")
+		Expect(resolver.Members("cn=developers,ou=groups,dc=example,dc=com")).To(Equal([]string{"asmith", "bjones", "jdoe"}))
+	})
+
+	It("terminates on cyclic memberships", func() {
+		group("a", "cn=b,ou=groups,dc=example,dc=com", "uid=jdoe,ou=people,dc=example,dc=com")
+		group("b", "cn=a,ou=groups,dc=example,dc=com", "uid=asmith,ou=people,dc=example,dc=com")
+		Expect(resolver.Members("cn=a,ou=groups,dc=example,dc=com")).To(Equal([]string{"asmith", "jdoe"}))
+	})
+
+	It("fails on groups nested deeper than the maximum depth", func() {
+		resolver.MaxDepth = 1
+		group("c", "uid=jdoe,ou=people,dc=example,dc=com")
+		group("b", "cn=c,ou=groups,dc=example,dc=com")
+		group("a", "cn=b,ou=groups,dc=example,dc=com")
+		_, err := resolver.Members("cn=a,ou=groups,dc=example,dc=com")
+		Expect(err).To(MatchError(ContainSubstring("nested deeper than 1")))
+	})
+
+	It("skips the empty DN and members which no longer exist", func() {
+		group("empty", "", "uid=gone,ou=people,dc=example,dc=com")
+		Expect(resolver.Members("cn=empty,ou=groups,dc=example,dc=com")).To(BeEmpty())
+	})
+
+	It("returns the members of posix groups", func() {
+		directory.add("cn=admins,ou=groups,dc=example,dc=com", map[string][]string{
+			"objectClass": {"posixGroup"},
+			"cn":          {"admins"},
+			"memberUid":   {"jdoe", "bjones"},
+		})
+		Expect(resolver.Members("cn=admins,ou=groups,dc=example,dc=com")).To(Equal([]string{"bjones", "jdoe"}))
+
+		By("looking up the username of posix members by uid")
+		resolver.UsernameAttribute = "mail"
+		Expect(resolver.Members("cn=admins,ou=groups,dc=example,dc=com")).To(
+			Equal([]string{"bjones@example.com", "jdoe@example.com"}))
+	})
+
+	It("fails if the group doesn't exist", func() {
+		_, err := resolver.Members("cn=missing,ou=groups,dc=example,dc=com")
+		Expect(err).To(MatchError(slapd.ErrGroupNotFound))
+
+		By("treating entries without members as missing groups")
+		_, err = resolver.Members("uid=jdoe,ou=people,dc=example,dc=com")
+		Expect(err).To(MatchError(slapd.ErrGroupNotFound))
+	})
+})
@@ -0,0 +1,127 @@
+package slapd
+
+import (
+	"slices"
+	"strings"
+)
+
+// syncreplDefaults are the syncrepl options slapd adds with their default values when it unparses olcSyncrepl.
+// The operator never sets them, so they are ignored when comparing directives
+var syncreplDefaults = []string{
+	"attrs", "exattrs", "filter", "interval", "keepalive", "network-timeout", "schemachecking", "scope",
+	"sizelimit", "timelimit", "tls_crlcheck", "tls_protocol_min", "lazycommit", "strictrefresh",
+}
+
+// NormalizeValue returns the form of a config value used to compare the value written by the operator with the
+// value read back from cn=config. slapd rewrites olcSyncrepl, olcAccess and olcLimits into its own canonical form,
+// reordering options, adding defaults, quoting values and normalising DNs, so these values are reduced to their
+// significant options. Other values are returned as they are, without the {n} ordering prefix
+func NormalizeValue(attribute, value string) string {
+	value = stripOrderingPrefix(value)
+	switch strings.ToLower(attribute) {
+	case "olcsyncrepl":
+		return normalizeSyncrepl(value)
+	case "olcaccess":
+		return strings.Join(normalizeTokens(splitTokens(value)), " ")
+	case "olclimits":
+		return normalizeLimits(value)
+	}
+	return value
+}
+
+func normalizeSyncrepl(value string) string {
+	options := []string{}
+	for _, token := range splitTokens(value) {
+		key, option, _ := strings.Cut(token, "=")
+		key = strings.ToLower(key)
+		if slices.Contains(syncreplDefaults, key) {
+			continue
+		}
+		option = unquote(option)
+		// starttls=no is how slapd unparses a connection without StartTLS
+		if key == "starttls" && option == "no" {
+			continue
+		}
+		if key != "credentials" {
+			option = strings.ToLower(option)
+		}
+		options = append(options, key+"="+option)
+	}
+	slices.Sort(options)
+	return strings.Join(options, " ")
+}
+
+func normalizeLimits(value string) string {
+	tokens := normalizeTokens(splitTokens(value))
+	if len(tokens) == 0 {
+		return ""
+	}
+	limits := []string{}
+	for _, token := range tokens[1:] {
+		key, limit, _ := strings.Cut(token, "=")
+		if key == "time" || key == "size" {
+			limits = append(limits, key+".soft="+limit, key+".hard="+limit)
+			continue
+		}
+		limits = append(limits, token)
+	}
+	slices.Sort(limits)
+	return strings.Join(append(tokens[:1], limits...), " ")
+}
+
+// normalizeTokens lower cases tokens, unquotes their values and maps dn.exact to its canonical name dn.base
+func normalizeTokens(tokens []string) []string {
+	normalized := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		key, value, found := strings.Cut(token, "=")
+		key = strings.ToLower(key)
+		if key == "dn.exact" {
+			key = "dn.base"
+		}
+		if !found {
+			normalized = append(normalized, key)
+			continue
+		}
+		value = strings.ToLower(unquote(value))
+		if strings.HasPrefix(key, "dn.") {
+			value = strings.ReplaceAll(value, ", ", ",")
+		}
+		normalized = append(normalized, key+"="+value)
+	}
+	return normalized
+}
+
+// splitTokens splits a config value on whitespace outside double quotes
+func splitTokens(value string) []string {
+	tokens := []string{}
+	var token strings.Builder
+	quoted, escaped := false, false
+	for _, c := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case !quoted && (c == ' ' || c == '\t' || c == '\n'):
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+			continue
+		}
+		token.WriteRune(c)
+	}
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+	return tokens
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,52 @@
+package slapd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("Normalize", func() {
+	It("matches olcSyncrepl values unparsed by slapd", func() {
+		written := `rid=002 provider=ldap://openldap-1.openldap-headless.bar.svc:3389 bindmethod=simple ` +
+			`binddn="cn=admin,dc=example,dc=com" credentials="S3cret" searchbase="dc=example,dc=com" ` +
+			`type=refreshAndPersist retry="5 5 300 +" timeout=1`
+		stored := `{0}rid=002 provider=ldap://openldap-1.openldap-headless.bar.svc:3389 bindmethod=simple ` +
+			`timeout=1 network-timeout=0 binddn="cn=admin,dc=example,dc=com" credentials="S3cret" ` +
+			`keepalive=0:0:0 starttls=no filter="(objectclass=*)" searchbase="dc=example,dc=com" scope=sub ` +
+			`attrs="*,+" schemachecking=off type=refreshAndPersist retry="5 5 300 +"`
+		Expect(slapd.NormalizeValue("olcSyncrepl", stored)).To(Equal(slapd.NormalizeValue("olcSyncrepl", written)))
+	})
+
+	It("detects changed syncrepl options", func() {
+		written := `rid=001 provider=ldap://a bindmethod=simple credentials="new" type=refreshAndPersist`
+		stored := `{0}rid=001 provider=ldap://a bindmethod=simple credentials="old" type=refreshAndPersist`
+		Expect(slapd.NormalizeValue("olcSyncrepl", stored)).NotTo(Equal(slapd.NormalizeValue("olcSyncrepl", written)))
+
+		By("treating TLS options as significant")
+		stored = `{0}rid=001 provider=ldap://a bindmethod=simple credentials="new" type=refreshAndPersist starttls=critical`
+		Expect(slapd.NormalizeValue("olcSyncrepl", stored)).NotTo(Equal(slapd.NormalizeValue("olcSyncrepl", written)))
+	})
+
+	It("matches olcAccess values rewritten by slapd", func() {
+		written := `to dn.subtree="ou=People, dc=example,dc=com" by dn.exact="cn=app,ou=services,dc=example,dc=com" read by * break`
+		stored := `{0}to dn.subtree="ou=people,dc=example,dc=com"  by dn.base="cn=app,ou=services,dc=example,dc=com" read  by * break`
+		Expect(slapd.NormalizeValue("olcAccess", stored)).To(Equal(slapd.NormalizeValue("olcAccess", written)))
+
+		By("keeping the order of clauses")
+		stored = `{0}to dn.subtree="ou=people,dc=example,dc=com" by * break by dn.base="cn=app,ou=services,dc=example,dc=com" read`
+		Expect(slapd.NormalizeValue("olcAccess", stored)).NotTo(Equal(slapd.NormalizeValue("olcAccess", written)))
+	})
+
+	It("matches olcLimits values rewritten by slapd", func() {
+		written := `dn.exact="cn=admin,dc=example,dc=com" time=unlimited size=unlimited`
+		stored := `{0}dn.exact="cn=admin,dc=example,dc=com" time.soft=unlimited time.hard=unlimited ` +
+			`size.soft=unlimited size.hard=unlimited`
+		Expect(slapd.NormalizeValue("olcLimits", stored)).To(Equal(slapd.NormalizeValue("olcLimits", written)))
+	})
+
+	It("only strips the ordering prefix of other attributes", func() {
+		Expect(slapd.NormalizeValue("olcSuffix", "{1}dc=Example,dc=com")).To(Equal("dc=Example,dc=com"))
+	})
+})
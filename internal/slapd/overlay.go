@@ -0,0 +1,211 @@
+package slapd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+// managedOverlays are the overlays configured by the operator. Other overlays of a database are left untouched
+var managedOverlays = []string{"memberof", "refint", "auditlog", "syncprov", "accesslog", "chain"}
+
+// overlayModules maps overlays to the module providing them where the names differ
+var overlayModules = map[string]string{
+	"chain": "back_ldap",
+}
+
+// OverlayEntries renders the cn=config entries of the overlays enabled on the database dn. Replication
+// overlays are included when replica is a provider
+func OverlayEntries(dn string, db *v1alpha1.DatabaseSpec, replica *Replica) []*Entry {
+	entries := []*Entry{}
+
+	if db.Overlays != nil && db.Overlays.MemberOf != nil {
+		memberOf := db.Overlays.MemberOf
+		entries = append(entries, overlayEntry(dn, "memberof", "olcMemberOf").
+			Add("olcMemberOfGroupOC", memberOf.GroupObjectClass).
+			Add("olcMemberOfMemberAD", memberOf.MemberAttribute).
+			Add("olcMemberOfMemberOfAD", memberOf.MemberOfAttribute).
+			Add("olcMemberOfDangling", string(memberOf.Dangling)).
+			Add("olcMemberOfRefInt", boolValue(memberOf.RefInt)))
+	}
+
+	if db.Overlays != nil && db.Overlays.RefInt != nil {
+		refInt := db.Overlays.RefInt
+		entries = append(entries, overlayEntry(dn, "refint", "olcRefintConfig").
+			Add("olcRefintAttribute", refInt.Attributes...).
+			Add("olcRefintModifiersName", refInt.ModifiersName))
+	}
+
+	if db.Audit != nil {
+		entries = append(entries, overlayEntry(dn, "auditlog", "olcAuditlogConfig").
+			Add("olcAuditlogFile", AuditLogFile(db)))
+	}
+
+	if replica != nil && replica.Provider {
+		entries = append(entries, syncProvOverlayEntry(dn))
+	}
+
+	if replica.Delta() && replica.Provider {
+		entries = append(entries, accessLogOverlayEntry(dn, db, replica.AccessLog))
+	}
+
+	if replica.Chained() {
+		entries = append(entries, overlayEntry(dn, "chain", "olcChainConfig").
+			Add("olcChainReturnError", "TRUE"))
+	}
+
+	return entries
+}
+
+// OverlayModules returns the modules which need to be loaded for the overlays of a database
+func OverlayModules(db *v1alpha1.DatabaseSpec, replica *Replica) []string {
+	modules := []string{}
+	for _, entry := range OverlayEntries("", db, replica) {
+		overlay := entry.Get("olcOverlay")[0]
+		if module, found := overlayModules[overlay]; found {
+			overlay = module
+		}
+		modules = append(modules, overlay)
+	}
+	return modules
+}
+
+// WithSyncProv adds the syncprov overlay to the overlays of the database dn if it isn't already included, for
+// databases followed over syncrepl by a directory watch
+func WithSyncProv(dn string, overlays []*Entry) []*Entry {
+	for _, overlay := range overlays {
+		if overlay.Get("olcOverlay")[0] == "syncprov" {
+			return overlays
+		}
+	}
+	return append(overlays, syncProvOverlayEntry(dn))
+}
+
+func syncProvOverlayEntry(dn string) *Entry {
+	return overlayEntry(dn, "syncprov", "olcSyncProvConfig").
+		Add("olcSpCheckpoint", "100 10").
+		Add("olcSpSessionLog", "100")
+}
+
+func accessLogOverlayEntry(dn string, db *v1alpha1.DatabaseSpec, accessLog *v1alpha1.AccessLogSpec) *Entry {
+	if accessLog == nil {
+		accessLog = &v1alpha1.AccessLogSpec{}
+	}
+
+	operations := accessLog.Operations
+	if len(operations) == 0 {
+		operations = []string{"writes"}
+	}
+
+	entry := overlayEntry(dn, "accesslog", "olcAccessLogConfig").
+		Add("olcAccessLogDB", db.AccessLogSuffix()).
+		Add("olcAccessLogOps", operations...).
+		Add("olcAccessLogSuccess", boolValue(accessLog.SuccessOnly == nil || *accessLog.SuccessOnly))
+	if accessLog.PurgeAge != "" && accessLog.PurgeInterval != "" {
+		entry.Add("olcAccessLogPurge", fmt.Sprintf("%s %s", accessLog.PurgeAge, accessLog.PurgeInterval))
+	}
+	return entry
+}
+
+func overlayEntry(dn, overlay, objectClass string) *Entry {
+	return NewEntry(fmt.Sprintf("olcOverlay=%s,%s", overlay, dn)).
+		Add("objectClass", "olcOverlayConfig", objectClass).
+		Add("olcOverlay", overlay)
+}
+
+func boolValue(value bool) string {
+	if value {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// EnsureOverlays creates or updates the overlays of the database dn and removes managed overlays which
+// aren't in overlays
+func (client *Client) EnsureOverlays(dn string, overlays []*Entry) error {
+	entries, err := client.Search(dn, ldap.ScopeSingleLevel, "(objectClass=olcOverlayConfig)", "olcOverlay")
+	if err != nil {
+		return err
+	}
+
+	existing := map[string]string{}
+	for _, entry := range entries {
+		existing[strings.ToLower(stripOrderingPrefix(entry.GetAttributeValue("olcOverlay")))] = entry.DN
+	}
+
+	desired := map[string]bool{}
+	for _, overlay := range overlays {
+		name := overlay.Get("olcOverlay")[0]
+		desired[name] = true
+
+		if existingDN, found := existing[name]; found {
+			overlay.DN = existingDN
+		}
+		if err := client.Ensure(overlay); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range managedOverlays {
+		if existingDN, found := existing[name]; found && !desired[name] {
+			if err := client.DeleteTree(existingDN); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// EnsureModules loads modules which aren't already loaded by a module list of cn=config
+func (client *Client) EnsureModules(modules ...string) error {
+	entries, err := client.Search(ConfigDN, ldap.ScopeSingleLevel, "(objectClass=olcModuleList)", "olcModuleLoad")
+	if err != nil {
+		return err
+	}
+
+	loaded := map[string]bool{}
+	for _, entry := range entries {
+		for _, module := range entry.GetAttributeValues("olcModuleLoad") {
+			loaded[moduleName(module)] = true
+		}
+	}
+
+	missing := []string{}
+	for _, module := range modules {
+		if !loaded[moduleName(module)] {
+			missing = append(missing, module)
+			loaded[moduleName(module)] = true
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if len(entries) == 0 {
+		_, err := client.Add(NewEntry(fmt.Sprintf("cn=module,%s", ConfigDN)).
+			Add("objectClass", "olcModuleList").
+			Add("cn", "module").
+			Add("olcModuleLoad", missing...))
+		return err
+	}
+
+	request := ldap.NewModifyRequest(entries[0].DN, nil)
+	request.Add("olcModuleLoad", missing)
+	if err := client.conn.Modify(request); err != nil {
+		return fmt.Errorf("failed to load modules %s: %w", strings.Join(missing, ", "), err)
+	}
+	return nil
+}
+
+// moduleName normalises an olcModuleLoad value, e.g. {0}/usr/lib/openldap/memberof.la, to the module name
+func moduleName(value string) string {
+	name := stripOrderingPrefix(value)
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.ToLower(strings.TrimSuffix(name, ".la"))
+}
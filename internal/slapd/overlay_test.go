@@ -0,0 +1,109 @@
+package slapd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("Overlay", func() {
+	const dn = "olcDatabase={1}mdb,cn=config"
+	var db *v1alpha1.DatabaseSpec
+
+	BeforeEach(func() {
+		db = &v1alpha1.DatabaseSpec{
+			Name:   "example",
+			Suffix: "dc=example,dc=com",
+		}
+	})
+
+	Context("without overlays", func() {
+		It("doesn't render any entries", func() {
+			Expect(slapd.OverlayEntries(dn, db, nil)).To(BeEmpty())
+		})
+
+		It("doesn't require any modules", func() {
+			Expect(slapd.OverlayModules(db, nil)).To(BeEmpty())
+		})
+	})
+
+	Context("with memberof and refint", func() {
+		var entries []*slapd.Entry
+
+		BeforeEach(func() {
+			db.Overlays = &v1alpha1.DatabaseOverlaysSpec{
+				MemberOf: &v1alpha1.MemberOfOverlaySpec{
+					GroupObjectClass:  "groupOfNames",
+					MemberAttribute:   "member",
+					MemberOfAttribute: "memberOf",
+					Dangling:          v1alpha1.MemberOfDanglingDrop,
+					RefInt:            true,
+				},
+				RefInt: &v1alpha1.RefIntOverlaySpec{
+					Attributes:    []string{"member", "manager"},
+					ModifiersName: "cn=Referential Integrity Overlay",
+				},
+			}
+			entries = slapd.OverlayEntries(dn, db, nil)
+		})
+
+		It("renders an entry per overlay below the database", func() {
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0].DN).To(Equal("olcOverlay=memberof,olcDatabase={1}mdb,cn=config"))
+			Expect(entries[1].DN).To(Equal("olcOverlay=refint,olcDatabase={1}mdb,cn=config"))
+		})
+
+		It("sets the memberof attributes", func() {
+			Expect(entries[0].Get("objectClass")).To(Equal([]string{"olcOverlayConfig", "olcMemberOf"}))
+			Expect(entries[0].Get("olcMemberOfGroupOC")).To(Equal([]string{"groupOfNames"}))
+			Expect(entries[0].Get("olcMemberOfMemberAD")).To(Equal([]string{"member"}))
+			Expect(entries[0].Get("olcMemberOfMemberOfAD")).To(Equal([]string{"memberOf"}))
+			Expect(entries[0].Get("olcMemberOfDangling")).To(Equal([]string{"drop"}))
+			Expect(entries[0].Get("olcMemberOfRefInt")).To(Equal([]string{"TRUE"}))
+		})
+
+		It("sets the refint attributes", func() {
+			Expect(entries[1].Get("objectClass")).To(Equal([]string{"olcOverlayConfig", "olcRefintConfig"}))
+			Expect(entries[1].Get("olcRefintAttribute")).To(Equal([]string{"member", "manager"}))
+			Expect(entries[1].Get("olcRefintModifiersName")).To(Equal([]string{"cn=Referential Integrity Overlay"}))
+		})
+
+		It("requires the overlay modules", func() {
+			Expect(slapd.OverlayModules(db, nil)).To(Equal([]string{"memberof", "refint"}))
+		})
+	})
+
+	Context("with an audit log", func() {
+		BeforeEach(func() {
+			db.Audit = &v1alpha1.AuditSpec{}
+		})
+
+		It("writes the audit log to the log directory", func() {
+			entries := slapd.OverlayEntries(dn, db, nil)
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].DN).To(Equal("olcOverlay=auditlog,olcDatabase={1}mdb,cn=config"))
+			Expect(entries[0].Get("objectClass")).To(Equal([]string{"olcOverlayConfig", "olcAuditlogConfig"}))
+			Expect(entries[0].Get("olcAuditlogFile")).To(Equal([]string{"/var/log/slapd/audit-example.ldif"}))
+		})
+
+		It("requires the auditlog module", func() {
+			Expect(slapd.OverlayModules(db, nil)).To(Equal([]string{"auditlog"}))
+		})
+	})
+
+	Context("with a directory watch", func() {
+		It("adds the syncprov overlay", func() {
+			entries := slapd.WithSyncProv(dn, slapd.OverlayEntries(dn, db, nil))
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].DN).To(Equal("olcOverlay=syncprov,olcDatabase={1}mdb,cn=config"))
+		})
+
+		It("doesn't add syncprov to providers twice", func() {
+			replica := &slapd.Replica{ServerID: 1, Provider: true, Mode: v1alpha1.ReplicationModeSyncrepl}
+			entries := slapd.WithSyncProv(dn, slapd.OverlayEntries(dn, db, replica))
+			Expect(entries).To(HaveLen(1))
+		})
+	})
+})
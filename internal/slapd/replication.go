@@ -0,0 +1,287 @@
+package slapd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+// accessLogFilter selects the successful writes a delta-syncrepl consumer replays
+const accessLogFilter = "(&(objectClass=auditWriteObject)(reqResult=0))"
+
+// Replica is the replication role of a single slapd instance of a directory
+type Replica struct {
+	// ServerID uniquely identifies the instance among the instances of the directory
+	ServerID int
+	// Provider is true if the instance accepts writes and serves changes to other instances
+	Provider bool
+	// Providers are the instances this instance replicates from
+	Providers []Peer
+	// Mode is the syncrepl variant used to replicate
+	Mode v1alpha1.ReplicationMode
+	// AccessLog configures the accesslog of providers in delta-syncrepl mode
+	AccessLog *v1alpha1.AccessLogSpec
+	// Credentials is the password consumers bind to providers with as the root DN of the database
+	Credentials string
+	// UpdateRef is the URI consumers refer writes to. Writes are chained to it on behalf of the client
+	UpdateRef string
+	// External is a provider outside the cluster which providers replicate a database from
+	External *External
+}
+
+// External is an LDAP server outside the cluster a database replicates from
+type External struct {
+	// Suffix of the database replicating from the server
+	Suffix      string
+	URI         string
+	SearchBase  string
+	BindDN      string
+	Credentials string
+	Retry       string
+	StartTLS    bool
+	// CACertFile is the path of the CA certificate the server's certificate is verified against
+	CACertFile string
+}
+
+// ExternalFor returns the external provider db replicates from, or nil if it only replicates within the cluster.
+// Only providers replicate from the external provider, consumers replicate from the providers
+func (replica *Replica) ExternalFor(db *v1alpha1.DatabaseSpec) *External {
+	if replica == nil || !replica.Provider || replica.External == nil || replica.External.Suffix != db.Suffix {
+		return nil
+	}
+	return replica.External
+}
+
+// Peer is another provider of the directory, either an instance in the cluster or a remote peer
+type Peer struct {
+	ServerID int
+	URI      string
+	// Suffix restricts replication with a remote peer to a single database. Instances in the cluster
+	// replicate every database
+	Suffix string
+	// BindDN and Credentials authenticate to a remote peer. Instances in the cluster are bound to as the
+	// root DN of the database with the credentials of the replica
+	BindDN      string
+	Credentials string
+	StartTLS    bool
+	// CACertFile is the path of the CA certificate the peer's certificate is verified against
+	CACertFile string
+}
+
+// ProvidersFor returns the providers db replicates from
+func (replica *Replica) ProvidersFor(db *v1alpha1.DatabaseSpec) []Peer {
+	if replica == nil {
+		return nil
+	}
+	providers := []Peer{}
+	for _, provider := range replica.Providers {
+		if provider.Suffix == "" || provider.Suffix == db.Suffix {
+			providers = append(providers, provider)
+		}
+	}
+	return providers
+}
+
+// Delta returns true if the replica replicates using delta-syncrepl
+func (replica *Replica) Delta() bool {
+	return replica != nil && replica.Mode == v1alpha1.ReplicationModeDeltaSyncrepl
+}
+
+// ServerIDEntry renders the server ID of the global config entry
+func ServerIDEntry(replica *Replica) *Entry {
+	return NewEntry(ConfigDN).Add("olcServerID", fmt.Sprint(replica.ServerID))
+}
+
+// SyncreplDirectives returns the olcSyncrepl values replicating db from the providers of replica
+func SyncreplDirectives(db *v1alpha1.DatabaseSpec, replica *Replica) []string {
+	if replica == nil {
+		return nil
+	}
+
+	if external := replica.ExternalFor(db); external != nil {
+		return []string{externalDirective(external)}
+	}
+
+	directives := []string{}
+	for _, provider := range replica.ProvidersFor(db) {
+		bindDN, credentials := db.RootDN(), replica.Credentials
+		if provider.BindDN != "" {
+			bindDN, credentials = provider.BindDN, provider.Credentials
+		}
+		directive := []string{
+			fmt.Sprintf("rid=%03d", provider.ServerID),
+			fmt.Sprintf("provider=%s", provider.URI),
+			"bindmethod=simple",
+			fmt.Sprintf(`binddn="%s"`, bindDN),
+			fmt.Sprintf(`credentials="%s"`, credentials),
+			fmt.Sprintf(`searchbase="%s"`, db.Suffix),
+			"type=refreshAndPersist",
+			`retry="5 5 300 +"`,
+			"timeout=1",
+		}
+		if replica.Delta() {
+			directive = append(directive,
+				fmt.Sprintf(`logbase="%s"`, db.AccessLogSuffix()),
+				fmt.Sprintf(`logfilter="%s"`, accessLogFilter),
+				"syncdata=accesslog",
+			)
+		}
+		directive = append(directive, tlsOptions(provider.StartTLS, provider.CACertFile)...)
+		directives = append(directives, strings.Join(directive, " "))
+	}
+	return directives
+}
+
+// tlsOptions returns the syncrepl options securing the connection to a provider
+func tlsOptions(startTLS bool, caCertFile string) []string {
+	options := []string{}
+	if startTLS {
+		options = append(options, "starttls=critical")
+	}
+	if caCertFile != "" {
+		options = append(options, fmt.Sprintf("tls_cacert=%s", caCertFile), "tls_reqcert=demand")
+	}
+	return options
+}
+
+func externalDirective(external *External) string {
+	searchBase := external.SearchBase
+	if searchBase == "" {
+		searchBase = external.Suffix
+	}
+	retry := external.Retry
+	if retry == "" {
+		retry = "60 +"
+	}
+
+	directive := []string{
+		"rid=000",
+		fmt.Sprintf("provider=%s", external.URI),
+		"bindmethod=simple",
+		fmt.Sprintf(`binddn="%s"`, external.BindDN),
+		fmt.Sprintf(`credentials="%s"`, external.Credentials),
+		fmt.Sprintf(`searchbase="%s"`, searchBase),
+		"type=refreshAndPersist",
+		fmt.Sprintf(`retry="%s"`, retry),
+		"timeout=1",
+	}
+	directive = append(directive, tlsOptions(external.StartTLS, external.CACertFile)...)
+	return strings.Join(directive, " ")
+}
+
+// ValidateDirectiveValue checks a value quoted into an olcSyncrepl directive. Quotes, backslashes and line breaks
+// would end the value early and let the rest of it add syncrepl keywords of its own. The value isn't included in
+// the error as it may be a password
+func ValidateDirectiveValue(name, value string) error {
+	if strings.ContainsAny(value, "\"\\\r\n") {
+		return fmt.Errorf("%s contains quotes, backslashes or line breaks", name)
+	}
+	return nil
+}
+
+// ValidateProvider checks the URI, bind DN and search base of a provider outside the cluster before they are
+// written into olcSyncrepl. searchBase is optional
+func ValidateProvider(uri, bindDN, searchBase string) error {
+	parsed, err := url.Parse(uri)
+	if err != nil || strings.ContainsAny(uri, " \t\"\\\r\n") || (parsed.Scheme != "ldap" && parsed.Scheme != "ldaps") ||
+		parsed.Host == "" {
+		return fmt.Errorf("invalid provider URI %q, expected ldap://host[:port] or ldaps://host[:port]", uri)
+	}
+
+	if err := validateDN("bind DN", bindDN); err != nil {
+		return err
+	}
+	if searchBase != "" {
+		return validateDN("search base", searchBase)
+	}
+	return nil
+}
+
+// validateDN checks a DN quoted into an olcSyncrepl directive
+func validateDN(name, dn string) error {
+	if err := ValidateDirectiveValue(name, dn); err != nil {
+		return err
+	}
+	if _, err := ldap.ParseDN(dn); err != nil {
+		return fmt.Errorf("invalid %s %q: %w", name, dn, err)
+	}
+	return nil
+}
+
+// Chained returns true if writes to the replica are chained to a provider
+func (replica *Replica) Chained() bool {
+	return replica != nil && !replica.Provider && replica.UpdateRef != ""
+}
+
+// ChainDatabaseEntry renders the back-ldap database of the chain overlay with the DN dn. Writes are forwarded
+// to the update referral bound as the root DN of db, asserting the identity of the client through proxy
+// authorization
+func ChainDatabaseEntry(dn string, db *v1alpha1.DatabaseSpec, replica *Replica) *Entry {
+	return NewEntry(fmt.Sprintf("olcDatabase=ldap,%s", dn)).
+		Add("objectClass", "olcLDAPConfig", "olcChainDatabase").
+		Add("olcDatabase", "ldap").
+		Add("olcDbURI", replica.UpdateRef).
+		Add("olcDbIDAssertBind", strings.Join([]string{
+			"bindmethod=simple",
+			fmt.Sprintf(`binddn="%s"`, db.RootDN()),
+			fmt.Sprintf(`credentials="%s"`, replica.Credentials),
+			"mode=self",
+		}, " "))
+}
+
+// EnsureChainDatabase creates or updates the back-ldap database of the chain overlay of the database dn.
+// Does nothing if the database has no chain overlay
+func (client *Client) EnsureChainDatabase(dn string, db *v1alpha1.DatabaseSpec, replica *Replica) error {
+	overlays, err := client.Search(dn, ldap.ScopeSingleLevel, "(objectClass=olcChainConfig)", "olcOverlay")
+	if err != nil {
+		return err
+	}
+	if len(overlays) == 0 || !replica.Chained() {
+		return nil
+	}
+
+	entry := ChainDatabaseEntry(overlays[0].DN, db, replica)
+	existing, err := client.Search(overlays[0].DN, ldap.ScopeSingleLevel, "(objectClass=olcChainDatabase)", "olcDatabase")
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		entry.DN = existing[0].DN
+	}
+	return client.Ensure(entry)
+}
+
+// AccessLogDatabaseEntry renders the cn=config entry of the accesslog database recording changes to db.
+// The root DN of db is granted read access so that consumers can replay the log
+func AccessLogDatabaseEntry(db *v1alpha1.DatabaseSpec, accessLog *v1alpha1.AccessLogSpec) *Entry {
+	entry := NewEntry(fmt.Sprintf("olcDatabase=mdb,%s", ConfigDN))
+	entry.Add("objectClass", "olcDatabaseConfig", "olcMdbConfig")
+	entry.Add("olcDatabase", "mdb")
+	entry.Add("olcSuffix", db.AccessLogSuffix())
+	entry.Add("olcDbDirectory", AccessLogDirectory(db))
+	entry.Add("olcDbIndex", "default eq", "entryCSN,objectClass,reqEnd,reqResult,reqStart,reqDN")
+	if accessLog != nil && accessLog.MaxSize != nil {
+		entry.Add("olcDbMaxSize", fmt.Sprint(accessLog.MaxSize.Value()))
+	}
+	entry.Add("olcAccess", fmt.Sprintf(`to * by dn.exact="%s" read by * none`, db.RootDN()))
+	entry.Add("olcLimits", fmt.Sprintf(`dn.exact="%s" time=unlimited size=unlimited`, db.RootDN()))
+	return entry
+}
+
+// AccessLogOverlayEntries renders the overlays of the accesslog database with the DN dn
+func AccessLogOverlayEntries(dn string) []*Entry {
+	return []*Entry{
+		overlayEntry(dn, "syncprov", "olcSyncProvConfig").
+			Add("olcSpNoPresent", "TRUE").
+			Add("olcSpReloadHint", "TRUE"),
+	}
+}
+
+// EnsureAccessLogDatabase creates or updates the accesslog database of db and returns its DN
+func (client *Client) EnsureAccessLogDatabase(db *v1alpha1.DatabaseSpec, accessLog *v1alpha1.AccessLogSpec) (string, error) {
+	return client.ensureDatabaseEntry(db.AccessLogSuffix(), AccessLogDatabaseEntry(db, accessLog))
+}
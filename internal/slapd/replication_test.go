@@ -0,0 +1,259 @@
+package slapd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("Replication", func() {
+	const dn = "olcDatabase={1}mdb,cn=config"
+	var db *v1alpha1.DatabaseSpec
+	var replica *slapd.Replica
+
+	BeforeEach(func() {
+		db = &v1alpha1.DatabaseSpec{
+			Name:   "example",
+			Suffix: "dc=example,dc=com",
+		}
+		replica = &slapd.Replica{
+			ServerID: 1,
+			Provider: true,
+			Providers: []slapd.Peer{
+				{ServerID: 2, URI: "ldap://10.0.0.2:3389"},
+				{ServerID: 3, URI: "ldap://10.0.0.3:3389"},
+			},
+			Mode:        v1alpha1.ReplicationModeSyncrepl,
+			Credentials: "secret",
+		}
+	})
+
+	Context("multi-provider syncrepl", func() {
+		It("replicates from each of the other providers", func() {
+			Expect(slapd.SyncreplDirectives(db, replica)).To(Equal([]string{
+				`rid=002 provider=ldap://10.0.0.2:3389 bindmethod=simple binddn="cn=admin,dc=example,dc=com" ` +
+					`credentials="secret" searchbase="dc=example,dc=com" type=refreshAndPersist retry="5 5 300 +" timeout=1`,
+				`rid=003 provider=ldap://10.0.0.3:3389 bindmethod=simple binddn="cn=admin,dc=example,dc=com" ` +
+					`credentials="secret" searchbase="dc=example,dc=com" type=refreshAndPersist retry="5 5 300 +" timeout=1`,
+			}))
+		})
+
+		It("enables multi-provider on the database", func() {
+			entry := slapd.DatabaseEntry(db, "{SSHA}hash", nil, replica)
+			Expect(entry.Get("olcSyncrepl")).To(HaveLen(2))
+			Expect(entry.Get("olcMultiProvider")).To(Equal([]string{"TRUE"}))
+			Expect(entry.Get("olcDbIndex")).To(ContainElements("entryCSN eq", "entryUUID eq"))
+		})
+
+		It("sets the server ID", func() {
+			Expect(slapd.ServerIDEntry(replica).Get("olcServerID")).To(Equal([]string{"1"}))
+		})
+
+		It("adds the syncprov overlay", func() {
+			entries := slapd.OverlayEntries(dn, db, replica)
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].DN).To(Equal("olcOverlay=syncprov,olcDatabase={1}mdb,cn=config"))
+		})
+	})
+
+	Context("provider/consumer syncrepl", func() {
+		It("doesn't enable multi-provider on the provider", func() {
+			replica.Providers = nil
+			entry := slapd.DatabaseEntry(db, "{SSHA}hash", nil, replica)
+			Expect(entry.Get("olcSyncrepl")).To(BeEmpty())
+			Expect(entry.Get("olcMultiProvider")).To(BeEmpty())
+		})
+
+		It("doesn't add the syncprov overlay on consumers", func() {
+			replica.Provider = false
+			Expect(slapd.OverlayEntries(dn, db, replica)).To(BeEmpty())
+		})
+	})
+
+	Context("consumer with update referral", func() {
+		BeforeEach(func() {
+			replica.Provider = false
+			replica.Providers = replica.Providers[:1]
+			replica.UpdateRef = "ldap://foo-directory-write.bar.svc:389"
+		})
+
+		It("refers writes to the providers", func() {
+			entry := slapd.DatabaseEntry(db, "{SSHA}hash", nil, replica)
+			Expect(entry.Get("olcUpdateRef")).To(Equal([]string{"ldap://foo-directory-write.bar.svc:389"}))
+		})
+
+		It("adds the chain overlay", func() {
+			entries := slapd.OverlayEntries(dn, db, replica)
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].DN).To(Equal("olcOverlay=chain,olcDatabase={1}mdb,cn=config"))
+			Expect(entries[0].Get("olcChainReturnError")).To(Equal([]string{"TRUE"}))
+		})
+
+		It("loads chain from the back_ldap module", func() {
+			Expect(slapd.OverlayModules(db, replica)).To(Equal([]string{"back_ldap"}))
+		})
+
+		It("chains writes with proxy authorization", func() {
+			entry := slapd.ChainDatabaseEntry("olcOverlay={0}chain,"+dn, db, replica)
+			Expect(entry.DN).To(Equal("olcDatabase=ldap,olcOverlay={0}chain,olcDatabase={1}mdb,cn=config"))
+			Expect(entry.Get("objectClass")).To(Equal([]string{"olcLDAPConfig", "olcChainDatabase"}))
+			Expect(entry.Get("olcDbURI")).To(Equal([]string{"ldap://foo-directory-write.bar.svc:389"}))
+			Expect(entry.Get("olcDbIDAssertBind")).To(Equal([]string{
+				`bindmethod=simple binddn="cn=admin,dc=example,dc=com" credentials="secret" mode=self`,
+			}))
+		})
+	})
+
+	Context("delta-syncrepl", func() {
+		BeforeEach(func() {
+			replica.Mode = v1alpha1.ReplicationModeDeltaSyncrepl
+			replica.AccessLog = &v1alpha1.AccessLogSpec{
+				Operations:    []string{"writes"},
+				SuccessOnly:   ptr.To(true),
+				PurgeAge:      "07+00:00",
+				PurgeInterval: "01+00:00",
+				MaxSize:       ptr.To(resource.MustParse("1Gi")),
+			}
+		})
+
+		It("replays the accesslog of the providers", func() {
+			directives := slapd.SyncreplDirectives(db, replica)
+			Expect(directives[0]).To(HaveSuffix(`logbase="cn=accesslog-example" ` +
+				`logfilter="(&(objectClass=auditWriteObject)(reqResult=0))" syncdata=accesslog`))
+		})
+
+		It("renders the accesslog database", func() {
+			entry := slapd.AccessLogDatabaseEntry(db, replica.AccessLog)
+			Expect(entry.Get("olcSuffix")).To(Equal([]string{"cn=accesslog-example"}))
+			Expect(entry.Get("olcDbDirectory")).To(Equal([]string{"/var/lib/openldap/example.accesslog"}))
+			Expect(entry.Get("olcDbMaxSize")).To(Equal([]string{"1073741824"}))
+			Expect(entry.Get("olcAccess")).To(Equal([]string{`to * by dn.exact="cn=admin,dc=example,dc=com" read by * none`}))
+		})
+
+		It("adds the accesslog overlay after syncprov", func() {
+			entries := slapd.OverlayEntries(dn, db, replica)
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[1].Get("olcOverlay")).To(Equal([]string{"accesslog"}))
+			Expect(entries[1].Get("olcAccessLogDB")).To(Equal([]string{"cn=accesslog-example"}))
+			Expect(entries[1].Get("olcAccessLogOps")).To(Equal([]string{"writes"}))
+			Expect(entries[1].Get("olcAccessLogSuccess")).To(Equal([]string{"TRUE"}))
+			Expect(entries[1].Get("olcAccessLogPurge")).To(Equal([]string{"07+00:00 01+00:00"}))
+		})
+
+		It("requires the replication modules", func() {
+			Expect(slapd.OverlayModules(db, replica)).To(Equal([]string{"syncprov", "accesslog"}))
+		})
+	})
+
+	Context("external provider", func() {
+		BeforeEach(func() {
+			replica.Providers = nil
+			replica.External = &slapd.External{
+				Suffix:      "dc=example,dc=com",
+				URI:         "ldap://ldap.example.com",
+				BindDN:      "cn=replicator,dc=example,dc=com",
+				Credentials: "external",
+				Retry:       "60 +",
+				StartTLS:    true,
+				CACertFile:  "/etc/openldap/external-ca/ca.crt",
+			}
+		})
+
+		It("replicates from the external provider", func() {
+			Expect(slapd.SyncreplDirectives(db, replica)).To(Equal([]string{
+				`rid=000 provider=ldap://ldap.example.com bindmethod=simple binddn="cn=replicator,dc=example,dc=com" ` +
+					`credentials="external" searchbase="dc=example,dc=com" type=refreshAndPersist retry="60 +" timeout=1 ` +
+					`starttls=critical tls_cacert=/etc/openldap/external-ca/ca.crt tls_reqcert=demand`,
+			}))
+		})
+
+		It("refers writes to the external provider", func() {
+			entry := slapd.DatabaseEntry(db, "{SSHA}hash", nil, replica)
+			Expect(entry.Get("olcUpdateRef")).To(Equal([]string{"ldap://ldap.example.com"}))
+			Expect(entry.Get("olcMultiProvider")).To(BeEmpty())
+		})
+
+		It("doesn't replicate other databases from the external provider", func() {
+			other := &v1alpha1.DatabaseSpec{Name: "other", Suffix: "dc=other,dc=com"}
+			Expect(slapd.SyncreplDirectives(other, replica)).To(BeEmpty())
+		})
+
+		It("leaves consumers replicating from the providers", func() {
+			replica.Provider = false
+			replica.Providers = []slapd.Peer{{ServerID: 1, URI: "ldap://10.0.0.1:3389"}}
+			directives := slapd.SyncreplDirectives(db, replica)
+			Expect(directives).To(HaveLen(1))
+			Expect(directives[0]).To(HavePrefix("rid=001 provider=ldap://10.0.0.1:3389"))
+		})
+	})
+
+	Context("remote peers", func() {
+		BeforeEach(func() {
+			replica.Providers = append(replica.Providers, slapd.Peer{
+				ServerID:    101,
+				URI:         "ldaps://ldap.eu-west.example.com",
+				Suffix:      "dc=example,dc=com",
+				BindDN:      "cn=replicator,dc=example,dc=com",
+				Credentials: "remote",
+				CACertFile:  "/etc/openldap/peer-ca/eu-west/ca.crt",
+			})
+		})
+
+		It("replicates from the remote peer alongside the providers in the cluster", func() {
+			directives := slapd.SyncreplDirectives(db, replica)
+			Expect(directives).To(HaveLen(3))
+			Expect(directives[2]).To(Equal(`rid=101 provider=ldaps://ldap.eu-west.example.com bindmethod=simple ` +
+				`binddn="cn=replicator,dc=example,dc=com" credentials="remote" searchbase="dc=example,dc=com" ` +
+				`type=refreshAndPersist retry="5 5 300 +" timeout=1 tls_cacert=/etc/openldap/peer-ca/eu-west/ca.crt tls_reqcert=demand`))
+		})
+
+		It("only replicates the database of the remote peer", func() {
+			other := &v1alpha1.DatabaseSpec{Name: "other", Suffix: "dc=other,dc=com"}
+			Expect(slapd.SyncreplDirectives(other, replica)).To(HaveLen(2))
+		})
+
+		It("enables multi-provider on a single instance", func() {
+			replica.Providers = replica.Providers[2:]
+			entry := slapd.DatabaseEntry(db, "{SSHA}hash", nil, replica)
+			Expect(entry.Get("olcSyncrepl")).To(HaveLen(1))
+			Expect(entry.Get("olcMultiProvider")).To(Equal([]string{"TRUE"}))
+
+			other := &v1alpha1.DatabaseSpec{Name: "other", Suffix: "dc=other,dc=com"}
+			Expect(slapd.DatabaseEntry(other, "{SSHA}hash", nil, replica).Get("olcMultiProvider")).To(BeEmpty())
+		})
+	})
+
+	Context("provider settings", func() {
+		It("accepts ldap and ldaps URIs with DNs", func() {
+			Expect(slapd.ValidateProvider("ldaps://ldap.example.com:636", "cn=replicator,dc=example,dc=com", "ou=people,dc=example,dc=com")).To(Succeed())
+			Expect(slapd.ValidateProvider("ldap://ldap.example.com", "cn=Smith,dc=example,dc=com", "")).To(Succeed())
+		})
+
+		It("rejects URIs which would add syncrepl keywords", func() {
+			Expect(slapd.ValidateProvider("ldaps://ldap.example.com bindmethod=sasl", "cn=replicator,dc=example,dc=com", "")).
+				To(MatchError(ContainSubstring("invalid provider URI")))
+			Expect(slapd.ValidateProvider("ldaps://", "cn=replicator,dc=example,dc=com", "")).NotTo(Succeed())
+			Expect(slapd.ValidateProvider("http://ldap.example.com", "cn=replicator,dc=example,dc=com", "")).NotTo(Succeed())
+		})
+
+		It("rejects DNs which would end the quoted value", func() {
+			Expect(slapd.ValidateProvider("ldaps://ldap.example.com", `cn=replicator,dc=example,dc=com" searchbase="dc=other`, "")).
+				To(MatchError("bind DN contains quotes, backslashes or line breaks"))
+			Expect(slapd.ValidateProvider("ldaps://ldap.example.com", "cn=replicator,dc=example,dc=com", "dc=example,dc=com\nfoo")).
+				To(MatchError("search base contains quotes, backslashes or line breaks"))
+			Expect(slapd.ValidateProvider("ldaps://ldap.example.com", "not a dn", "")).To(MatchError(ContainSubstring("invalid bind DN")))
+		})
+
+		It("rejects passwords with quotes, backslashes or line breaks", func() {
+			Expect(slapd.ValidateDirectiveValue("password", "s3cret")).To(Succeed())
+			for _, password := range []string{`s3"cret`, `s3\\cret`, "s3cret\n", "s3\rcret"} {
+				Expect(slapd.ValidateDirectiveValue("password", password)).To(MatchError("password contains quotes, backslashes or line breaks"))
+			}
+		})
+	})
+})
@@ -0,0 +1,100 @@
+package slapd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+// SchemaDN is the parent of the schema entries of cn=config
+const SchemaDN = "cn=schema," + ConfigDN
+
+// customSchemas holds the definitions of the schemas loaded by the operator
+var customSchemas = map[v1alpha1.Schema]struct {
+	attributeTypes []string
+	objectClasses  []string
+}{
+	v1alpha1.SchemaOpenSSHLPK: {
+		attributeTypes: []string{
+			"( 1.3.6.1.4.1.24552.500.1.1.1.13 NAME 'sshPublicKey' DESC 'MANDATORY: OpenSSH Public key' " +
+				"EQUALITY octetStringMatch SYNTAX 1.3.6.1.4.1.1466.115.121.1.40 )",
+		},
+		objectClasses: []string{
+			"( 1.3.6.1.4.1.24552.500.1.1.2.0 NAME 'ldapPublicKey' SUP top AUXILIARY " +
+				"DESC 'MANDATORY: OpenSSH LPK objectclass' MAY ( sshPublicKey $ uid ) )",
+		},
+	},
+	v1alpha1.SchemaSudo: {
+		attributeTypes: []string{
+			"( 1.3.6.1.4.1.15953.9.1.1 NAME 'sudoUser' DESC 'User(s) who may  run sudo' EQUALITY caseExactIA5Match " +
+				"SUBSTR caseExactIA5SubstringsMatch SYNTAX 1.3.6.1.4.1.1466.115.121.1.26 )",
+			"( 1.3.6.1.4.1.15953.9.1.2 NAME 'sudoHost' DESC 'Host(s) who may run sudo' EQUALITY caseExactIA5Match " +
+				"SUBSTR caseExactIA5SubstringsMatch SYNTAX 1.3.6.1.4.1.1466.115.121.1.26 )",
+			"( 1.3.6.1.4.1.15953.9.1.3 NAME 'sudoCommand' DESC 'Command(s) to be executed by sudo' " +
+				"EQUALITY caseExactIA5Match SYNTAX 1.3.6.1.4.1.1466.115.121.1.26 )",
+			"( 1.3.6.1.4.1.15953.9.1.4 NAME 'sudoRunAs' DESC 'User(s) impersonated by sudo (deprecated)' " +
+				"EQUALITY caseExactIA5Match SYNTAX 1.3.6.1.4.1.1466.115.121.1.26 )",
+			"( 1.3.6.1.4.1.15953.9.1.5 NAME 'sudoOption' DESC 'Options(s) followed by sudo' " +
+				"EQUALITY caseExactIA5Match SYNTAX 1.3.6.1.4.1.1466.115.121.1.26 )",
+			"( 1.3.6.1.4.1.15953.9.1.6 NAME 'sudoRunAsUser' DESC 'User(s) impersonated by sudo' " +
+				"EQUALITY caseExactIA5Match SYNTAX 1.3.6.1.4.1.1466.115.121.1.26 )",
+			"( 1.3.6.1.4.1.15953.9.1.7 NAME 'sudoRunAsGroup' DESC 'Group(s) impersonated by sudo' " +
+				"EQUALITY caseExactIA5Match SYNTAX 1.3.6.1.4.1.1466.115.121.1.26 )",
+			"( 1.3.6.1.4.1.15953.9.1.8 NAME 'sudoNotBefore' DESC 'Start of time interval for which the entry is valid' " +
+				"EQUALITY generalizedTimeMatch ORDERING generalizedTimeOrderingMatch SYNTAX 1.3.6.1.4.1.1466.115.121.1.24 )",
+			"( 1.3.6.1.4.1.15953.9.1.9 NAME 'sudoNotAfter' DESC 'End of time interval for which the entry is valid' " +
+				"EQUALITY generalizedTimeMatch ORDERING generalizedTimeOrderingMatch SYNTAX 1.3.6.1.4.1.1466.115.121.1.24 )",
+			"( 1.3.6.1.4.1.15953.9.1.10 NAME 'sudoOrder' DESC 'an integer to order the sudoRole entries' " +
+				"EQUALITY integerMatch ORDERING integerOrderingMatch SYNTAX 1.3.6.1.4.1.1466.115.121.1.27 )",
+		},
+		objectClasses: []string{
+			"( 1.3.6.1.4.1.15953.9.2.1 NAME 'sudoRole' SUP top STRUCTURAL DESC 'Sudoer Entries' MUST ( cn ) " +
+				"MAY ( sudoUser $ sudoHost $ sudoCommand $ sudoRunAs $ sudoRunAsUser $ sudoRunAsGroup $ sudoOption $ " +
+				"sudoOrder $ sudoNotBefore $ sudoNotAfter $ description ) )",
+		},
+	},
+}
+
+// SchemaEntry renders the cn=schema,cn=config entry of a schema loaded by the operator
+func SchemaEntry(schema v1alpha1.Schema) (*Entry, error) {
+	definition, found := customSchemas[schema]
+	if !found {
+		return nil, fmt.Errorf("schema %s is not a custom schema", schema)
+	}
+	return NewEntry(fmt.Sprintf("cn=%s,%s", schema, SchemaDN)).
+		Add("objectClass", "olcSchemaConfig").
+		Add("cn", schema.String()).
+		Add("olcAttributeTypes", definition.attributeTypes...).
+		Add("olcObjectClasses", definition.objectClasses...), nil
+}
+
+// EnsureSchemas loads the schemas which aren't already loaded. Loaded schemas are left untouched, as slapd doesn't
+// support removing schemas and modifying them could invalidate existing entries
+func (client *Client) EnsureSchemas(schemas v1alpha1.SchemaList) error {
+	entries, err := client.Search(SchemaDN, ldap.ScopeSingleLevel, "(objectClass=olcSchemaConfig)", "cn")
+	if err != nil {
+		return err
+	}
+
+	loaded := map[string]bool{}
+	for _, entry := range entries {
+		loaded[strings.ToLower(stripOrderingPrefix(entry.GetAttributeValue("cn")))] = true
+	}
+
+	for _, schema := range schemas {
+		if loaded[strings.ToLower(schema.String())] {
+			continue
+		}
+		entry, err := SchemaEntry(schema)
+		if err != nil {
+			return err
+		}
+		if _, err := client.Add(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package slapd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("Schema", func() {
+	It("renders the openssh-lpk schema", func() {
+		entry, err := slapd.SchemaEntry(v1alpha1.SchemaOpenSSHLPK)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entry.DN).To(Equal("cn=openssh-lpk,cn=schema,cn=config"))
+		Expect(entry.Get("olcAttributeTypes")).To(ConsistOf(ContainSubstring("NAME 'sshPublicKey'")))
+		Expect(entry.Get("olcObjectClasses")).To(ConsistOf(ContainSubstring("NAME 'ldapPublicKey' SUP top AUXILIARY")))
+	})
+
+	It("only renders custom schemas", func() {
+		_, err := slapd.SchemaEntry("nis")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("leaves custom schemas out of the schemas loaded by the image", func() {
+		schemas := v1alpha1.SchemaList{"core", "cosine", v1alpha1.SchemaOpenSSHLPK, "nis"}
+		Expect(schemas.Bundled().Join()).To(Equal("core,cosine,nis"))
+		Expect(schemas.Custom()).To(Equal(v1alpha1.SchemaList{v1alpha1.SchemaOpenSSHLPK}))
+	})
+})
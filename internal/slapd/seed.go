@@ -0,0 +1,96 @@
+package slapd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/go-ldap/ldap/v3"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+// SeedTemplateData is the data available to templated seed sources
+type SeedTemplateData struct {
+	Suffix string
+	RootDN string
+}
+
+// PasswordFunc returns the hashed password generated for name
+type PasswordFunc func(name string) (string, error)
+
+// SeedRevision returns an identifier for the content of a seed source
+func SeedRevision(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// RenderSeed renders a templated seed source into LDIF
+func RenderSeed(name string, data []byte, db *v1alpha1.DatabaseSpec, password PasswordFunc) ([]byte, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(template.FuncMap{
+		"password": password,
+	}).Parse(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, SeedTemplateData{Suffix: db.Suffix, RootDN: db.RootDN()}); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// PresetEntries returns the entries making up a predefined directory information tree
+func PresetEntries(preset v1alpha1.DITPreset, suffix string) ([]*Entry, error) {
+	if preset == "" || preset == v1alpha1.DITPresetNone {
+		return nil, nil
+	}
+
+	base, err := SuffixEntry(suffix)
+	if err != nil {
+		return nil, err
+	}
+	entries := []*Entry{base}
+
+	for _, ou := range []string{"people", "groups", "services"} {
+		entries = append(entries, NewEntry(fmt.Sprintf("ou=%s,%s", ou, suffix)).
+			Add("objectClass", "top", "organizationalUnit").
+			Add("ou", ou))
+	}
+
+	return entries, nil
+}
+
+// SuffixEntry returns the entry at the root of a database
+func SuffixEntry(suffix string) (*Entry, error) {
+	dn, err := ldap.ParseDN(suffix)
+	if err != nil {
+		return nil, fmt.Errorf("invalid suffix %s: %w", suffix, err)
+	}
+	if len(dn.RDNs) == 0 || len(dn.RDNs[0].Attributes) != 1 {
+		return nil, fmt.Errorf("unsupported suffix %s", suffix)
+	}
+	rdn := dn.RDNs[0].Attributes[0]
+
+	entry := NewEntry(suffix)
+	switch strings.ToLower(rdn.Type) {
+	case "dc":
+		entry.Add("objectClass", "top", "dcObject", "organization")
+		entry.Add("dc", rdn.Value)
+		entry.Add("o", rdn.Value)
+	case "o":
+		entry.Add("objectClass", "top", "organization")
+		entry.Add("o", rdn.Value)
+	case "ou":
+		entry.Add("objectClass", "top", "organizationalUnit")
+		entry.Add("ou", rdn.Value)
+	default:
+		return nil, fmt.Errorf("unsupported suffix naming attribute %s", rdn.Type)
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,98 @@
+package slapd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("Seed", func() {
+	var db *v1alpha1.DatabaseSpec
+
+	BeforeEach(func() {
+		db = &v1alpha1.DatabaseSpec{
+			Name:   "example",
+			Suffix: "dc=example,dc=com",
+		}
+	})
+
+	Context("standard preset", func() {
+		var entries []*slapd.Entry
+		var err error
+
+		BeforeEach(func() {
+			entries, err = slapd.PresetEntries(v1alpha1.DITPresetStandard, db.Suffix)
+		})
+
+		It("doesn't return an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("creates the suffix entry and organizational units", func() {
+			Expect(entries).To(HaveLen(4))
+			Expect(entries[0].DN).To(Equal("dc=example,dc=com"))
+			Expect(entries[0].Get("objectClass")).To(Equal([]string{"top", "dcObject", "organization"}))
+			Expect(entries[0].Get("dc")).To(Equal([]string{"example"}))
+			Expect(entries[1].DN).To(Equal("ou=people,dc=example,dc=com"))
+			Expect(entries[2].DN).To(Equal("ou=groups,dc=example,dc=com"))
+			Expect(entries[3].DN).To(Equal("ou=services,dc=example,dc=com"))
+		})
+	})
+
+	Context("no preset", func() {
+		It("returns no entries", func() {
+			entries, err := slapd.PresetEntries(v1alpha1.DITPresetNone, db.Suffix)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+		})
+	})
+
+	Context("suffix entry", func() {
+		It("creates an organization for o suffixes", func() {
+			entry, err := slapd.SuffixEntry("o=example")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entry.Get("objectClass")).To(Equal([]string{"top", "organization"}))
+		})
+
+		It("rejects unsupported naming attributes", func() {
+			_, err := slapd.SuffixEntry("cn=example")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("render template", func() {
+		It("substitutes the suffix, root dn and passwords", func() {
+			requested := []string{}
+			rendered, err := slapd.RenderSeed("test", []byte(`dn: uid=alice,{{ .Suffix }}
+userPassword: {{ password "alice" }}
+manager: {{ .RootDN }}
+`), db, func(name string) (string, error) {
+				requested = append(requested, name)
+				return "{SSHA}hash", nil
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(rendered)).To(Equal(`dn: uid=alice,dc=example,dc=com
+userPassword: {SSHA}hash
+manager: cn=admin,dc=example,dc=com
+`))
+			Expect(requested).To(Equal([]string{"alice"}))
+		})
+
+		It("returns an error for invalid templates", func() {
+			_, err := slapd.RenderSeed("test", []byte(`{{ .Missing }}`), db, nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("revision", func() {
+		It("is stable for the same content", func() {
+			Expect(slapd.SeedRevision([]byte("foo"))).To(Equal(slapd.SeedRevision([]byte("foo"))))
+		})
+
+		It("changes with the content", func() {
+			Expect(slapd.SeedRevision([]byte("foo"))).ToNot(Equal(slapd.SeedRevision([]byte("bar"))))
+		})
+	})
+})
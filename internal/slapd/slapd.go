@@ -0,0 +1,91 @@
+// Package slapd manages the configuration and content of running slapd instances over LDAP
+package slapd
+
+import (
+	"fmt"
+	"net"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+const (
+	// ConfigDN is the base DN of the slapd configuration tree
+	ConfigDN = "cn=config"
+	// ConfigRootDN is the root DN of the config database. Its password is stored in the directory secret
+	ConfigRootDN = "cn=config"
+	// ConfigDir is the directory slapd keeps its cn=config database in
+	ConfigDir = "/etc/openldap/slapd.d"
+	// DataDir is the directory under which database files are stored
+	DataDir = "/var/lib/openldap"
+	// TLSDir is the directory the TLS secret is mounted at
+	TLSDir = "/etc/openldap/tls"
+	// ExternalCADir is the directory the CA certificate of an external provider is mounted at
+	ExternalCADir = "/etc/openldap/external-ca"
+	// PeerCADir is the directory the CA certificates of remote peers are mounted below, one directory per peer
+	PeerCADir = "/etc/openldap/peer-ca"
+	// LogDir is the directory slapd writes its log file and audit logs to when they are shipped by sidecars
+	LogDir = "/var/log/slapd"
+	// LDAPPort is the port slapd listens on for LDAP within its pod
+	LDAPPort = 3389
+)
+
+// ServiceURI returns the in-cluster URI of the directory service
+func ServiceURI(directory *v1alpha1.Directory) string {
+	return fmt.Sprintf("ldap://%s:389", ServiceHost(directory))
+}
+
+// ServiceHost returns the in-cluster host name of the directory service
+func ServiceHost(directory *v1alpha1.Directory) string {
+	return fmt.Sprintf("%s.%s.svc", directory.ServiceName(), directory.Namespace)
+}
+
+// ServiceLDAPSURI returns the in-cluster LDAPS URI of the directory service
+func ServiceLDAPSURI(directory *v1alpha1.Directory) string {
+	return fmt.Sprintf("ldaps://%s:636", ServiceHost(directory))
+}
+
+// WriteServiceURI returns the in-cluster URI of the service selecting providers
+func WriteServiceURI(directory *v1alpha1.Directory) string {
+	return fmt.Sprintf("ldap://%s:389", WriteServiceHost(directory))
+}
+
+// WriteServiceHost returns the in-cluster host name of the service selecting providers
+func WriteServiceHost(directory *v1alpha1.Directory) string {
+	return fmt.Sprintf("%s.%s.svc", directory.WriteServiceName(), directory.Namespace)
+}
+
+// ReadServiceURI returns the in-cluster URI of the service selecting instances serving searches
+func ReadServiceURI(directory *v1alpha1.Directory) string {
+	return fmt.Sprintf("ldap://%s:389", ReadServiceHost(directory))
+}
+
+// ReadServiceHost returns the in-cluster host name of the service selecting instances serving searches
+func ReadServiceHost(directory *v1alpha1.Directory) string {
+	return fmt.Sprintf("%s.%s.svc", directory.ReadServiceName(), directory.Namespace)
+}
+
+// InstanceURI returns the URI of a single instance of the directory, resolved through the headless service
+func InstanceURI(directory *v1alpha1.Directory, ordinal int) string {
+	return fmt.Sprintf("ldap://%s-%d.%s.%s.svc:%d",
+		directory.StatefulSetName(), ordinal, directory.HeadlessServiceName(), directory.Namespace, LDAPPort)
+}
+
+// DatabaseDirectory returns the path of the directory containing the files of a database
+func DatabaseDirectory(db *v1alpha1.DatabaseSpec) string {
+	return fmt.Sprintf("%s/%s", DataDir, db.Name)
+}
+
+// AccessLogDirectory returns the path of the directory containing the files of the accesslog database of a database
+func AccessLogDirectory(db *v1alpha1.DatabaseSpec) string {
+	return fmt.Sprintf("%s/%s.accesslog", DataDir, db.Name)
+}
+
+// PodURI returns the URI of the slapd instance running in the pod with the given IP
+func PodURI(ip string) string {
+	return fmt.Sprintf("ldap://%s", net.JoinHostPort(ip, fmt.Sprint(LDAPPort)))
+}
+
+// PeerCACertFile returns the path the CA certificate of the remote peer named name is mounted at
+func PeerCACertFile(name string) string {
+	return fmt.Sprintf("%s/%s/ca.crt", PeerCADir, name)
+}
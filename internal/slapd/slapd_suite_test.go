@@ -0,0 +1,13 @@
+package slapd_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSlapd(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Slapd Suite")
+}
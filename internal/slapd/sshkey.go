@@ -0,0 +1,93 @@
+package slapd
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+// minRSAKeyBits is the smallest RSA key accepted as an SSH public key
+const minRSAKeyBits = 2048
+
+// ParseSSHPublicKey validates a public key in authorized_keys format. Returns the key normalised to a single
+// line along with its SHA256 fingerprint. Keys with options, DSA keys and RSA keys shorter than 2048 bits are
+// rejected
+func ParseSSHPublicKey(key string) (string, string, error) {
+	parsed, comment, options, rest, err := ssh.ParseAuthorizedKey([]byte(key))
+	if err != nil {
+		return "", "", err
+	}
+	if len(options) > 0 {
+		return "", "", fmt.Errorf("key options aren't allowed")
+	}
+	if len(strings.TrimSpace(string(rest))) > 0 {
+		return "", "", fmt.Errorf("only a single key is allowed")
+	}
+
+	switch parsed.Type() {
+	case ssh.KeyAlgoDSA:
+		return "", "", fmt.Errorf("DSA keys aren't allowed")
+	case ssh.KeyAlgoRSA:
+		if crypto, ok := parsed.(ssh.CryptoPublicKey); ok {
+			if rsaKey, ok := crypto.CryptoPublicKey().(*rsa.PublicKey); ok && rsaKey.N.BitLen() < minRSAKeyBits {
+				return "", "", fmt.Errorf("RSA keys must be at least %d bits", minRSAKeyBits)
+			}
+		}
+	}
+
+	normalised := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(parsed)))
+	if comment != "" {
+		normalised = fmt.Sprintf("%s %s", normalised, comment)
+	}
+	return normalised, ssh.FingerprintSHA256(parsed), nil
+}
+
+// SSHPublicKeys resolves the SSH public keys of a user at now. Returns the sshPublicKey values of the keys which
+// are valid, unexpired and not revoked, the state of every key, and the time the next active key expires
+func SSHPublicKeys(user *v1alpha1.LdapUser, now time.Time) ([]string, []v1alpha1.SSHPublicKeyStatus, *time.Time) {
+	revoked := slices.Clone(user.Status.RevokedSSHPublicKeys)
+	parsed := make([]string, len(user.Spec.SSHPublicKeys))
+	statuses := make([]v1alpha1.SSHPublicKeyStatus, len(user.Spec.SSHPublicKeys))
+	for i, key := range user.Spec.SSHPublicKeys {
+		statuses[i].Name = key.Name
+		normalised, fingerprint, err := ParseSSHPublicKey(key.Key)
+		if err != nil {
+			statuses[i].State = v1alpha1.SSHPublicKeyInvalid
+			statuses[i].Message = err.Error()
+			continue
+		}
+		parsed[i] = normalised
+		statuses[i].Fingerprint = fingerprint
+		if key.Revoked && !slices.Contains(revoked, fingerprint) {
+			revoked = append(revoked, fingerprint)
+		}
+	}
+
+	values := []string{}
+	var expiry *time.Time
+	for i, key := range user.Spec.SSHPublicKeys {
+		switch {
+		case statuses[i].State == v1alpha1.SSHPublicKeyInvalid:
+		case slices.Contains(revoked, statuses[i].Fingerprint):
+			statuses[i].State = v1alpha1.SSHPublicKeyRevoked
+		case key.ExpiresAt != nil && !now.Before(key.ExpiresAt.Time):
+			statuses[i].State = v1alpha1.SSHPublicKeyExpired
+		default:
+			statuses[i].State = v1alpha1.SSHPublicKeyActive
+			if !slices.Contains(values, parsed[i]) {
+				values = append(values, parsed[i])
+			}
+			if key.ExpiresAt != nil && (expiry == nil || key.ExpiresAt.Time.Before(*expiry)) {
+				expiry = &key.ExpiresAt.Time
+			}
+		}
+	}
+
+	return values, statuses, expiry
+}
@@ -0,0 +1,119 @@
+package slapd_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+// authorizedKey returns a public key in authorized_keys format
+func authorizedKey(key any, comment string) string {
+	public, err := ssh.NewPublicKey(key)
+	Expect(err).NotTo(HaveOccurred())
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(public))) + " " + comment
+}
+
+func ed25519Key(comment string) string {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	return authorizedKey(public, comment)
+}
+
+var _ = Describe("SSH public keys", func() {
+	Context("parsing", func() {
+		It("normalises valid keys", func() {
+			key := ed25519Key("jdoe@laptop")
+			normalised, fingerprint, err := slapd.ParseSSHPublicKey("  " + key + "\n")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(normalised).To(Equal(key))
+			Expect(fingerprint).To(HavePrefix("SHA256:"))
+		})
+
+		It("rejects malformed keys", func() {
+			_, _, err := slapd.ParseSSHPublicKey("ssh-ed25519 not-base64")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects key options", func() {
+			_, _, err := slapd.ParseSSHPublicKey(`command="/bin/true" ` + ed25519Key("jdoe"))
+			Expect(err).To(MatchError(ContainSubstring("options")))
+		})
+
+		It("rejects several keys", func() {
+			_, _, err := slapd.ParseSSHPublicKey(ed25519Key("one") + "\n" + ed25519Key("two"))
+			Expect(err).To(MatchError(ContainSubstring("single key")))
+		})
+
+		It("rejects short RSA keys", func() {
+			private, err := rsa.GenerateKey(rand.Reader, 1024)
+			Expect(err).NotTo(HaveOccurred())
+			_, _, err = slapd.ParseSSHPublicKey(authorizedKey(&private.PublicKey, "jdoe"))
+			Expect(err).To(MatchError(ContainSubstring("2048 bits")))
+		})
+	})
+
+	Context("resolving the keys of a user", func() {
+		now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		var user *v1alpha1.LdapUser
+		var active, expired, revoked string
+
+		BeforeEach(func() {
+			active, expired, revoked = ed25519Key("active"), ed25519Key("expired"), ed25519Key("revoked")
+			user = &v1alpha1.LdapUser{
+				Spec: v1alpha1.LdapUserSpec{
+					SSHPublicKeys: []v1alpha1.SSHPublicKeySpec{
+						{Name: "active", Key: active, ExpiresAt: &metav1.Time{Time: now.Add(time.Hour)}},
+						{Name: "expired", Key: expired, ExpiresAt: &metav1.Time{Time: now.Add(-time.Hour)}},
+						{Name: "revoked", Key: revoked, Revoked: true},
+						{Name: "invalid", Key: "ssh-rsa"},
+					},
+				},
+			}
+		})
+
+		It("writes only active keys", func() {
+			values, statuses, expiry := slapd.SSHPublicKeys(user, now)
+			Expect(values).To(Equal([]string{active}))
+			Expect(expiry).To(Equal(&user.Spec.SSHPublicKeys[0].ExpiresAt.Time))
+
+			states := []v1alpha1.SSHPublicKeyState{}
+			for _, status := range statuses {
+				states = append(states, status.State)
+			}
+			Expect(states).To(Equal([]v1alpha1.SSHPublicKeyState{
+				v1alpha1.SSHPublicKeyActive, v1alpha1.SSHPublicKeyExpired, v1alpha1.SSHPublicKeyRevoked, v1alpha1.SSHPublicKeyInvalid,
+			}))
+			Expect(statuses[3].Message).NotTo(BeEmpty())
+		})
+
+		It("keeps revoked keys revoked under another name", func() {
+			_, statuses, _ := slapd.SSHPublicKeys(user, now)
+			user.Status.RevokedSSHPublicKeys = []string{statuses[2].Fingerprint}
+			user.Spec.SSHPublicKeys = []v1alpha1.SSHPublicKeySpec{{Name: "readded", Key: revoked}}
+
+			values, statuses, _ := slapd.SSHPublicKeys(user, now)
+			Expect(values).To(BeEmpty())
+			Expect(statuses[0].State).To(Equal(v1alpha1.SSHPublicKeyRevoked))
+		})
+
+		It("makes the user entry an ldapPublicKey", func() {
+			db := &v1alpha1.DatabaseSpec{Name: "example", Suffix: "dc=example,dc=com"}
+			user.Name = "jdoe"
+			values, _, _ := slapd.SSHPublicKeys(user, now)
+			entry := slapd.UserEntry(user, db, "", values)
+			Expect(entry.Get("objectClass")).To(Equal([]string{"inetOrgPerson", "ldapPublicKey"}))
+			Expect(entry.Get("sshPublicKey")).To(Equal([]string{active}))
+		})
+	})
+})
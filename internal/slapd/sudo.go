@@ -0,0 +1,149 @@
+package slapd
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+// generalizedTimeFormat is the format of GeneralizedTime attribute values
+const generalizedTimeFormat = "20060102150405Z"
+
+var (
+	sudoDigestPattern = regexp.MustCompile(`^(sha224|sha256|sha384|sha512):[A-Za-z0-9+/=]+$`)
+	sudoFlagPattern   = regexp.MustCompile(`^!?[a-z_]+$`)
+	sudoValuePattern  = regexp.MustCompile(`^[a-z_]+(=|\+=|-=)\S.*$`)
+)
+
+// SudoersEntry renders the organizational unit holding sudoRole entries
+func SudoersEntry(db *v1alpha1.DatabaseSpec) *Entry {
+	return NewEntry(db.SudoersDN()).
+		Add("objectClass", "top", "organizationalUnit").
+		Add("ou", db.Sudoers())
+}
+
+// SudoRoleEntry renders the sudoRole entry of a rule
+func SudoRoleEntry(rule *v1alpha1.SudoRule, db *v1alpha1.DatabaseSpec) *Entry {
+	hosts := rule.Spec.Hosts
+	if len(hosts) == 0 {
+		hosts = []string{"ALL"}
+	}
+
+	entry := NewEntry(rule.DN(db)).
+		Add("objectClass", "top", "sudoRole").
+		Add("cn", rule.Name).
+		Add("description", optional(rule.Spec.Description)...).
+		Add("sudoUser", rule.Spec.Users...).
+		Add("sudoHost", hosts...).
+		Add("sudoCommand", rule.Spec.Commands...).
+		Add("sudoRunAsUser", rule.Spec.RunAsUsers...).
+		Add("sudoRunAsGroup", rule.Spec.RunAsGroups...).
+		Add("sudoOption", rule.Spec.Options...).
+		Add("sudoNotBefore", optionalTime(rule.Spec.NotBefore)...).
+		Add("sudoNotAfter", optionalTime(rule.Spec.NotAfter)...)
+
+	if rule.Spec.Order != nil {
+		return entry.Add("sudoOrder", strconv.Itoa(int(*rule.Spec.Order)))
+	}
+	return entry.Add("sudoOrder")
+}
+
+// ValidateSudoRule checks the commands and options of a rule are well formed before they are handed to sudo
+func ValidateSudoRule(rule *v1alpha1.SudoRule) error {
+	fields := map[string][]string{
+		"user":        rule.Spec.Users,
+		"host":        rule.Spec.Hosts,
+		"runAs user":  rule.Spec.RunAsUsers,
+		"runAs group": rule.Spec.RunAsGroups,
+	}
+	for name, values := range fields {
+		for _, value := range values {
+			if strings.TrimSpace(value) == "" || strings.IndexFunc(value, unicode.IsControl) >= 0 {
+				return fmt.Errorf("invalid %s %q", name, value)
+			}
+		}
+	}
+
+	for _, command := range rule.Spec.Commands {
+		if err := validateSudoCommand(command); err != nil {
+			return fmt.Errorf("invalid command %q: %w", command, err)
+		}
+	}
+
+	for _, option := range rule.Spec.Options {
+		if strings.IndexFunc(option, unicode.IsControl) >= 0 ||
+			(!sudoFlagPattern.MatchString(option) && !sudoValuePattern.MatchString(option)) {
+			return fmt.Errorf("invalid option %q, expected flag, !flag, name=value, name+=value or name-=value", option)
+		}
+	}
+
+	if rule.Spec.NotBefore != nil && rule.Spec.NotAfter != nil && !rule.Spec.NotBefore.Before(rule.Spec.NotAfter) {
+		return fmt.Errorf("notBefore must be before notAfter")
+	}
+	return nil
+}
+
+// validateSudoCommand accepts ALL, sudoedit with absolute paths, and absolute command paths with optional
+// arguments and digest. Commands may be negated with !
+func validateSudoCommand(command string) error {
+	if strings.IndexFunc(command, unicode.IsControl) >= 0 {
+		return fmt.Errorf("control characters aren't allowed")
+	}
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(command), "!"))
+	if len(fields) == 0 {
+		return fmt.Errorf("command is empty")
+	}
+	if len(fields) == 1 && fields[0] == "ALL" {
+		return nil
+	}
+
+	if sudoDigestPattern.MatchString(fields[0]) {
+		fields = fields[1:]
+		if len(fields) == 0 {
+			return fmt.Errorf("digest without command")
+		}
+	}
+
+	if fields[0] == "sudoedit" {
+		if len(fields) == 1 {
+			return fmt.Errorf("sudoedit requires a file")
+		}
+		for _, file := range fields[1:] {
+			if err := validateSudoPath(file); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return validateSudoPath(fields[0])
+}
+
+// validateSudoPath accepts absolute, clean paths. A trailing slash matches every command in a directory
+func validateSudoPath(file string) error {
+	if !strings.HasPrefix(file, "/") {
+		return fmt.Errorf("path %s isn't absolute", file)
+	}
+	clean := path.Clean(file)
+	if strings.HasSuffix(file, "/") && file != "/" {
+		clean += "/"
+	}
+	if clean != file {
+		return fmt.Errorf("path %s isn't clean, expected %s", file, clean)
+	}
+	return nil
+}
+
+// optionalTime returns t as a GeneralizedTime attribute value, or no values if it is nil
+func optionalTime(t *metav1.Time) []string {
+	if t.IsZero() {
+		return nil
+	}
+	return []string{t.UTC().Format(generalizedTimeFormat)}
+}
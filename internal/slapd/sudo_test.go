@@ -0,0 +1,98 @@
+package slapd_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("Sudo", func() {
+	var db *v1alpha1.DatabaseSpec
+	var rule *v1alpha1.SudoRule
+
+	BeforeEach(func() {
+		db = &v1alpha1.DatabaseSpec{Name: "example", Suffix: "dc=example,dc=com"}
+		rule = &v1alpha1.SudoRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "restart", Namespace: "bar"},
+			Spec: v1alpha1.SudoRuleSpec{
+				Users:    []string{"%developers", "jdoe"},
+				Commands: []string{"/usr/bin/systemctl restart nginx", "!/usr/bin/su"},
+				Options:  []string{"!authenticate", "env_keep+=SSH_AUTH_SOCK"},
+			},
+		}
+	})
+
+	Context("sudoRole entry", func() {
+		It("is created below ou=SUDOers", func() {
+			entry := slapd.SudoRoleEntry(rule, db)
+			Expect(entry.DN).To(Equal("cn=restart,ou=SUDOers,dc=example,dc=com"))
+			Expect(entry.Get("objectClass")).To(Equal([]string{"top", "sudoRole"}))
+			Expect(entry.Get("sudoUser")).To(Equal([]string{"%developers", "jdoe"}))
+			Expect(entry.Get("sudoHost")).To(Equal([]string{"ALL"}))
+			Expect(entry.Get("sudoCommand")).To(Equal([]string{"/usr/bin/systemctl restart nginx", "!/usr/bin/su"}))
+			Expect(entry.Get("sudoOption")).To(Equal([]string{"!authenticate", "env_keep+=SSH_AUTH_SOCK"}))
+			Expect(entry.Get("sudoOrder")).To(BeEmpty())
+		})
+
+		It("uses the configured sudoers organizational unit", func() {
+			db.SudoersOU = "sudo"
+			Expect(slapd.SudoRoleEntry(rule, db).DN).To(Equal("cn=restart,ou=sudo,dc=example,dc=com"))
+			Expect(slapd.SudoersEntry(db).Get("ou")).To(Equal([]string{"sudo"}))
+		})
+
+		It("renders the validity interval as generalized time", func() {
+			rule.Spec.NotBefore = &metav1.Time{Time: time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)}
+			rule.Spec.NotAfter = &metav1.Time{Time: time.Date(2025, 6, 1, 18, 30, 0, 0, time.UTC)}
+			rule.Spec.Order = ptr.To(int32(10))
+			entry := slapd.SudoRoleEntry(rule, db)
+			Expect(entry.Get("sudoNotBefore")).To(Equal([]string{"20250601080000Z"}))
+			Expect(entry.Get("sudoNotAfter")).To(Equal([]string{"20250601183000Z"}))
+			Expect(entry.Get("sudoOrder")).To(Equal([]string{"10"}))
+		})
+	})
+
+	Context("validation", func() {
+		It("accepts well formed rules", func() {
+			rule.Spec.Commands = append(rule.Spec.Commands, "ALL", "sudoedit /etc/hosts", "/usr/sbin/",
+				"sha256:0b8bMhIUmhfHBOUzWNHxXsTWHfmL8Ff5ab5ln2QfyUU= /usr/bin/id")
+			Expect(slapd.ValidateSudoRule(rule)).To(Succeed())
+		})
+
+		DescribeTable("rejects commands",
+			func(command string) {
+				rule.Spec.Commands = []string{command}
+				Expect(slapd.ValidateSudoRule(rule)).To(MatchError(ContainSubstring("invalid command")))
+			},
+			Entry("relative path", "systemctl restart nginx"),
+			Entry("unclean path", "/usr/bin/../bin/sh"),
+			Entry("sudoedit without file", "sudoedit"),
+			Entry("sudoedit with relative file", "sudoedit hosts"),
+			Entry("digest without command", "sha256:0b8bMhIU"),
+			Entry("newline", "/usr/bin/id\n/bin/sh"),
+		)
+
+		DescribeTable("rejects options",
+			func(option string) {
+				rule.Spec.Options = []string{option}
+				Expect(slapd.ValidateSudoRule(rule)).To(MatchError(ContainSubstring("invalid option")))
+			},
+			Entry("double negation", "!!authenticate"),
+			Entry("missing value", "secure_path="),
+			Entry("unknown operator", "env_keep*=HOME"),
+			Entry("uppercase name", "Authenticate"),
+		)
+
+		It("rejects an empty validity interval", func() {
+			now := metav1.Now()
+			rule.Spec.NotBefore, rule.Spec.NotAfter = &now, &now
+			Expect(slapd.ValidateSudoRule(rule)).To(MatchError(ContainSubstring("notBefore")))
+		})
+	})
+})
@@ -0,0 +1,293 @@
+package slapd
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Type to represent the kind of change made to an entry
+type ChangeType string
+
+const (
+	ChangeAdd    ChangeType = "add"
+	ChangeModify ChangeType = "modify"
+	ChangeDelete ChangeType = "delete"
+)
+
+// SecretAttributes hold passwords or password hashes, and are left out of the attributes of changes passed on
+var SecretAttributes = []string{"userPassword", "authPassword", "pwdHistory", "sambaNTPassword", "sambaLMPassword"}
+
+// PublicAttributes returns attributes without the secret attributes
+func PublicAttributes(attributes map[string][]string) map[string][]string {
+	public := map[string][]string{}
+	for name, values := range attributes {
+		// Attribute options such as ;binary don't change the attribute
+		base, _, _ := strings.Cut(name, ";")
+		if !slices.ContainsFunc(SecretAttributes, func(secret string) bool { return strings.EqualFold(secret, base) }) {
+			public[name] = values
+		}
+	}
+	return public
+}
+
+// syncBufferSize is the number of sync messages read ahead of the handler
+const syncBufferSize = 64
+
+// Change is a change to an entry received over syncrepl. Changes without a type only advance the cookie
+type Change struct {
+	Type      ChangeType
+	DN        string
+	EntryUUID string
+	// CSN of the change, the entryCSN of added and modified entries
+	CSN string
+	// Attributes of added and modified entries
+	Attributes map[string][]string
+	// Changed are the names of the attributes which changed, all attributes of added entries
+	Changed []string
+	// Cookie to resume the sync from once the change has been handled
+	Cookie string
+}
+
+// SyncRequest selects the entries to sync and the point to resume from
+type SyncRequest struct {
+	Base   string
+	Filter string
+	// Attributes of the entries to sync. Defaults to all user attributes
+	Attributes []string
+	// Cookie of the last change handled. Without a cookie only changes made after the sync starts are handled
+	Cookie string
+}
+
+// syncedEntry is the last known state of an entry, used to work out which attributes a modification changed
+type syncedEntry struct {
+	dn         string
+	attributes map[string][]string
+}
+
+// Sync follows the changes to the entries selected by request with a refreshAndPersist syncrepl search,
+// calling handle for each change in order until ctx is cancelled or handle returns an error. The database must
+// have the syncprov overlay.
+//
+// syncrepl sends the whole of a modified entry, so the changed attributes are found by comparing it to the
+// last known state of the entry. Without a cookie the refresh phase returns every entry, which is that state, so
+// no change made after the sync starts is missed. With a cookie the refresh phase only returns the entries changed
+// since, so the current state is read first. Changes made while the sync wasn't running can't be compared and are
+// delivered with all attributes marked changed
+func (client *Client) Sync(ctx context.Context, request SyncRequest, handle func(*Change) error) error {
+	attributes := request.Attributes
+	if len(attributes) == 0 {
+		attributes = []string{"*"}
+	}
+	attributes = append(slices.Clone(attributes), "entryUUID", "entryCSN")
+
+	known := map[string]*syncedEntry{}
+	resuming := request.Cookie != ""
+	if resuming {
+		entries, err := client.Search(request.Base, ldap.ScopeWholeSubtree, request.Filter, attributes...)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			uuid := entry.GetAttributeValue("entryUUID")
+			known[uuid] = &syncedEntry{dn: entry.DN, attributes: entryAttributes(entry)}
+		}
+	}
+
+	// Persistent searches outlive the request timeout of the connection
+	client.conn.SetTimeout(0)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	response := client.conn.Syncrepl(ctx, ldap.NewSearchRequest(
+		request.Base, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		request.Filter, attributes, nil,
+	), syncBufferSize, ldap.SyncRequestModeRefreshAndPersist, []byte(request.Cookie), false)
+
+	// Without a cookie the entries returned by the refresh phase only record the state changes are compared to
+	refreshing := true
+
+	for response.Next() {
+		for _, control := range response.Controls() {
+			changes := []*Change{}
+
+			switch control := control.(type) {
+			case *ldap.ControlSyncState:
+				change := syncStateChange(known, response.Entry(), control, refreshing)
+				if change != nil && (!refreshing || resuming || change.Type == "") {
+					changes = append(changes, change)
+				}
+
+			case *ldap.ControlSyncInfo:
+				cookie, done, deleted := syncInfo(control)
+				if done {
+					refreshing = false
+				}
+				for _, uuid := range deleted {
+					entry, found := known[uuid]
+					if !found {
+						continue
+					}
+					delete(known, uuid)
+					if !refreshing || resuming {
+						changes = append(changes, &Change{Type: ChangeDelete, DN: entry.dn, EntryUUID: uuid})
+					}
+				}
+				if len(changes) > 0 {
+					changes[len(changes)-1].Cookie = cookie
+				} else if cookie != "" {
+					changes = append(changes, &Change{Cookie: cookie})
+				}
+
+			case *ldap.ControlSyncDone:
+				if len(control.Cookie) > 0 {
+					changes = append(changes, &Change{Cookie: string(control.Cookie)})
+				}
+			}
+
+			for _, change := range changes {
+				if change.Type == "" && change.Cookie == "" {
+					continue
+				}
+				if err := handle(change); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if err := response.Err(); err != nil && !errors.Is(ctx.Err(), context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// syncStateChange returns the change to the entry described by a sync state control, updating the known state
+// of the entry
+func syncStateChange(known map[string]*syncedEntry, entry *ldap.Entry, control *ldap.ControlSyncState, refreshing bool) *Change {
+	if entry == nil {
+		return nil
+	}
+	uuid := control.EntryUUID.String()
+	change := &Change{DN: entry.DN, EntryUUID: uuid, Cookie: string(control.Cookie)}
+
+	switch control.State {
+	case ldap.SyncStatePresent:
+		if previous, found := known[uuid]; found {
+			previous.dn = entry.DN
+		}
+		return change
+
+	case ldap.SyncStateDelete:
+		delete(known, uuid)
+		change.Type = ChangeDelete
+		return change
+	}
+
+	attributes := entryAttributes(entry)
+	change.CSN = entry.GetAttributeValue("entryCSN")
+	change.Attributes = attributes
+
+	previous, found := known[uuid]
+	known[uuid] = &syncedEntry{dn: entry.DN, attributes: attributes}
+	if !found {
+		change.Type = ChangeAdd
+		change.Changed = attributeNames(attributes)
+		return change
+	}
+
+	change.Type = ChangeModify
+	change.Changed = changedAttributes(previous.attributes, attributes)
+	if len(change.Changed) == 0 {
+		if !refreshing {
+			// Only attributes which aren't synced changed
+			change.Type = ""
+			return change
+		}
+		change.Changed = attributeNames(attributes)
+	}
+	return change
+}
+
+// syncInfo returns the cookie of a sync info message, whether the refresh phase is done, and the UUIDs of
+// entries it reports deleted
+func syncInfo(control *ldap.ControlSyncInfo) (string, bool, []string) {
+	switch {
+	case control.NewCookie != nil:
+		return string(control.NewCookie.Cookie), false, nil
+	case control.RefreshDelete != nil:
+		return string(control.RefreshDelete.Cookie), control.RefreshDelete.RefreshDone, nil
+	case control.RefreshPresent != nil:
+		return string(control.RefreshPresent.Cookie), control.RefreshPresent.RefreshDone, nil
+	case control.SyncIdSet != nil:
+		deleted := []string{}
+		if control.SyncIdSet.RefreshDeletes {
+			for _, uuid := range control.SyncIdSet.SyncUUIDs {
+				deleted = append(deleted, uuid.String())
+			}
+		}
+		return string(control.SyncIdSet.Cookie), false, deleted
+	}
+	return "", false, nil
+}
+
+// entryAttributes returns the synced attributes of an entry, without the entryUUID and entryCSN requested to
+// follow it
+func entryAttributes(entry *ldap.Entry) map[string][]string {
+	attributes := map[string][]string{}
+	for _, attribute := range entry.Attributes {
+		if !strings.EqualFold(attribute.Name, "entryUUID") && !strings.EqualFold(attribute.Name, "entryCSN") {
+			attributes[attribute.Name] = attribute.Values
+		}
+	}
+	return attributes
+}
+
+func attributeNames(attributes map[string][]string) []string {
+	names := []string{}
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// changedAttributes returns the names of the attributes which were added, removed or whose values changed
+func changedAttributes(previous, current map[string][]string) []string {
+	changed := []string{}
+	for name, values := range current {
+		if !slices.Equal(previous[name], values) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range previous {
+		if _, found := current[name]; !found {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// CookieCSN returns the most recent CSN of a syncrepl cookie, e.g. rid=000,sid=001,csn=20250101120000.000000Z#...
+func CookieCSN(cookie string) string {
+	latest := ""
+	for _, field := range strings.Split(cookie, ",") {
+		value, found := strings.CutPrefix(field, "csn=")
+		if !found {
+			continue
+		}
+		for _, csn := range strings.Split(value, ";") {
+			// CSNs start with their timestamp, so compare as strings
+			if csn > latest {
+				latest = csn
+			}
+		}
+	}
+	return latest
+}
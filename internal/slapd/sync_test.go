@@ -0,0 +1,24 @@
+package slapd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("Sync", func() {
+	It("returns the CSN of a cookie", func() {
+		Expect(slapd.CookieCSN("rid=000,sid=001,csn=20250101120000.000000Z#000000#001#000000")).
+			To(Equal("20250101120000.000000Z#000000#001#000000"))
+	})
+
+	It("returns the most recent CSN of a multi-provider cookie", func() {
+		Expect(slapd.CookieCSN("rid=000,csn=20250102120000.000000Z#000000#001#000000;20250101120000.000000Z#000000#002#000000")).
+			To(Equal("20250102120000.000000Z#000000#001#000000"))
+	})
+
+	It("returns nothing for cookies without a CSN", func() {
+		Expect(slapd.CookieCSN("rid=000")).To(BeEmpty())
+	})
+})
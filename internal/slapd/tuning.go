@@ -0,0 +1,168 @@
+package slapd
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+// FrontendDN is the DN of the frontend database, whose limits apply to every database
+const FrontendDN = "olcDatabase={-1}frontend,cn=config"
+
+// defaultMaxSize is the maximum size of a database under the Auto profile when the directory has neither
+// persistent storage nor an ephemeral storage request. The MDB default of 10MiB is too small for most directories
+var defaultMaxSize = resource.MustParse("1Gi")
+
+// mdbMaxSize is the MDB default maximum size, which applies to accesslog databases without a maximum size
+var mdbMaxSize = resource.MustParse("10Mi")
+
+// DirectoryTuning returns the tuning of the slapd daemon, with settings which aren't set derived from the
+// tuning profile
+func DirectoryTuning(directory *v1alpha1.Directory) *v1alpha1.TuningSpec {
+	tuning := &v1alpha1.TuningSpec{}
+	if directory.Spec.SlapdConfig != nil && directory.Spec.SlapdConfig.Tuning != nil {
+		tuning = directory.Spec.SlapdConfig.Tuning.DeepCopy()
+	}
+	if tuning.Profile != v1alpha1.TuningProfileAuto {
+		return tuning
+	}
+
+	cpu := requestedCPU(directory)
+	cores := int32((cpu.MilliValue() + 999) / 1000)
+	if tuning.Threads == nil {
+		tuning.Threads = ptr.To(clamp(4*cores, 8, 64))
+	}
+	if tuning.ToolThreads == nil {
+		tuning.ToolThreads = ptr.To(clamp(cores, 1, 8))
+	}
+	return tuning
+}
+
+// DatabaseTuning returns the tuning of db, with settings which aren't set derived from the tuning profile. The
+// persistent storage of the directory, or its requested ephemeral storage without it, is shared evenly between
+// the databases after setting aside the accesslog databases of delta-syncrepl
+func DatabaseTuning(directory *v1alpha1.Directory, db *v1alpha1.DatabaseSpec) *v1alpha1.DatabaseTuningSpec {
+	tuning := &v1alpha1.DatabaseTuningSpec{}
+	if db.Tuning != nil {
+		tuning = db.Tuning.DeepCopy()
+	}
+	if DirectoryTuning(directory).Profile != v1alpha1.TuningProfileAuto {
+		return tuning
+	}
+
+	if tuning.MaxSize == nil {
+		maxSize := defaultMaxSize.DeepCopy()
+		if storage := availableStorage(directory); !storage.IsZero() {
+			share := storage.Value() / int64(len(directory.Spec.SlapdConfig.Databases))
+			if accessLog := accessLogSize(directory); accessLog < share {
+				share -= accessLog
+			}
+			maxSize = *resource.NewQuantity(share, resource.BinarySI)
+		}
+		tuning.MaxSize = &maxSize
+	}
+	if tuning.Checkpoint == nil {
+		tuning.Checkpoint = &v1alpha1.MdbCheckpointSpec{KBytes: 1024, Minutes: 5}
+	}
+	return tuning
+}
+
+// availableStorage returns the size of the volume holding the databases, zero if unknown
+func availableStorage(directory *v1alpha1.Directory) resource.Quantity {
+	if directory.Spec.Storage != nil {
+		return directory.Spec.Storage.Size
+	}
+	return requested(directory, corev1.ResourceEphemeralStorage)
+}
+
+// accessLogSize returns the size the accesslog database of each data database may grow to, zero unless the
+// directory replicates with delta-syncrepl
+func accessLogSize(directory *v1alpha1.Directory) int64 {
+	if !directory.Replicated() || directory.ReplicationMode() != v1alpha1.ReplicationModeDeltaSyncrepl {
+		return 0
+	}
+	if accessLog := directory.Spec.Replication.AccessLog; accessLog != nil && accessLog.MaxSize != nil {
+		return accessLog.MaxSize.Value()
+	}
+	return mdbMaxSize.Value()
+}
+
+// requestedCPU returns the CPU requested by the slapd container, at least one core
+func requestedCPU(directory *v1alpha1.Directory) resource.Quantity {
+	cpu := requested(directory, corev1.ResourceCPU)
+	if cpu.IsZero() {
+		return resource.MustParse("1")
+	}
+	return cpu
+}
+
+// requested returns the request of the slapd container for a resource, falling back to its limit
+func requested(directory *v1alpha1.Directory, name corev1.ResourceName) resource.Quantity {
+	if directory.Spec.Resources == nil {
+		return resource.Quantity{}
+	}
+	if quantity, found := directory.Spec.Resources.Requests[name]; found {
+		return quantity
+	}
+	return directory.Spec.Resources.Limits[name]
+}
+
+func clamp(value, lower, upper int32) int32 {
+	return min(max(value, lower), upper)
+}
+
+// TuningEntry renders the tuning attributes of the global config entry. Settings which aren't set are removed,
+// restoring the slapd defaults
+func TuningEntry(tuning *v1alpha1.TuningSpec) *Entry {
+	return NewEntry(ConfigDN).
+		Add("olcThreads", intValue(tuning.Threads)...).
+		Add("olcToolThreads", intValue(tuning.ToolThreads)...).
+		Add("olcIdleTimeout", intValue(tuning.IdleTimeout)...).
+		Add("olcConnMaxPending", intValue(tuning.ConnMaxPending)...)
+}
+
+// FrontendTuningEntry renders the search limits of the frontend database
+func FrontendTuningEntry(tuning *v1alpha1.TuningSpec) *Entry {
+	return NewEntry(FrontendDN).
+		Add("olcSizeLimit", limitValue(tuning.SizeLimit)...).
+		Add("olcTimeLimit", limitValue(tuning.TimeLimit)...)
+}
+
+// DatabaseTuningEntry renders the tuning attributes of the data database with the DN dn
+func DatabaseTuningEntry(dn string, tuning *v1alpha1.DatabaseTuningSpec) *Entry {
+	entry := NewEntry(dn)
+	if tuning.MaxSize != nil {
+		entry.Add("olcDbMaxSize", fmt.Sprint(tuning.MaxSize.Value()))
+	} else {
+		entry.Add("olcDbMaxSize")
+	}
+	flags := []string{}
+	for _, flag := range tuning.EnvFlags {
+		flags = append(flags, string(flag))
+	}
+	entry.Add("olcDbEnvFlags", flags...)
+	if tuning.Checkpoint != nil {
+		entry.Add("olcDbCheckpoint", fmt.Sprintf("%d %d", tuning.Checkpoint.KBytes, tuning.Checkpoint.Minutes))
+	} else {
+		entry.Add("olcDbCheckpoint")
+	}
+	return entry
+}
+
+func intValue(value *int32) []string {
+	if value == nil {
+		return nil
+	}
+	return []string{fmt.Sprint(*value)}
+}
+
+func limitValue(value *int32) []string {
+	if value != nil && *value < 0 {
+		return []string{"unlimited"}
+	}
+	return intValue(value)
+}
@@ -0,0 +1,135 @@
+package slapd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("Tuning", func() {
+	const dn = "olcDatabase={1}mdb,cn=config"
+	var directory *v1alpha1.Directory
+
+	BeforeEach(func() {
+		directory = &v1alpha1.Directory{
+			Spec: v1alpha1.DirectorySpec{
+				SlapdConfig: &v1alpha1.SlapdConfigSpec{
+					Databases: []v1alpha1.DatabaseSpec{
+						{Name: "example", Suffix: "dc=example,dc=com"},
+						{Name: "other", Suffix: "dc=other,dc=com"},
+					},
+				},
+			},
+		}
+	})
+
+	Context("without tuning", func() {
+		It("removes the tuning attributes", func() {
+			entry := slapd.TuningEntry(slapd.DirectoryTuning(directory))
+			Expect(entry.DN).To(Equal("cn=config"))
+			for _, attribute := range entry.Attributes {
+				Expect(attribute.Values).To(BeEmpty())
+			}
+			db := &directory.Spec.SlapdConfig.Databases[0]
+			Expect(slapd.DatabaseTuningEntry(dn, slapd.DatabaseTuning(directory, db)).Get("olcDbMaxSize")).To(BeEmpty())
+		})
+	})
+
+	Context("with explicit settings", func() {
+		BeforeEach(func() {
+			directory.Spec.SlapdConfig.Tuning = &v1alpha1.TuningSpec{
+				Threads:        ptr.To(int32(32)),
+				IdleTimeout:    ptr.To(int32(600)),
+				ConnMaxPending: ptr.To(int32(200)),
+				SizeLimit:      ptr.To(int32(-1)),
+				TimeLimit:      ptr.To(int32(60)),
+			}
+			directory.Spec.SlapdConfig.Databases[0].Tuning = &v1alpha1.DatabaseTuningSpec{
+				MaxSize:    ptr.To(resource.MustParse("2Gi")),
+				EnvFlags:   []v1alpha1.MdbEnvFlag{"writemap", "nometasync"},
+				Checkpoint: &v1alpha1.MdbCheckpointSpec{KBytes: 512, Minutes: 10},
+			}
+		})
+
+		It("renders the global settings", func() {
+			entry := slapd.TuningEntry(slapd.DirectoryTuning(directory))
+			Expect(entry.Get("olcThreads")).To(Equal([]string{"32"}))
+			Expect(entry.Get("olcToolThreads")).To(BeEmpty())
+			Expect(entry.Get("olcIdleTimeout")).To(Equal([]string{"600"}))
+			Expect(entry.Get("olcConnMaxPending")).To(Equal([]string{"200"}))
+		})
+
+		It("renders the limits on the frontend", func() {
+			entry := slapd.FrontendTuningEntry(slapd.DirectoryTuning(directory))
+			Expect(entry.DN).To(Equal("olcDatabase={-1}frontend,cn=config"))
+			Expect(entry.Get("olcSizeLimit")).To(Equal([]string{"unlimited"}))
+			Expect(entry.Get("olcTimeLimit")).To(Equal([]string{"60"}))
+		})
+
+		It("renders the database settings", func() {
+			db := &directory.Spec.SlapdConfig.Databases[0]
+			entry := slapd.DatabaseTuningEntry(dn, slapd.DatabaseTuning(directory, db))
+			Expect(entry.Get("olcDbMaxSize")).To(Equal([]string{"2147483648"}))
+			Expect(entry.Get("olcDbEnvFlags")).To(Equal([]string{"writemap", "nometasync"}))
+			Expect(entry.Get("olcDbCheckpoint")).To(Equal([]string{"512 10"}))
+		})
+	})
+
+	Context("with the auto profile", func() {
+		BeforeEach(func() {
+			directory.Spec.SlapdConfig.Tuning = &v1alpha1.TuningSpec{
+				Profile:     v1alpha1.TuningProfileAuto,
+				ToolThreads: ptr.To(int32(2)),
+			}
+			directory.Spec.Resources = &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:              resource.MustParse("3500m"),
+					corev1.ResourceEphemeralStorage: resource.MustParse("8Gi"),
+				},
+			}
+		})
+
+		It("derives the thread pool from the requested CPU", func() {
+			tuning := slapd.DirectoryTuning(directory)
+			Expect(tuning.Threads).To(Equal(ptr.To(int32(16))))
+		})
+
+		It("keeps explicit settings", func() {
+			Expect(slapd.DirectoryTuning(directory).ToolThreads).To(Equal(ptr.To(int32(2))))
+		})
+
+		It("shares the requested storage between the databases", func() {
+			tuning := slapd.DatabaseTuning(directory, &directory.Spec.SlapdConfig.Databases[1])
+			Expect(tuning.MaxSize.Value()).To(Equal(int64(4 << 30)))
+			Expect(tuning.Checkpoint).To(Equal(&v1alpha1.MdbCheckpointSpec{KBytes: 1024, Minutes: 5}))
+		})
+
+		It("shares the persistent storage between the databases", func() {
+			directory.Spec.Storage = &v1alpha1.StorageSpec{Size: resource.MustParse("20Gi")}
+			tuning := slapd.DatabaseTuning(directory, &directory.Spec.SlapdConfig.Databases[0])
+			Expect(tuning.MaxSize.Value()).To(Equal(int64(10 << 30)))
+
+			By("setting aside the accesslog databases of delta-syncrepl")
+			directory.Spec.Replicas = 3
+			directory.Spec.Replication = &v1alpha1.ReplicationSpec{
+				Mode:      v1alpha1.ReplicationModeDeltaSyncrepl,
+				AccessLog: &v1alpha1.AccessLogSpec{MaxSize: ptr.To(resource.MustParse("2Gi"))},
+			}
+			tuning = slapd.DatabaseTuning(directory, &directory.Spec.SlapdConfig.Databases[0])
+			Expect(tuning.MaxSize.Value()).To(Equal(int64(8 << 30)))
+		})
+
+		It("falls back to defaults without resource requests", func() {
+			directory.Spec.Resources = nil
+			Expect(slapd.DirectoryTuning(directory).Threads).To(Equal(ptr.To(int32(8))))
+			tuning := slapd.DatabaseTuning(directory, &directory.Spec.SlapdConfig.Databases[0])
+			Expect(tuning.MaxSize.Value()).To(Equal(int64(1 << 30)))
+		})
+	})
+})
@@ -0,0 +1,95 @@
+package slapd
+
+import (
+	"strconv"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+)
+
+// UserEntry renders the entry of a user. password is the hashed password, or empty if the user has no password,
+// and sshKeys are the active SSH public keys of the user. The uidNumber and gidNumber of a POSIX user are taken
+// from its status, where they are recorded once allocated
+func UserEntry(user *v1alpha1.LdapUser, db *v1alpha1.DatabaseSpec, password string, sshKeys []string) *Entry {
+	entry := NewEntry(user.DN(db)).
+		Add("objectClass", "inetOrgPerson").
+		Add("uid", user.UID()).
+		Add("cn", user.Spec.CN).
+		Add("sn", user.Spec.SN).
+		Add("givenName", optional(user.Spec.GivenName)...).
+		Add("displayName", optional(user.Spec.DisplayName)...).
+		Add("mail", user.Spec.Mail...).
+		Add("userPassword", optional(password)...)
+
+	posix := user.Spec.Posix
+	if posix != nil && user.Status.UIDNumber != nil && user.Status.GIDNumber != nil {
+		entry.Add("objectClass", "posixAccount").
+			Add("uidNumber", strconv.FormatInt(*user.Status.UIDNumber, 10)).
+			Add("gidNumber", strconv.FormatInt(*user.Status.GIDNumber, 10)).
+			Add("homeDirectory", user.HomeDirectory()).
+			Add("loginShell", optional(posix.LoginShell)...)
+	} else {
+		entry.Add("uidNumber").
+			Add("gidNumber").
+			Add("homeDirectory").
+			Add("loginShell")
+	}
+
+	if len(sshKeys) > 0 {
+		entry.Add("objectClass", "ldapPublicKey")
+	}
+	return entry.Add("sshPublicKey", sshKeys...)
+}
+
+// GroupEntry renders the entry of a group with the given members. groupOfNames requires at least one member, so a
+// group without members holds the empty DN. A POSIX group is a posixGroup listing the uids of its members, taking
+// its gidNumber from its status
+func GroupEntry(group *v1alpha1.LdapGroup, db *v1alpha1.DatabaseSpec, members []*v1alpha1.LdapUser) *Entry {
+	entry := NewEntry(group.DN(db))
+	if group.Spec.Posix != nil && group.Status.GIDNumber != nil {
+		uids := []string{}
+		for _, member := range members {
+			uids = append(uids, member.UID())
+		}
+		return entry.
+			Add("objectClass", "posixGroup").
+			Add("cn", group.CN()).
+			Add("gidNumber", strconv.FormatInt(*group.Status.GIDNumber, 10)).
+			Add("description", optional(group.Spec.Description)...).
+			Add("memberUid", uids...)
+	}
+
+	dns := []string{}
+	for _, member := range members {
+		dns = append(dns, member.Status.DN)
+	}
+	if len(dns) == 0 {
+		dns = []string{""}
+	}
+	return entry.
+		Add("objectClass", "groupOfNames").
+		Add("cn", group.CN()).
+		Add("description", optional(group.Spec.Description)...).
+		Add("member", dns...)
+}
+
+// PeopleEntry renders the organizational unit holding users
+func PeopleEntry(db *v1alpha1.DatabaseSpec) *Entry {
+	return NewEntry(db.PeopleDN()).
+		Add("objectClass", "top", "organizationalUnit").
+		Add("ou", "people")
+}
+
+// GroupsEntry renders the organizational unit holding groups
+func GroupsEntry(db *v1alpha1.DatabaseSpec) *Entry {
+	return NewEntry(db.GroupsDN()).
+		Add("objectClass", "top", "organizationalUnit").
+		Add("ou", "groups")
+}
+
+// optional returns value as a single attribute value, or no values if it is empty
+func optional(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
@@ -0,0 +1,104 @@
+package slapd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	v1alpha1 "github.com/paddyoneill/openldap-operator/api/v1alpha1"
+	"github.com/paddyoneill/openldap-operator/internal/slapd"
+)
+
+var _ = Describe("Users and groups", func() {
+	var db *v1alpha1.DatabaseSpec
+	var user *v1alpha1.LdapUser
+	var group *v1alpha1.LdapGroup
+
+	BeforeEach(func() {
+		db = &v1alpha1.DatabaseSpec{
+			Name:   "example",
+			Suffix: "dc=example,dc=com",
+		}
+		user = &v1alpha1.LdapUser{
+			ObjectMeta: metav1.ObjectMeta{Name: "jdoe", Namespace: "bar"},
+			Spec: v1alpha1.LdapUserSpec{
+				DirectoryRef: corev1.LocalObjectReference{Name: "foo-directory"},
+				CN:           "Jane Doe",
+				SN:           "Doe",
+				Mail:         []string{"jane.doe@example.com"},
+			},
+		}
+		group = &v1alpha1.LdapGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "developers", Namespace: "bar"},
+			Spec: v1alpha1.LdapGroupSpec{
+				DirectoryRef: corev1.LocalObjectReference{Name: "foo-directory"},
+			},
+		}
+	})
+
+	Context("user entry", func() {
+		It("is an inetOrgPerson below ou=people", func() {
+			entry := slapd.UserEntry(user, db, "{SSHA}hash", nil)
+			Expect(entry.DN).To(Equal("uid=jdoe,ou=people,dc=example,dc=com"))
+			Expect(entry.Get("objectClass")).To(Equal([]string{"inetOrgPerson"}))
+			Expect(entry.Get("cn")).To(Equal([]string{"Jane Doe"}))
+			Expect(entry.Get("sn")).To(Equal([]string{"Doe"}))
+			Expect(entry.Get("mail")).To(Equal([]string{"jane.doe@example.com"}))
+			Expect(entry.Get("userPassword")).To(Equal([]string{"{SSHA}hash"}))
+		})
+
+		It("removes unset attributes", func() {
+			entry := slapd.UserEntry(user, db, "", nil)
+			for _, name := range []string{"givenName", "userPassword", "uidNumber", "homeDirectory", "loginShell"} {
+				Expect(entry.Attributes).To(ContainElement(slapd.Attribute{Name: name}))
+			}
+		})
+
+		It("uses the uid as the RDN", func() {
+			user.Spec.UID = "jane"
+			Expect(slapd.UserEntry(user, db, "", nil).DN).To(Equal("uid=jane,ou=people,dc=example,dc=com"))
+		})
+
+		It("adds the posixAccount attributes", func() {
+			user.Spec.Posix = &v1alpha1.PosixAccountSpec{LoginShell: "/bin/zsh"}
+			user.Status.UIDNumber = ptr.To(int64(10001))
+			user.Status.GIDNumber = ptr.To(int64(10000))
+			entry := slapd.UserEntry(user, db, "", nil)
+			Expect(entry.Get("objectClass")).To(Equal([]string{"inetOrgPerson", "posixAccount"}))
+			Expect(entry.Get("uidNumber")).To(Equal([]string{"10001"}))
+			Expect(entry.Get("gidNumber")).To(Equal([]string{"10000"}))
+			Expect(entry.Get("homeDirectory")).To(Equal([]string{"/home/jdoe"}))
+			Expect(entry.Get("loginShell")).To(Equal([]string{"/bin/zsh"}))
+		})
+	})
+
+	Context("group entry", func() {
+		BeforeEach(func() {
+			user.Status.DN = "uid=jdoe,ou=people,dc=example,dc=com"
+		})
+
+		It("is a groupOfNames below ou=groups", func() {
+			entry := slapd.GroupEntry(group, db, []*v1alpha1.LdapUser{user})
+			Expect(entry.DN).To(Equal("cn=developers,ou=groups,dc=example,dc=com"))
+			Expect(entry.Get("objectClass")).To(Equal([]string{"groupOfNames"}))
+			Expect(entry.Get("member")).To(Equal([]string{"uid=jdoe,ou=people,dc=example,dc=com"}))
+		})
+
+		It("holds the empty DN without members", func() {
+			Expect(slapd.GroupEntry(group, db, nil).Get("member")).To(Equal([]string{""}))
+		})
+
+		It("is a posixGroup listing member uids", func() {
+			group.Spec.Posix = &v1alpha1.PosixGroupSpec{}
+			group.Status.GIDNumber = ptr.To(int64(10000))
+			entry := slapd.GroupEntry(group, db, []*v1alpha1.LdapUser{user})
+			Expect(entry.Get("objectClass")).To(Equal([]string{"posixGroup"}))
+			Expect(entry.Get("gidNumber")).To(Equal([]string{"10000"}))
+			Expect(entry.Get("memberUid")).To(Equal([]string{"jdoe"}))
+			Expect(entry.Get("member")).To(BeNil())
+		})
+	})
+})
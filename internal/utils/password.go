@@ -2,7 +2,10 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
 	"encoding/base64"
+	"strings"
 )
 
 func GenerateRandonPassword(length int) ([]byte, error) {
@@ -26,3 +29,37 @@ func randomBytes(length int) ([]byte, error) {
 
 	return bytes, nil
 }
+
+// HashPassword returns a salted SHA1 hash of password in the {SSHA} format understood by slapd
+func HashPassword(password []byte) (string, error) {
+	salt, err := randomBytes(8)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha1.New()
+	hash.Write(password)
+	hash.Write(salt)
+
+	return "{SSHA}" + base64.StdEncoding.EncodeToString(append(hash.Sum(nil), salt...)), nil
+}
+
+// VerifyPassword returns true if hash is an {SSHA} hash of password
+func VerifyPassword(hash string, password []byte) bool {
+	encoded, found := strings.CutPrefix(hash, "{SSHA}")
+	if !found {
+		return false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(decoded) <= sha1.Size {
+		return false
+	}
+	digest, salt := decoded[:sha1.Size], decoded[sha1.Size:]
+
+	expected := sha1.New()
+	expected.Write(password)
+	expected.Write(salt)
+
+	return subtle.ConstantTimeCompare(expected.Sum(nil), digest) == 1
+}